@@ -0,0 +1,302 @@
+// Package main is a command line equivalent of the NIST STS assess program: it runs the
+// SP 800-22 test suite on a file or stdin and exits non-zero if the sequence fails.
+//
+// A single sequence fails if the Sidak-corrected minimum of its test p-values is below
+// Alpha, so random data fails with probability close to Alpha rather than in about one
+// of seven runs, as it would if every one of the 15 tests had to pass.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/bitstream"
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/generators"
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/nist"
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/sts"
+)
+
+// Exit codes
+const (
+	exitPassed = 0
+	// exitFailed means the combined p-value of the tests (or, in multi-sequence mode, one
+	// proportion or uniformity check) failed.
+	exitFailed = 1
+	// exitError means invalid flags or input, or that the suite could not run.
+	exitError = 2
+)
+
+// defaultGeneratorBits is the sequence length of -generator without -bits.
+const defaultGeneratorBits = 1_000_000
+
+// options holds the parsed command line.
+type options struct {
+	input string
+	// generator replaces the input with the output of a built-in STS generator if not empty.
+	generator string
+	encoding  bitstream.Encoding
+	bits      int
+	sequences int
+	format    string
+	// experimentsDir receives the STS experiments/AlgorithmTesting tree if not empty.
+	experimentsDir string
+	cfg            nist.SuiteConfig
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
+
+// run executes the command and returns its exit code.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	opts, err := parseFlags(args, stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return exitPassed
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "nist-sts: %v\n", err)
+		return exitError
+	}
+
+	passed, err := execute(ctx, opts, stdin, stdout)
+	if err != nil {
+		fmt.Fprintf(stderr, "nist-sts: %v\n", err)
+		return exitError
+	}
+	if !passed {
+		return exitFailed
+	}
+	return exitPassed
+}
+
+func parseFlags(args []string, stderr io.Writer) (*options, error) {
+	fs := flag.NewFlagSet("nist-sts", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: nist-sts [flags] [file]\n"+
+			"       nist-sts -generator name [flags]\n\n"+
+			"Runs the NIST SP 800-22 test suite on file (or stdin if file is omitted or \"-\"), or on\n"+
+			"the output of a NIST STS reference generator.\n"+
+			"Exit status: 0 if the sequence passed, 1 if it failed, 2 on errors. A single sequence\n"+
+			"fails if the Sidak-corrected minimum p-value of the tests is below 0.01; with\n"+
+			"-sequences, if a proportion or uniformity check fails.\n\nFlags:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(fs.Output(), "\nGenerators:\n")
+		for _, name := range generators.Names() {
+			fmt.Fprintf(fs.Output(), "  %-15s %s\n", name, generators.Description(name))
+		}
+	}
+
+	var (
+		opts     options
+		encoding string
+		bitOrder string
+	)
+	fs.StringVar(&encoding, "encoding", "raw", "input encoding: raw (alias binary), ascii, hex or base64")
+	fs.StringVar(&bitOrder, "bit-order", "msb", "bit order within each input byte: msb or lsb")
+	fs.StringVar(&opts.generator, "generator", "", "test a built-in NIST STS generator instead of input (see Generators below)")
+	fs.IntVar(&opts.bits, "bits", 0, fmt.Sprintf("sequence length in bits (default: all input, divided by -sequences; %d with -generator)", defaultGeneratorBits))
+	fs.IntVar(&opts.sequences, "sequences", 1, "number of consecutive sequences to test (multi-sequence analysis if > 1)")
+	fs.StringVar(&opts.format, "format", "table", "output format: table, json or report (NIST finalAnalysisReport.txt)")
+	fs.StringVar(&opts.experimentsDir, "experiments-dir", "", "also write the NIST STS experiments/AlgorithmTesting tree (<Test>/results.txt, stats.txt, finalAnalysisReport.txt) to this directory")
+	fs.IntVar(&opts.cfg.BlockFrequencyBlockLength, "block-frequency-m", 0, "Block Frequency block length M (default 128)")
+	fs.IntVar(&opts.cfg.NonOverlappingTemplateLength, "non-overlapping-m", 0, "Non-overlapping Template length m (default 9)")
+	fs.IntVar(&opts.cfg.NonOverlappingTemplateBlocks, "non-overlapping-n", 0, "Non-overlapping Template number of blocks N (default 8)")
+	fs.IntVar(&opts.cfg.OverlappingTemplateLength, "overlapping-m", 0, "Overlapping Template length m (default 9)")
+	fs.IntVar(&opts.cfg.ApproximateEntropyBlockLength, "approximate-entropy-m", 0, "Approximate Entropy block length m (default 10)")
+	fs.IntVar(&opts.cfg.SerialBlockLength, "serial-m", 0, "Serial block length m (default 16)")
+	fs.IntVar(&opts.cfg.LinearComplexityBlockLength, "linear-complexity-m", 0, "Linear Complexity block length M (default 500)")
+	fs.IntVar(&opts.cfg.BinaryMatrixRows, "matrix-rows", 0, "Binary Matrix Rank matrix rows M (default 32)")
+	fs.IntVar(&opts.cfg.BinaryMatrixColumns, "matrix-columns", 0, "Binary Matrix Rank matrix columns Q (default 32)")
+	fs.IntVar(&opts.cfg.Workers, "workers", 0, "maximum number of tests run concurrently (default GOMAXPROCS)")
+	fs.IntVar(&opts.cfg.MaxBits, "max-bits", 0, fmt.Sprintf("maximum sequence length (default %d)", nist.MaxBits))
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		opts.input = fs.Arg(0)
+	default:
+		return nil, fmt.Errorf("expected at most one input file, got %d", fs.NArg())
+	}
+
+	var err error
+	if opts.encoding, err = bitstream.ParseEncoding(encoding); err != nil {
+		return nil, err
+	}
+	switch bitOrder {
+	case "msb":
+		opts.cfg.BitOrder = nist.MSBFirst
+	case "lsb":
+		opts.cfg.BitOrder = nist.LSBFirst
+	default:
+		return nil, fmt.Errorf("unknown bit order %q: want msb or lsb", bitOrder)
+	}
+	if opts.encoding == bitstream.ASCIIBits && opts.cfg.BitOrder == nist.LSBFirst {
+		return nil, errors.New("-bit-order lsb does not apply to ascii input")
+	}
+	if opts.generator != "" {
+		if _, err := generators.New(opts.generator); err != nil {
+			return nil, err
+		}
+		if opts.input != "" {
+			return nil, errors.New("-generator does not read an input file")
+		}
+		if opts.cfg.BitOrder == nist.LSBFirst {
+			return nil, errors.New("-bit-order lsb does not apply to generated sequences")
+		}
+		if opts.bits == 0 {
+			opts.bits = defaultGeneratorBits
+		}
+	}
+	switch opts.format {
+	case "table", "json", "report":
+	default:
+		return nil, fmt.Errorf("unknown format %q: want table, json or report", opts.format)
+	}
+	if opts.bits < 0 {
+		return nil, fmt.Errorf("-bits must not be negative, got %d", opts.bits)
+	}
+	if opts.sequences < 1 {
+		return nil, fmt.Errorf("-sequences must be positive, got %d", opts.sequences)
+	}
+
+	return &opts, nil
+}
+
+// execute runs the suite as selected by opts and writes the results to stdout.
+// It reports whether the sequence passed.
+func execute(ctx context.Context, opts *options, stdin io.Reader, stdout io.Writer) (bool, error) {
+	data, numBits, name, err := loadInput(ctx, opts, stdin)
+	if err != nil {
+		return false, err
+	}
+
+	// The STS final analysis report always describes a set of sequences
+	if opts.sequences > 1 || opts.format == "report" || opts.experimentsDir != "" {
+		return runMultiSequence(ctx, opts, name, data, numBits, stdout)
+	}
+
+	n := numBits
+	if opts.bits > 0 {
+		if opts.bits > numBits {
+			return false, fmt.Errorf("input has %d bits, fewer than -bits %d", numBits, opts.bits)
+		}
+		n = opts.bits
+	}
+	seq, err := nist.NewBitSequenceOrder(data, n, opts.cfg.BitOrder)
+	if err != nil {
+		return false, err
+	}
+	results, err := nist.RunAllTestsSequence(ctx, seq, opts.cfg)
+	if err != nil {
+		return false, err
+	}
+
+	combined, _ := nist.CombinedPValue(results)
+	passed := combined >= nist.Alpha
+	if opts.format == "json" {
+		return passed, writeResultsJSON(stdout, n, opts.cfg, passed, results)
+	}
+	return passed, writeResultsTable(stdout, n, passed, results)
+}
+
+func runMultiSequence(ctx context.Context, opts *options, name string, data []byte, numBits int, stdout io.Writer) (bool, error) {
+	seqLen := opts.bits
+	if seqLen == 0 {
+		// Whole bytes only; the engine splits the input at byte boundaries
+		seqLen = (numBits / opts.sequences) &^ 7
+	}
+	if seqLen%8 != 0 {
+		return false, fmt.Errorf("-bits must be a multiple of 8 for multi-sequence analysis, got %d", seqLen)
+	}
+	if seqLen*opts.sequences > numBits {
+		return false, fmt.Errorf("input has %d bits, fewer than %d sequences of %d bits", numBits, opts.sequences, seqLen)
+	}
+
+	var onSequence nist.SequenceFunc
+	var experiment *sts.ExperimentWriter
+	if opts.experimentsDir != "" {
+		w, err := sts.NewExperimentWriter(opts.experimentsDir)
+		if err != nil {
+			return false, err
+		}
+		defer w.Close()
+		experiment, onSequence = w, w.AddSequence
+	}
+
+	report, err := nist.RunMultiSequenceFunc(ctx, data, seqLen, opts.sequences, opts.cfg, onSequence)
+	if err != nil {
+		return false, err
+	}
+	if experiment != nil {
+		if err := experiment.Finish(name, report); err != nil {
+			return false, err
+		}
+	}
+
+	passed := true
+	for _, a := range report.Results {
+		if analysisFailed(a) {
+			passed = false
+		}
+	}
+	switch opts.format {
+	case "report":
+		return passed, writeReport(stdout, name, report)
+	case "json":
+		return passed, writeReportJSON(stdout, passed, report)
+	default:
+		return passed, writeReportTable(stdout, report)
+	}
+}
+
+// loadInput returns the decoded input bits, or the output of the selected generator, and
+// a name for reports.
+func loadInput(ctx context.Context, opts *options, stdin io.Reader) ([]byte, int, string, error) {
+	if opts.generator != "" {
+		data, err := generators.Generate(ctx, opts.generator, opts.bits, opts.sequences)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		return data, opts.bits * opts.sequences, opts.generator, nil
+	}
+
+	raw, name, err := readInput(opts.input, stdin)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	data, numBits, err := bitstream.Decode(raw, opts.encoding)
+	if err != nil {
+		return nil, 0, "", fmt.Errorf("cannot decode %s input: %w", opts.encoding, err)
+	}
+	return data, numBits, name, nil
+}
+
+// readInput reads the named file, or stdin if path is empty or "-", and returns its
+// content and a name for reports.
+func readInput(path string, stdin io.Reader) ([]byte, string, error) {
+	if path == "" || path == "-" {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, "", fmt.Errorf("read stdin: %w", err)
+		}
+		return data, "<stdin>", nil
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // reading the user-selected input is the purpose
+	if err != nil {
+		return nil, "", err
+	}
+	return data, path, nil
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/nist"
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/testrand"
+)
+
+// passingData returns MinBits of LCG output that passes all 15 tests.
+func passingData() []byte {
+	return testrand.Bytes(nist.MinBits/8, 4)
+}
+
+func writeFile(t *testing.T, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "data.bin")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	return path
+}
+
+// runCLI runs the command and returns its exit code, stdout and stderr.
+func runCLI(stdin []byte, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), args, bytes.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRunTable(t *testing.T) {
+	path := writeFile(t, passingData())
+
+	code, out, stderr := runCLI(nil, path)
+	if code != exitPassed {
+		t.Fatalf("expected exit %d, got %d: %s%s", exitPassed, code, out, stderr)
+	}
+	if !strings.Contains(out, "non_overlapping_template") || !strings.Contains(out, "387840 bits: 15 passed, 0 failed") {
+		t.Errorf("unexpected table:\n%s", out)
+	}
+
+	// The same bits as hex on stdin
+	code, hexOut, _ := runCLI([]byte(hex.EncodeToString(passingData())+"\n"), "-encoding", "hex", "-")
+	if code != exitPassed || hexOut != out {
+		t.Errorf("hex input on stdin differs (exit %d):\n%s", code, hexOut)
+	}
+}
+
+func TestRunJSON(t *testing.T) {
+	data := passingData()
+	var ascii strings.Builder
+	for _, b := range data {
+		fmt.Fprintf(&ascii, "%08b", b)
+	}
+	// Trailing bits beyond -bits are ignored
+	ascii.WriteString("101\n")
+	path := writeFile(t, []byte(ascii.String()))
+
+	code, out, stderr := runCLI(nil, "-encoding", "ascii", "-bits", fmt.Sprint(nist.MinBits), "-serial-m", "12", "-matrix-rows", "16", "-format", "json", path)
+	if code != exitPassed {
+		t.Fatalf("expected exit %d, got %d: %s", exitPassed, code, stderr)
+	}
+
+	var got jsonResults
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if !got.Passed || got.CombinedPValue < nist.Alpha || got.Bits != nist.MinBits || len(got.Results) != 15 {
+		t.Fatalf("unexpected results: %+v", got)
+	}
+	if got.Config.SerialBlockLength != 12 || got.Config.BlockFrequencyBlockLength != 128 || got.Config.BitOrder != "msb-first" ||
+		got.Config.BinaryMatrixRows != 16 || got.Config.BinaryMatrixColumns != 32 {
+		t.Errorf("unexpected config: %+v", got.Config)
+	}
+	if r := got.Results[7]; r.Name != "non_overlapping_template" || len(r.SubResults) != 148 {
+		t.Errorf("unexpected template result: %+v", r)
+	}
+}
+
+func TestRunFailure(t *testing.T) {
+	code, out, _ := runCLI(make([]byte, nist.MinBits/8))
+	if code != exitFailed {
+		t.Fatalf("expected exit %d for all-zero input, got %d", exitFailed, code)
+	}
+	if !strings.Contains(out, "frequency_monobit           failed") {
+		t.Errorf("unexpected table:\n%s", out)
+	}
+}
+
+func TestRunMultiSequence(t *testing.T) {
+	path := writeFile(t, passingData())
+
+	code, out, stderr := runCLI(nil, "-format", "report", path)
+	if code != exitPassed {
+		t.Fatalf("expected exit %d, got %d: %s", exitPassed, code, stderr)
+	}
+	for _, want := range []string{"generator is <" + path + ">", "      1/1       Frequency\n", "NonOverlappingTemplate"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report lacks %q:\n%s", want, out)
+		}
+	}
+
+	// Two all-zero sequences fail every proportion check
+	zeros := make([]byte, 2*nist.MinBits/8)
+	code, out, _ = runCLI(zeros, "-sequences", "2", "-format", "json")
+	if code != exitFailed {
+		t.Fatalf("expected exit %d, got %d", exitFailed, code)
+	}
+	var got jsonReport
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if got.Passed || got.NumSequences != 2 || got.SequenceLengthBits != nist.MinBits || got.Results[0].PassedSequences != 0 {
+		t.Errorf("unexpected report: %+v", got)
+	}
+
+	code, out, _ = runCLI(zeros, "-sequences", "2")
+	if code != exitFailed || !strings.Contains(out, "2 sequences of 387840 bits") {
+		t.Errorf("unexpected table (exit %d):\n%s", code, out)
+	}
+}
+
+func TestRunExperimentsDir(t *testing.T) {
+	path := writeFile(t, passingData())
+	dir := filepath.Join(t.TempDir(), "AlgorithmTesting")
+
+	code, _, stderr := runCLI(nil, "-experiments-dir", dir, path)
+	if code != exitPassed {
+		t.Fatalf("expected exit %d, got %d: %s", exitPassed, code, stderr)
+	}
+	results, err := os.ReadFile(filepath.Join(dir, "Frequency", "results.txt")) //nolint:gosec // test file below t.TempDir
+	if err != nil {
+		t.Fatalf("read results: %v", err)
+	}
+	if strings.Count(string(results), "\n") != 1 {
+		t.Errorf("expected one p-value, got %q", results)
+	}
+	for _, file := range []string{"finalAnalysisReport.txt", "NonOverlappingTemplate/stats.txt", "RandomExcursionsVariant/results.txt"} {
+		if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+			t.Errorf("missing %s: %v", file, err)
+		}
+	}
+
+	// The directory cannot be created below a file
+	code, _, stderr = runCLI(nil, "-experiments-dir", filepath.Join(path, "sub"), path)
+	if code != exitError || stderr == "" {
+		t.Errorf("expected exit %d with an error, got %d: %s", exitError, code, stderr)
+	}
+}
+
+func TestRunGenerator(t *testing.T) {
+	code, out, stderr := runCLI(nil, "-generator", "xor", "-bits", fmt.Sprint(nist.MinBits))
+	if code != exitFailed {
+		t.Fatalf("expected exit %d, got %d: %s", exitFailed, code, stderr)
+	}
+	// The XOR generator is a 127-bit LFSR
+	if !strings.Contains(out, "linear_complexity           failed  0.000000") {
+		t.Errorf("unexpected table:\n%s", out)
+	}
+
+	code, out, stderr = runCLI(nil, "-generator", "g-sha1", "-bits", fmt.Sprint(nist.MinBits), "-sequences", "2", "-format", "report")
+	if code != exitPassed {
+		t.Fatalf("expected exit %d, got %d: %s%s", exitPassed, code, out, stderr)
+	}
+	if !strings.Contains(out, "generator is <g-sha1>") || !strings.Contains(out, "      2/2       Frequency\n") {
+		t.Errorf("unexpected report:\n%s", out)
+	}
+}
+
+func TestRunGoodGenerator(t *testing.T) {
+	// A good generator may fail a single test by chance, as BBS does here; the sequence
+	// still passes on the corrected p-value.
+	code, out, stderr := runCLI(nil, "-generator", "bbs")
+	if code != exitPassed {
+		t.Fatalf("expected exit %d for 1,000,000 bits of BBS output, got %d: %s%s", exitPassed, code, out, stderr)
+	}
+	if !strings.Contains(out, "14 passed, 1 failed") || !strings.Contains(out, "sequence passed") {
+		t.Errorf("unexpected table:\n%s", out)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	data := passingData()
+	tests := []struct {
+		name  string
+		stdin []byte
+		args  []string
+		want  string
+	}{
+		{"unknown flag", nil, []string{"-foo"}, "flag provided but not defined"},
+		{"unknown encoding", nil, []string{"-encoding", "utf8"}, "unknown encoding"},
+		{"unknown bit order", nil, []string{"-bit-order", "little"}, "unknown bit order"},
+		{"lsb ascii", nil, []string{"-encoding", "ascii", "-bit-order", "lsb"}, "does not apply"},
+		{"unknown format", nil, []string{"-format", "xml"}, "unknown format"},
+		{"negative bits", nil, []string{"-bits", "-1"}, "must not be negative"},
+		{"no sequences", nil, []string{"-sequences", "0"}, "must be positive"},
+		{"two files", nil, []string{"a", "b"}, "at most one input file"},
+		{"missing file", nil, []string{filepath.Join(t.TempDir(), "missing")}, "no such file"},
+		{"invalid hex", []byte("0g"), []string{"-encoding", "hex"}, "'g' at offset 1"},
+		{"too short", data[:100], nil, "insufficient bits"},
+		{"bits exceed input", data, []string{"-bits", fmt.Sprint(len(data)*8 + 1)}, "fewer than -bits"},
+		{"unaligned sequences", data, []string{"-sequences", "2", "-bits", "1001"}, "multiple of 8"},
+		{"too few sequences", data, []string{"-sequences", "2", "-bits", fmt.Sprint(nist.MinBits)}, "fewer than 2 sequences"},
+		{"invalid parameter", data, []string{"-serial-m", "1"}, "serial block length"},
+		{"unknown generator", nil, []string{"-generator", "rand"}, "unknown generator"},
+		{"generator and file", nil, []string{"-generator", "lcg", "data.bin"}, "does not read an input file"},
+		{"generator lsb", nil, []string{"-generator", "lcg", "-bit-order", "lsb"}, "does not apply to generated"},
+		{"generator unaligned", nil, []string{"-generator", "lcg", "-bits", "1001", "-sequences", "2"}, "multiple of 8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := runCLI(tt.stdin, tt.args...)
+			if code != exitError || !strings.Contains(stderr, tt.want) {
+				t.Fatalf("expected exit %d with %q, got %d: %s", exitError, tt.want, code, stderr)
+			}
+		})
+	}
+
+	if code, _, stderr := runCLI(nil, "-h"); code != exitPassed || !strings.Contains(stderr, "Usage: nist-sts") {
+		t.Errorf("expected usage and exit %d, got %d: %s", exitPassed, code, stderr)
+	}
+}
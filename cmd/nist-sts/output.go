@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/nist"
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/sts"
+)
+
+// jsonConfig lists the effective test parameters under their gRPC API names.
+type jsonConfig struct {
+	BlockFrequencyBlockLength         int    `json:"block_frequency_block_length"`
+	NonOverlappingTemplateBlockLength int    `json:"non_overlapping_template_block_length"`
+	NonOverlappingTemplateNumBlocks   int    `json:"non_overlapping_template_num_blocks"`
+	OverlappingTemplateBlockLength    int    `json:"overlapping_template_block_length"`
+	ApproximateEntropyBlockLength     int    `json:"approximate_entropy_block_length"`
+	SerialBlockLength                 int    `json:"serial_block_length"`
+	LinearComplexitySequenceLength    int    `json:"linear_complexity_sequence_length"`
+	BinaryMatrixRows                  int    `json:"binary_matrix_rows"`
+	BinaryMatrixColumns               int    `json:"binary_matrix_columns"`
+	BitOrder                          string `json:"bit_order"`
+}
+
+func newJSONConfig(c nist.SuiteConfig) jsonConfig {
+	c = c.WithDefaults()
+	return jsonConfig{
+		BlockFrequencyBlockLength:         c.BlockFrequencyBlockLength,
+		NonOverlappingTemplateBlockLength: c.NonOverlappingTemplateLength,
+		NonOverlappingTemplateNumBlocks:   c.NonOverlappingTemplateBlocks,
+		OverlappingTemplateBlockLength:    c.OverlappingTemplateLength,
+		ApproximateEntropyBlockLength:     c.ApproximateEntropyBlockLength,
+		SerialBlockLength:                 c.SerialBlockLength,
+		LinearComplexitySequenceLength:    c.LinearComplexityBlockLength,
+		BinaryMatrixRows:                  c.BinaryMatrixRows,
+		BinaryMatrixColumns:               c.BinaryMatrixColumns,
+		BitOrder:                          c.BitOrder.String(),
+	}
+}
+
+type jsonSubResult struct {
+	Name   string  `json:"name"`
+	PValue float64 `json:"p_value"`
+	Passed bool    `json:"passed"`
+}
+
+type jsonResult struct {
+	Name       string          `json:"name"`
+	Status     string          `json:"status"`
+	PValue     float64         `json:"p_value"`
+	Warning    string          `json:"warning,omitempty"`
+	SubResults []jsonSubResult `json:"sub_results,omitempty"`
+}
+
+type jsonResults struct {
+	Bits           int          `json:"bits"`
+	Passed         bool         `json:"passed"`
+	CombinedPValue float64      `json:"combined_p_value"`
+	Config         jsonConfig   `json:"config"`
+	Results        []jsonResult `json:"results"`
+}
+
+type jsonAnalysis struct {
+	Name             string  `json:"name"`
+	Histogram        []int   `json:"histogram"`
+	PValueUniformity float64 `json:"p_value_uniformity"`
+	UniformityPassed bool    `json:"uniformity_passed"`
+	PassedSequences  int     `json:"passed_sequences"`
+	SampleSize       int     `json:"sample_size"`
+	Proportion       float64 `json:"proportion"`
+	ProportionPassed bool    `json:"proportion_passed"`
+}
+
+type jsonReport struct {
+	SequenceLengthBits int            `json:"sequence_length_bits"`
+	NumSequences       int            `json:"num_sequences"`
+	Passed             bool           `json:"passed"`
+	Config             jsonConfig     `json:"config"`
+	Results            []jsonAnalysis `json:"results"`
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func writeResultsJSON(w io.Writer, bits int, cfg nist.SuiteConfig, passed bool, results []nist.TestResult) error {
+	combined, _ := nist.CombinedPValue(results)
+	out := jsonResults{Bits: bits, Passed: passed, CombinedPValue: combined, Config: newJSONConfig(cfg), Results: make([]jsonResult, len(results))}
+	for i, r := range results {
+		out.Results[i] = jsonResult{Name: r.Name, Status: r.Status.String(), PValue: r.PValue, Warning: r.Warning}
+		for _, sub := range r.SubResults {
+			out.Results[i].SubResults = append(out.Results[i].SubResults, jsonSubResult{Name: sub.Name, PValue: sub.PValue, Passed: sub.Passed})
+		}
+	}
+	return writeJSON(w, out)
+}
+
+func writeResultsTable(w io.Writer, bits int, passed bool, results []nist.TestResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TEST\tSTATUS\tP-VALUE\tNOTE")
+
+	counts := make(map[nist.Status]int)
+	for _, r := range results {
+		counts[r.Status]++
+		pValue := "-"
+		if r.Status.Completed() {
+			pValue = fmt.Sprintf("%.6f", r.PValue)
+		}
+		note := r.Warning
+		if note == "" && r.MultiValued() {
+			note = fmt.Sprintf("Sidak-corrected minimum of %d statistics", len(r.SubResults))
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Name, r.Status, pValue, note)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	combined, k := nist.CombinedPValue(results)
+	verdict := "passed"
+	if !passed {
+		verdict = "failed"
+	}
+	_, err := fmt.Fprintf(w, "\n%d bits: %d passed, %d failed, %d not applicable, %d errors\n"+
+		"Sidak-corrected minimum p-value of %d tests: %.6f, sequence %s\n", bits,
+		counts[nist.StatusPassed], counts[nist.StatusFailed], counts[nist.StatusNotApplicable], counts[nist.StatusError],
+		k, combined, verdict)
+	return err
+}
+
+// analysisFailed reports whether a statistic fails the proportion check or, for samples
+// large enough to evaluate it, the uniformity check.
+func analysisFailed(a nist.SequenceAnalysis) bool {
+	if a.SampleSize == 0 {
+		return false
+	}
+	return !a.ProportionPassed || (a.SampleSize >= sts.MinUniformitySampleSize && !a.UniformityPassed)
+}
+
+func writeReport(w io.Writer, name string, report *nist.MultiSequenceReport) error {
+	return sts.WriteFinalAnalysisReport(w, name, report)
+}
+
+func writeReportJSON(w io.Writer, passed bool, report *nist.MultiSequenceReport) error {
+	out := jsonReport{
+		SequenceLengthBits: report.SequenceLength,
+		NumSequences:       report.NumSequences,
+		Passed:             passed,
+		Config:             newJSONConfig(report.Config),
+		Results:            make([]jsonAnalysis, len(report.Results)),
+	}
+	for i, a := range report.Results {
+		out.Results[i] = jsonAnalysis{
+			Name:             a.Name,
+			Histogram:        a.Histogram[:],
+			PValueUniformity: a.PValueT,
+			UniformityPassed: a.UniformityPassed,
+			PassedSequences:  a.PassedSequences,
+			SampleSize:       a.SampleSize,
+			Proportion:       a.Proportion,
+			ProportionPassed: a.ProportionPassed,
+		}
+	}
+	return writeJSON(w, out)
+}
+
+func writeReportTable(w io.Writer, report *nist.MultiSequenceReport) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STATISTIC\tP-VALUE_T\tPROPORTION\tRESULT")
+
+	failed := 0
+	for _, a := range report.Results {
+		uniformity := "-"
+		if a.SampleSize >= sts.MinUniformitySampleSize {
+			uniformity = fmt.Sprintf("%.6f", a.PValueT)
+		}
+		result := "passed"
+		switch {
+		case a.SampleSize == 0:
+			result = "not_applicable"
+		case analysisFailed(a):
+			result = "failed"
+			failed++
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d/%d\t%s\n", a.Name, uniformity, a.PassedSequences, a.SampleSize, result)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "\n%d sequences of %d bits: %d of %d statistics failed\n",
+		report.NumSequences, report.SequenceLength, failed, len(report.Results))
+	return err
+}
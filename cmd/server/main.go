@@ -51,6 +51,10 @@ func run(ctx context.Context) error {
 		Int("metrics_port", cfg.MetricsPort).
 		Str("log_level", cfg.LogLevel).
 		Bool("auth_enabled", cfg.AuthEnabled).
+		Int("test_concurrency", cfg.TestConcurrency).
+		Dur("max_execution_time", cfg.MaxExecutionTime).
+		Int("max_bits", cfg.MaxBits).
+		Int("stream_max_bits", cfg.StreamMaxBits).
 		Msg("Starting NIST Statistical Test Service")
 
 	// Start Prometheus metrics server
@@ -67,12 +71,12 @@ func run(ctx context.Context) error {
 		return fmt.Errorf("failed to create gRPC listener: %w", err)
 	}
 
-	unaryInterceptors, err := buildUnaryInterceptors(cfg)
+	interceptors, err := buildInterceptors(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to configure gRPC server: %w", err)
 	}
 
-	grpcServer, err := runGRPCServer(cfg, unaryInterceptors)
+	grpcServer, err := runGRPCServer(cfg, interceptors)
 	if err != nil {
 		return fmt.Errorf("failed to create gRPC server: %w", err)
 	}
@@ -172,8 +176,8 @@ func startMetricsServer(ln net.Listener) *http.Server {
 }
 
 // runGRPCServer creates and configures the gRPC server
-func runGRPCServer(cfg *config.Config, unaryInterceptors []grpc.UnaryServerInterceptor) (*grpc.Server, error) {
-	serverOpts, err := buildGRPCServerOptions(cfg, unaryInterceptors)
+func runGRPCServer(cfg *config.Config, interceptors serverInterceptors) (*grpc.Server, error) {
+	serverOpts, err := buildGRPCServerOptions(cfg, interceptors)
 	if err != nil {
 		return nil, err
 	}
@@ -181,7 +185,12 @@ func runGRPCServer(cfg *config.Config, unaryInterceptors []grpc.UnaryServerInter
 	grpcServer := grpc.NewServer(serverOpts...)
 
 	// Register NIST SP 800-22 service
-	nistServer := service.NewServer()
+	nistServer := service.NewServerWithOptions(service.Options{
+		TestConcurrency:  cfg.TestConcurrency,
+		MaxExecutionTime: cfg.MaxExecutionTime,
+		MaxBits:          cfg.MaxBits,
+		StreamMaxBits:    cfg.StreamMaxBits,
+	})
 	pb.RegisterSp80022TestServiceServer(grpcServer, nistServer)
 
 	// Register health check service
@@ -195,10 +204,22 @@ func runGRPCServer(cfg *config.Config, unaryInterceptors []grpc.UnaryServerInter
 	return grpcServer, nil
 }
 
-func buildUnaryInterceptors(cfg *config.Config) ([]grpc.UnaryServerInterceptor, error) {
-	interceptors := []grpc.UnaryServerInterceptor{
-		middleware.UnaryRequestIDInterceptor(),
-		loggingInterceptor,
+// serverInterceptors holds the unary and stream interceptor chains of the gRPC server
+type serverInterceptors struct {
+	unary  []grpc.UnaryServerInterceptor
+	stream []grpc.StreamServerInterceptor
+}
+
+func buildInterceptors(cfg *config.Config) (serverInterceptors, error) {
+	interceptors := serverInterceptors{
+		unary: []grpc.UnaryServerInterceptor{
+			middleware.UnaryRequestIDInterceptor(),
+			loggingInterceptor,
+		},
+		stream: []grpc.StreamServerInterceptor{
+			middleware.StreamRequestIDInterceptor(),
+			streamLoggingInterceptor,
+		},
 	}
 
 	if !cfg.AuthEnabled {
@@ -212,7 +233,7 @@ func buildUnaryInterceptors(cfg *config.Config) ([]grpc.UnaryServerInterceptor,
 
 	validator, err := validatorBuilder.Build()
 	if err != nil {
-		return nil, fmt.Errorf("failed to build auth validator: %w", err)
+		return serverInterceptors{}, fmt.Errorf("failed to build auth validator: %w", err)
 	}
 
 	log.Info().
@@ -221,20 +242,22 @@ func buildUnaryInterceptors(cfg *config.Config) ([]grpc.UnaryServerInterceptor,
 		Str("jwks_url", cfg.AuthJWKSURL).
 		Msg("gRPC authentication enabled")
 
-	authInterceptor := grpcserver.UnaryServerInterceptor(
-		validator,
-		grpcserver.WithExemptMethods(
-			"/grpc.health.v1.Health/Check",
-			"/grpc.health.v1.Health/Watch",
-		),
+	exempt := grpcserver.WithExemptMethods(
+		"/grpc.health.v1.Health/Check",
+		"/grpc.health.v1.Health/Watch",
 	)
 
-	return append(interceptors, authInterceptor), nil
+	interceptors.unary = append(interceptors.unary, grpcserver.UnaryServerInterceptor(validator, exempt))
+	interceptors.stream = append(interceptors.stream, grpcserver.StreamServerInterceptor(validator, exempt))
+
+	return interceptors, nil
 }
 
-func buildGRPCServerOptions(cfg *config.Config, unaryInterceptors []grpc.UnaryServerInterceptor) ([]grpc.ServerOption, error) {
+func buildGRPCServerOptions(cfg *config.Config, interceptors serverInterceptors) ([]grpc.ServerOption, error) {
 	opts := []grpc.ServerOption{
-		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainUnaryInterceptor(interceptors.unary...),
+		grpc.ChainStreamInterceptor(interceptors.stream...),
+		grpc.MaxRecvMsgSize(maxRecvMsgSize(cfg)),
 	}
 
 	if !cfg.TLSEnabled {
@@ -276,6 +299,19 @@ func buildGRPCServerOptions(cfg *config.Config, unaryInterceptors []grpc.UnarySe
 	return append(opts, tlsOpt), nil
 }
 
+// maxRecvMsgSize returns the gRPC message size limit, raised above the 4 MB default
+// when MAX_BITS allows larger RunTestSuite bitstreams. It is sized for ASCII_BITS, the
+// largest encoding at one byte per bit, so a bitstream of MAX_BITS bits fits in every
+// encoding unless it is padded with whitespace.
+func maxRecvMsgSize(cfg *config.Config) int {
+	const (
+		defaultMaxRecvMsgSize = 4 << 20
+		// headroom for the request config, protobuf framing and line breaks
+		overhead = 64 << 10
+	)
+	return max(defaultMaxRecvMsgSize, cfg.MaxBits+overhead)
+}
+
 func tlsVersionString(version uint16) string {
 	switch version {
 	case tls.VersionTLS13:
@@ -322,3 +358,39 @@ func loggingInterceptor(
 
 	return resp, err
 }
+
+// streamLoggingInterceptor logs all streaming gRPC calls with request ID
+func streamLoggingInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	start := time.Now()
+
+	// Get request ID from context
+	requestID := middleware.GetRequestID(ss.Context())
+
+	// Call the handler
+	err := handler(srv, ss)
+
+	// Log the call
+	duration := time.Since(start)
+
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("method", info.FullMethod).
+			Dur("duration", duration).
+			Msg("gRPC stream failed")
+	} else {
+		log.Debug().
+			Str("request_id", requestID).
+			Str("method", info.FullMethod).
+			Dur("duration", duration).
+			Msg("gRPC stream completed")
+	}
+
+	return err
+}
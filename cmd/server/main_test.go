@@ -67,6 +67,46 @@ func TestLoggingInterceptor(t *testing.T) {
 	}
 }
 
+func TestStreamLoggingInterceptor(t *testing.T) {
+	origLevel := zerolog.GlobalLevel()
+	defer zerolog.SetGlobalLevel(origLevel)
+	setupLogging("debug")
+
+	info := &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream", IsClientStream: true}
+	ss := &testServerStream{ctx: context.Background()}
+
+	if err := streamLoggingInterceptor(nil, ss, info, func(srv interface{}, stream grpc.ServerStream) error {
+		return nil
+	}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	err := streamLoggingInterceptor(nil, ss, info, func(srv interface{}, stream grpc.ServerStream) error {
+		return fmt.Errorf("handler error")
+	})
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+// testServerStream is a minimal grpc.ServerStream for interceptor tests
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context { return s.ctx }
+
+func TestMaxRecvMsgSize(t *testing.T) {
+	if got := maxRecvMsgSize(&config.Config{MaxBits: 1_000_000}); got != 4<<20 {
+		t.Errorf("expected the 4 MB default, got %d", got)
+	}
+	// ASCII_BITS takes one byte per bit
+	if got := maxRecvMsgSize(&config.Config{MaxBits: 10_000_000}); got <= 10_000_000 {
+		t.Errorf("expected room for 10 MB ASCII_BITS bitstreams, got %d", got)
+	}
+}
+
 func TestStartMetricsServer(t *testing.T) {
 	ln := mustListen(t)
 	// No defer ln.Close() here, server will close it
@@ -97,7 +137,7 @@ func TestRunGRPCServer(t *testing.T) {
 	ln := mustListen(t)
 	defer ln.Close()
 
-	interceptors, err := buildUnaryInterceptors(&config.Config{})
+	interceptors, err := buildInterceptors(&config.Config{})
 	if err != nil {
 		t.Fatalf("failed to build interceptors: %v", err)
 	}
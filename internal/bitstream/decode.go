@@ -0,0 +1,179 @@
+// Package bitstream decodes the text encodings in which bit sequences are commonly
+// exchanged, e.g. the ASCII '0'/'1' files read by the NIST STS assess binary.
+package bitstream
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// Encoding identifies how a bitstream is represented.
+type Encoding int
+
+const (
+	// Raw is the bitstream itself, eight bits per byte, most significant bit first.
+	Raw Encoding = iota
+	// ASCIIBits is one '0' or '1' character per bit.
+	ASCIIBits
+	// Hex is two hexadecimal digits per byte.
+	Hex
+	// Base64 is the standard base64 encoding with padding (RFC 4648).
+	Base64
+)
+
+// ErrInvalidInput is wrapped by all errors reporting malformed encoded input.
+var ErrInvalidInput = errors.New("invalid encoded bitstream")
+
+// String returns the name of the encoding.
+func (e Encoding) String() string {
+	switch e {
+	case Raw:
+		return "raw"
+	case ASCIIBits:
+		return "ascii"
+	case Hex:
+		return "hex"
+	case Base64:
+		return "base64"
+	default:
+		return fmt.Sprintf("Encoding(%d)", int(e))
+	}
+}
+
+// ParseEncoding returns the encoding with the given name as returned by String;
+// "binary" is accepted as an alias for raw.
+func ParseEncoding(name string) (Encoding, error) {
+	switch name {
+	case "raw", "binary":
+		return Raw, nil
+	case "ascii":
+		return ASCIIBits, nil
+	case "hex":
+		return Hex, nil
+	case "base64":
+		return Base64, nil
+	default:
+		return 0, fmt.Errorf("unknown encoding %q: want raw, ascii, hex or base64", name)
+	}
+}
+
+// Decode converts data from the encoding enc to a packed bitstream and returns it together
+// with its length in bits, which for ASCIIBits need not be a multiple of 8 (the pad bits
+// of the last byte are zero). Whitespace (space, tab, CR, LF) is ignored in all text
+// encodings; any other unexpected character is rejected with an error wrapping
+// ErrInvalidInput that gives its offset in data.
+func Decode(data []byte, enc Encoding) ([]byte, int, error) {
+	switch enc {
+	case Raw:
+		return data, len(data) * 8, nil
+	case ASCIIBits:
+		return decodeASCIIBits(data)
+	case Hex:
+		return decodeHex(data)
+	case Base64:
+		return decodeBase64(data)
+	default:
+		return nil, 0, fmt.Errorf("unsupported encoding %v", enc)
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+func invalidChar(c byte, offset int) error {
+	return fmt.Errorf("%w: unexpected character %q at offset %d", ErrInvalidInput, c, offset)
+}
+
+func decodeASCIIBits(data []byte) ([]byte, int, error) {
+	packed := make([]byte, 0, len(data)/8+1)
+	var current byte
+	n := 0
+	for i, c := range data {
+		switch {
+		case c == '0' || c == '1':
+			current |= (c - '0') << (7 - uint(n&7))
+			n++
+			if n&7 == 0 {
+				packed = append(packed, current)
+				current = 0
+			}
+		case isSpace(c):
+		default:
+			return nil, 0, invalidChar(c, i)
+		}
+	}
+	if n&7 != 0 {
+		packed = append(packed, current)
+	}
+	return packed, n, nil
+}
+
+func hexValue(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	default:
+		return 0, false
+	}
+}
+
+func decodeHex(data []byte) ([]byte, int, error) {
+	packed := make([]byte, 0, len(data)/2)
+	var current byte
+	digits := 0
+	for i, c := range data {
+		if isSpace(c) {
+			continue
+		}
+		v, ok := hexValue(c)
+		if !ok {
+			return nil, 0, invalidChar(c, i)
+		}
+		current = current<<4 | v
+		digits++
+		if digits%2 == 0 {
+			packed = append(packed, current)
+			current = 0
+		}
+	}
+	if digits%2 != 0 {
+		return nil, 0, fmt.Errorf("%w: odd number of hex digits (%d)", ErrInvalidInput, digits)
+	}
+	return packed, len(packed) * 8, nil
+}
+
+func isBase64(c byte) bool {
+	return c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '+' || c == '/' || c == '='
+}
+
+func decodeBase64(data []byte) ([]byte, int, error) {
+	// base64.StdEncoding skips CR and LF only and reports errors per 4-character quantum;
+	// check the alphabet here so that errors give the offset in the original input.
+	compact := make([]byte, 0, len(data))
+	for i, c := range data {
+		switch {
+		case isBase64(c):
+			compact = append(compact, c)
+		case isSpace(c):
+		default:
+			return nil, 0, invalidChar(c, i)
+		}
+	}
+	if len(compact)%4 != 0 {
+		return nil, 0, fmt.Errorf("%w: %d base64 characters, not a multiple of 4", ErrInvalidInput, len(compact))
+	}
+
+	packed := make([]byte, base64.StdEncoding.DecodedLen(len(compact)))
+	n, err := base64.StdEncoding.Strict().Decode(packed, compact)
+	if err != nil {
+		// Misplaced padding or non-zero pad bits
+		return nil, 0, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	return packed[:n], n * 8, nil
+}
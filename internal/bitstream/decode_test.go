@@ -0,0 +1,90 @@
+package bitstream
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name  string
+		enc   Encoding
+		input string
+		want  []byte
+		bits  int
+	}{
+		{"raw", Raw, "\xa5\x0f", []byte{0xA5, 0x0F}, 16},
+		{"ascii", ASCIIBits, "10100101 00001111", []byte{0xA5, 0x0F}, 16},
+		{"ascii with line breaks", ASCIIBits, "  1010\r\n0101\t\n", []byte{0xA5}, 8},
+		{"ascii partial byte", ASCIIBits, "1010010111", []byte{0xA5, 0xC0}, 10},
+		{"ascii empty", ASCIIBits, " \n", []byte{}, 0},
+		{"hex", Hex, "a50F", []byte{0xA5, 0x0F}, 16},
+		{"hex with whitespace", Hex, "a5 0f\n", []byte{0xA5, 0x0F}, 16},
+		{"base64", Base64, "pQ8=", []byte{0xA5, 0x0F}, 16},
+		{"base64 with line breaks", Base64, "pQ8A\r\npQ==\n", []byte{0xA5, 0x0F, 0x00, 0xA5}, 32},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, bits, err := Decode([]byte(tt.input), tt.enc)
+			if err != nil {
+				t.Fatalf("Decode failed: %v", err)
+			}
+			if !bytes.Equal(got, tt.want) || bits != tt.bits {
+				t.Errorf("expected %x (%d bits), got %x (%d bits)", tt.want, tt.bits, got, bits)
+			}
+		})
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		enc   Encoding
+		input string
+		// detail is a substring of the expected error
+		detail string
+	}{
+		{"ascii digit", ASCIIBits, "0101201", `'2' at offset 4`},
+		{"ascii comma", ASCIIBits, "01,10", `',' at offset 2`},
+		{"hex letter", Hex, "a5g0", `'g' at offset 2`},
+		{"hex prefix", Hex, "0xa5", `'x' at offset 1`},
+		{"hex odd digits", Hex, "a5 0", "odd number of hex digits"},
+		{"base64 character", Base64, "pQ\n8*", `'*' at offset 4`},
+		{"base64 url alphabet", Base64, "pQ_=", `'_' at offset 2`},
+		{"base64 missing padding", Base64, "pQ8", "not a multiple of 4"},
+		{"base64 misplaced padding", Base64, "p=Q8", "illegal base64 data"},
+		{"base64 non-zero pad bits", Base64, "pQ9=", "illegal base64 data"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Decode([]byte(tt.input), tt.enc)
+			if !errors.Is(err, ErrInvalidInput) {
+				t.Fatalf("expected ErrInvalidInput, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.detail) {
+				t.Errorf("expected %q in error, got %q", tt.detail, err)
+			}
+		})
+	}
+
+	if _, _, err := Decode(nil, Encoding(42)); err == nil || !strings.Contains(err.Error(), "Encoding(42)") {
+		t.Errorf("expected unsupported encoding error, got %v", err)
+	}
+}
+
+func TestParseEncoding(t *testing.T) {
+	for _, enc := range []Encoding{Raw, ASCIIBits, Hex, Base64} {
+		got, err := ParseEncoding(enc.String())
+		if err != nil || got != enc {
+			t.Errorf("ParseEncoding(%q) = %v, %v", enc.String(), got, err)
+		}
+	}
+	if got, err := ParseEncoding("binary"); err != nil || got != Raw {
+		t.Errorf("expected binary to alias raw, got %v, %v", got, err)
+	}
+	if _, err := ParseEncoding("utf8"); err == nil {
+		t.Error("expected error for an unknown encoding")
+	}
+}
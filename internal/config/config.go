@@ -3,9 +3,14 @@ package config
 import (
 	"crypto/tls"
 	"fmt"
+	"math"
 	"os"
+	"runtime"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/nist"
 )
 
 // Config holds all service configuration
@@ -32,6 +37,16 @@ type Config struct {
 	AuthIssuer   string
 	AuthAudience string
 	AuthJWKSURL  string
+
+	// Test engine configuration
+	TestConcurrency int
+	// MaxExecutionTime bounds the test run of a single request (0 disables the limit)
+	MaxExecutionTime time.Duration
+	// MaxBits caps the bitstream size of RunTestSuite and the sequence length of RunMultiSequenceAnalysis
+	MaxBits int
+	// StreamMaxBits caps the bitstream size accepted by RunTestSuiteStream. It defaults to
+	// nist.MaxBits like MaxBits, so the default passes the memory check wherever MaxBits does.
+	StreamMaxBits int
 }
 
 // Load reads configuration from environment variables
@@ -50,6 +65,11 @@ func Load() (*Config, error) {
 		AuthIssuer:    getEnvString("AUTH_ISSUER", ""),
 		AuthAudience:  getEnvString("AUTH_AUDIENCE", ""),
 		AuthJWKSURL:   getEnvString("AUTH_JWKS_URL", ""),
+
+		TestConcurrency:  getEnvInt("TEST_CONCURRENCY", runtime.GOMAXPROCS(0)),
+		MaxExecutionTime: getEnvDuration("MAX_EXECUTION_TIME", 5*time.Minute),
+		MaxBits:          getEnvInt("MAX_BITS", nist.MaxBits),
+		StreamMaxBits:    getEnvInt("STREAM_MAX_BITS", nist.MaxBits),
 	}
 
 	if err := cfg.Validate(); err != nil {
@@ -104,6 +124,35 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.TestConcurrency < 1 {
+		return fmt.Errorf("invalid TEST_CONCURRENCY: %d (must be >= 1)", c.TestConcurrency)
+	}
+
+	if c.MaxExecutionTime < 0 {
+		return fmt.Errorf("invalid MAX_EXECUTION_TIME: %s (must not be negative)", c.MaxExecutionTime)
+	}
+
+	if c.MaxBits < nist.MinBits || c.MaxBits > math.MaxInt32 {
+		return fmt.Errorf("invalid MAX_BITS: %d (must be %d-%d)", c.MaxBits, nist.MinBits, math.MaxInt32)
+	}
+
+	if c.StreamMaxBits < nist.MinBits || c.StreamMaxBits > math.MaxInt32 {
+		return fmt.Errorf("invalid STREAM_MAX_BITS: %d (must be %d-%d)", c.StreamMaxBits, nist.MinBits, math.MaxInt32)
+	}
+
+	// A single request must fit into memory
+	if available, ok := availableMemory(); ok {
+		for _, limit := range []struct {
+			name string
+			bits int
+		}{{"MAX_BITS", c.MaxBits}, {"STREAM_MAX_BITS", c.StreamMaxBits}} {
+			if need := nist.EstimateMemory(limit.bits); need > available {
+				return fmt.Errorf("invalid %s: %d bits need about %d MiB, only %d MiB available",
+					limit.name, limit.bits, need>>20, available>>20)
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -172,3 +221,13 @@ func getEnvBool(key string, defaultValue bool) bool {
 	}
 	return defaultValue
 }
+
+// getEnvDuration reads a duration (e.g. "90s", "5m") from environment variable or returns default
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
@@ -1,7 +1,10 @@
 package config
 
 import (
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestLoadWithEnvOverrides(t *testing.T) {
@@ -18,6 +21,11 @@ func TestLoadWithEnvOverrides(t *testing.T) {
 	t.Setenv("TLS_CA_FILE", "/tmp/ca.pem")
 	t.Setenv("TLS_CLIENT_AUTH", "requireandverify")
 	t.Setenv("TLS_MIN_VERSION", "1.3")
+	t.Setenv("TEST_CONCURRENCY", "3")
+	t.Setenv("MAX_EXECUTION_TIME", "90s")
+	t.Setenv("MAX_BITS", "20000000")
+	t.Setenv("STREAM_MAX_BITS", "200000000")
+	withAvailableMemory(t, 64<<30)
 
 	cfg, err := Load()
 	if err != nil {
@@ -54,6 +62,18 @@ func TestLoadWithEnvOverrides(t *testing.T) {
 	if cfg.TLSMinVersion != "1.3" {
 		t.Fatalf("unexpected TLS min version: %s", cfg.TLSMinVersion)
 	}
+	if cfg.TestConcurrency != 3 {
+		t.Fatalf("unexpected test concurrency: %d", cfg.TestConcurrency)
+	}
+	if cfg.MaxExecutionTime != 90*time.Second {
+		t.Fatalf("unexpected max execution time: %s", cfg.MaxExecutionTime)
+	}
+	if cfg.MaxBits != 20000000 {
+		t.Fatalf("unexpected max bits: %d", cfg.MaxBits)
+	}
+	if cfg.StreamMaxBits != 200000000 {
+		t.Fatalf("unexpected stream max bits: %d", cfg.StreamMaxBits)
+	}
 }
 
 func TestValidateFailures(t *testing.T) {
@@ -70,6 +90,12 @@ func TestValidateFailures(t *testing.T) {
 		{"tls enabled missing key", Config{GRPCPort: 9000, MetricsPort: 9001, LogLevel: "info", TLSEnabled: true, TLSCertFile: "/tmp/cert.pem"}},
 		{"tls enabled invalid client auth", Config{GRPCPort: 9000, MetricsPort: 9001, LogLevel: "info", TLSEnabled: true, TLSCertFile: "/tmp/cert.pem", TLSKeyFile: "/tmp/key.pem", TLSClientAuth: "invalid"}},
 		{"tls enabled invalid min version", Config{GRPCPort: 9000, MetricsPort: 9001, LogLevel: "info", TLSEnabled: true, TLSCertFile: "/tmp/cert.pem", TLSKeyFile: "/tmp/key.pem", TLSMinVersion: "1.1"}},
+		{"bad test concurrency", Config{GRPCPort: 9000, MetricsPort: 9001, LogLevel: "info", TestConcurrency: 0}},
+		{"negative max execution time", Config{GRPCPort: 9000, MetricsPort: 9001, LogLevel: "info", TestConcurrency: 1, MaxExecutionTime: -time.Second}},
+		{"max bits below minimum", Config{GRPCPort: 9000, MetricsPort: 9001, LogLevel: "info", TestConcurrency: 1, MaxBits: 1000}},
+		{"max bits above int32", Config{GRPCPort: 9000, MetricsPort: 9001, LogLevel: "info", TestConcurrency: 1, MaxBits: 1 << 31}},
+		{"stream max bits below minimum", Config{GRPCPort: 9000, MetricsPort: 9001, LogLevel: "info", TestConcurrency: 1, MaxBits: 1e7, StreamMaxBits: 1000}},
+		{"stream max bits above int32", Config{GRPCPort: 9000, MetricsPort: 9001, LogLevel: "info", TestConcurrency: 1, MaxBits: 1e7, StreamMaxBits: 1 << 31}},
 	}
 
 	for _, tt := range tests {
@@ -86,13 +112,20 @@ func TestValidateFailures(t *testing.T) {
 	if v := getEnvInt("SOME_INT", 42); v != 42 {
 		t.Fatalf("expected default on parse error, got %d", v)
 	}
+
+	// getEnvDuration falls back on parse error
+	t.Setenv("SOME_DURATION", "soon")
+	if v := getEnvDuration("SOME_DURATION", time.Minute); v != time.Minute {
+		t.Fatalf("expected default on parse error, got %s", v)
+	}
 }
 
 func TestLoadDefaults(t *testing.T) {
 	// Clear any environment variables
-	for _, key := range []string{"GRPC_PORT", "METRICS_PORT", "LOG_LEVEL", "AUTH_ENABLED", "AUTH_ISSUER", "AUTH_AUDIENCE", "AUTH_JWKS_URL", "TLS_ENABLED", "TLS_CERT_FILE", "TLS_KEY_FILE", "TLS_CA_FILE", "TLS_CLIENT_AUTH", "TLS_MIN_VERSION"} {
+	for _, key := range []string{"GRPC_PORT", "METRICS_PORT", "LOG_LEVEL", "AUTH_ENABLED", "AUTH_ISSUER", "AUTH_AUDIENCE", "AUTH_JWKS_URL", "TLS_ENABLED", "TLS_CERT_FILE", "TLS_KEY_FILE", "TLS_CA_FILE", "TLS_CLIENT_AUTH", "TLS_MIN_VERSION", "TEST_CONCURRENCY", "MAX_EXECUTION_TIME", "MAX_BITS", "STREAM_MAX_BITS"} {
 		t.Setenv(key, "")
 	}
+	withAvailableMemory(t, 64<<30)
 
 	cfg, err := Load()
 	if err != nil {
@@ -127,6 +160,18 @@ func TestLoadDefaults(t *testing.T) {
 	if cfg.TLSMinVersion != "1.2" {
 		t.Errorf("expected TLSMinVersion to default to '1.2', got %s", cfg.TLSMinVersion)
 	}
+	if cfg.TestConcurrency != runtime.GOMAXPROCS(0) {
+		t.Errorf("expected TestConcurrency to default to GOMAXPROCS, got %d", cfg.TestConcurrency)
+	}
+	if cfg.MaxExecutionTime != 5*time.Minute {
+		t.Errorf("expected MaxExecutionTime to default to 5m, got %s", cfg.MaxExecutionTime)
+	}
+	if cfg.MaxBits != 10000000 {
+		t.Errorf("expected MaxBits to default to 10000000, got %d", cfg.MaxBits)
+	}
+	if cfg.StreamMaxBits != 10000000 {
+		t.Errorf("expected StreamMaxBits to default to 10000000, got %d", cfg.StreamMaxBits)
+	}
 }
 
 func TestLoadInvalidConfig(t *testing.T) {
@@ -136,3 +181,38 @@ func TestLoadInvalidConfig(t *testing.T) {
 		t.Fatal("expected error for invalid port")
 	}
 }
+
+func TestValidateAvailableMemory(t *testing.T) {
+	cfg := Config{GRPCPort: 9000, MetricsPort: 9001, LogLevel: "info", TestConcurrency: 1, MaxBits: 1e7, StreamMaxBits: 1e8}
+
+	withAvailableMemory(t, 64<<30)
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected error with enough memory: %v", err)
+	}
+
+	// 1e7 bits fit into 1 GiB, 1e8 bits do not
+	withAvailableMemory(t, 1<<30)
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "STREAM_MAX_BITS") {
+		t.Fatalf("expected STREAM_MAX_BITS memory error, got %v", err)
+	}
+
+	cfg.MaxBits = 1e8
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "MAX_BITS") {
+		t.Fatalf("expected MAX_BITS memory error, got %v", err)
+	}
+
+	// Unknown memory is not enforced
+	availableMemory = func() (uint64, bool) { return 0, false }
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected error with unknown memory: %v", err)
+	}
+}
+
+// withAvailableMemory overrides the detected available memory for the duration of the test
+func withAvailableMemory(t *testing.T, bytes uint64) {
+	t.Helper()
+	orig := availableMemory
+	t.Cleanup(func() { availableMemory = orig })
+	availableMemory = func() (uint64, bool) { return bytes, true }
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// availableMemory is a variable to allow mocking in tests
+var availableMemory = systemAvailableMemory
+
+// cgroupLimitFiles are the memory limit files of cgroup v2 and v1
+var cgroupLimitFiles = []string{
+	"/sys/fs/cgroup/memory.max",
+	"/sys/fs/cgroup/memory/memory.limit_in_bytes",
+}
+
+// systemAvailableMemory returns the memory available to the process in bytes: the smaller
+// of MemAvailable in /proc/meminfo and the cgroup memory limit. ok is false if neither
+// can be determined (e.g. on non-Linux systems), in which case no limit is enforced.
+func systemAvailableMemory() (available uint64, ok bool) {
+	if v, found := readMemAvailable("/proc/meminfo"); found {
+		available, ok = v, true
+	}
+	for _, path := range cgroupLimitFiles {
+		if v, found := readCgroupLimit(path); found && (!ok || v < available) {
+			available, ok = v, true
+		}
+	}
+	return available, ok
+}
+
+// readMemAvailable reads the MemAvailable entry (in kB) of a meminfo file
+func readMemAvailable(path string) (uint64, bool) {
+	f, err := os.Open(path) // #nosec G304 -- fixed system path
+	if err != nil {
+		return 0, false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "MemAvailable:" {
+			continue
+		}
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		return kb * 1024, true
+	}
+	return 0, false
+}
+
+// readCgroupLimit reads a cgroup memory limit in bytes; "max" means no limit
+func readCgroupLimit(path string) (uint64, bool) {
+	data, err := os.ReadFile(path) // #nosec G304 -- fixed system path
+	if err != nil {
+		return 0, false
+	}
+	limit, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return limit, true
+}
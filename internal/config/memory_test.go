@@ -0,0 +1,45 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSystemAvailableMemory(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		return path
+	}
+
+	meminfo := write("meminfo", "MemTotal:       16000000 kB\nMemAvailable:    8000000 kB\n")
+	if v, ok := readMemAvailable(meminfo); !ok || v != 8000000*1024 {
+		t.Errorf("unexpected MemAvailable: %d, %v", v, ok)
+	}
+	if _, ok := readMemAvailable(write("nomeminfo", "MemTotal: 1 kB\n")); ok {
+		t.Error("expected no MemAvailable entry")
+	}
+	if _, ok := readMemAvailable(filepath.Join(dir, "missing")); ok {
+		t.Error("expected missing file to be ignored")
+	}
+
+	limit := write("memory.max", "2147483648\n")
+	if v, ok := readCgroupLimit(limit); !ok || v != 2<<30 {
+		t.Errorf("unexpected cgroup limit: %d, %v", v, ok)
+	}
+	if _, ok := readCgroupLimit(write("unlimited", "max\n")); ok {
+		t.Error("expected \"max\" to mean no limit")
+	}
+
+	// The smaller of the cgroup limit and MemAvailable wins
+	origFiles := cgroupLimitFiles
+	t.Cleanup(func() { cgroupLimitFiles = origFiles })
+	cgroupLimitFiles = []string{limit}
+	if v, ok := systemAvailableMemory(); !ok || v > 2<<30 {
+		t.Errorf("expected the cgroup limit to cap available memory, got %d, %v", v, ok)
+	}
+}
@@ -0,0 +1,155 @@
+// Package datasets computes the binary expansions of pi, e, sqrt(2) and sqrt(3) that the
+// NIST STS ships as its sample data files data.pi, data.e, data.sqrt2 and data.sqrt3, so
+// the reference results of SP 800-22 Appendix B can be reproduced without those files.
+//
+// Like the STS files, every expansion starts with the most significant bit of the
+// integer part: pi = 11.0010..., e = 10.1011..., sqrt(2) = 1.0110..., sqrt(3) = 1.1011...
+package datasets
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+)
+
+// guardBits are computed beyond the requested length so that the truncation of the
+// series and square roots cannot reach the returned bits.
+const guardBits = 64
+
+// Pi returns the first n bits of the binary expansion of pi, packed MSB-first.
+func Pi(n int) []byte {
+	return pack(piScaled(n), n)
+}
+
+// E returns the first n bits of the binary expansion of e, packed MSB-first.
+func E(n int) []byte {
+	return pack(eScaled(n), n)
+}
+
+// Sqrt2 returns the first n bits of the binary expansion of sqrt(2), packed MSB-first.
+func Sqrt2(n int) []byte {
+	return pack(sqrtScaled(2, n), n)
+}
+
+// Sqrt3 returns the first n bits of the binary expansion of sqrt(3), packed MSB-first.
+func Sqrt3(n int) []byte {
+	return pack(sqrtScaled(3, n), n)
+}
+
+// expansions maps the STS file names to their expansions.
+var expansions = map[string]func(int) []byte{
+	"data.pi":    Pi,
+	"data.e":     E,
+	"data.sqrt2": Sqrt2,
+	"data.sqrt3": Sqrt3,
+}
+
+// Names returns the STS file names of the datasets in sorted order.
+func Names() []string {
+	names := make([]string, 0, len(expansions))
+	for name := range expansions {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Bits returns the first n bits of the named dataset (e.g. "data.pi"), packed MSB-first.
+func Bits(name string, n int) ([]byte, error) {
+	f, ok := expansions[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown dataset %q", name)
+	}
+	if n <= 0 {
+		return nil, fmt.Errorf("invalid number of bits %d", n)
+	}
+	return f(n), nil
+}
+
+// pack returns the n most significant bits of x, which must have at least n bits,
+// left-aligned in (n+7)/8 bytes.
+func pack(x *big.Int, n int) []byte {
+	x.Rsh(x, uint(x.BitLen()-n))
+	x.Lsh(x, uint(8*((n+7)/8)-n))
+	return x.FillBytes(make([]byte, (n+7)/8))
+}
+
+// piScaled returns floor(pi * 2^k) using the Chudnovsky series:
+// pi = 426880 sqrt(10005) Q(0, N) / T(0, N).
+func piScaled(k int) *big.Int {
+	k += guardBits
+	// Every term adds log2(151931373056000) = 47.1 bits
+	_, q, t := chudnovsky(0, int64(k)/47+2)
+
+	x := new(big.Int).Lsh(big.NewInt(10005), uint(2*k))
+	x.Sqrt(x)
+	x.Mul(x, big.NewInt(426880)).Mul(x, q).Quo(x, t)
+	return x.Rsh(x, guardBits)
+}
+
+// chudnovsky returns P, Q and T of the binary splitting of the Chudnovsky series over
+// the terms [a, b).
+func chudnovsky(a, b int64) (p, q, t *big.Int) {
+	if b-a == 1 {
+		if a == 0 {
+			p, q = big.NewInt(1), big.NewInt(1)
+		} else {
+			// P(a) = (6a-5)(2a-1)(6a-1), Q(a) = a^3 640320^3 / 24
+			p = big.NewInt(6*a - 5)
+			p.Mul(p, big.NewInt(2*a-1)).Mul(p, big.NewInt(6*a-1))
+			q = big.NewInt(a)
+			q.Mul(q, q).Mul(q, big.NewInt(a)).Mul(q, big.NewInt(10939058860032000))
+		}
+		t = big.NewInt(13591409 + 545140134*a)
+		t.Mul(t, p)
+		if a%2 == 1 {
+			t.Neg(t)
+		}
+		return p, q, t
+	}
+
+	m := (a + b) / 2
+	p1, q1, t1 := chudnovsky(a, m)
+	p2, q2, t2 := chudnovsky(m, b)
+	// T = T1 Q2 + P1 T2
+	t = t1.Mul(t1, q2)
+	t.Add(t, t2.Mul(p1, t2))
+	return p1.Mul(p1, p2), q1.Mul(q1, q2), t
+}
+
+// eScaled returns floor(e * 2^k) from e = 1 + sum 1/j! for j = 1..N with N! > 2^k.
+func eScaled(k int) *big.Int {
+	k += guardBits
+	n := int64(1)
+	for log2Fact := 0.0; log2Fact <= float64(k); {
+		n++
+		log2Fact += math.Log2(float64(n))
+	}
+
+	p, q := eSeries(0, n)
+	x := p.Add(p, q)
+	x.Lsh(x, uint(k)).Quo(x, q)
+	return x.Rsh(x, guardBits)
+}
+
+// eSeries returns P and Q with P/Q = sum a!/j! for j = a+1..b by binary splitting.
+func eSeries(a, b int64) (p, q *big.Int) {
+	if b-a == 1 {
+		return big.NewInt(1), big.NewInt(b)
+	}
+
+	m := (a + b) / 2
+	p1, q1 := eSeries(a, m)
+	p2, q2 := eSeries(m, b)
+	// P = P1 Q2 + P2, Q = Q1 Q2
+	p = p1.Mul(p1, q2)
+	p.Add(p, p2)
+	return p, q1.Mul(q1, q2)
+}
+
+// sqrtScaled returns floor(sqrt(v) * 2^k), which is exact.
+func sqrtScaled(v int64, k int) *big.Int {
+	x := new(big.Int).Lsh(big.NewInt(v), uint(2*k))
+	return x.Sqrt(x)
+}
@@ -0,0 +1,71 @@
+package datasets
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestExpansions(t *testing.T) {
+	// The well-known hexadecimal expansions, integer part included
+	tests := []struct {
+		name   string
+		expand func(int) []byte
+		want   string
+		// bits of the integer part in front of the hexadecimal fraction
+		intBits int
+	}{
+		{"pi", Pi, "3243f6a8885a308d313198a2e0370734", 2},
+		{"e", E, "2b7e151628aed2a6abf7158809cf4f3c", 2},
+		{"sqrt2", Sqrt2, "16a09e667f3bcc908b2fb1366ea957d3", 1},
+		{"sqrt3", Sqrt3, "1bb67ae8584caa73b25742d7078b83b8", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Drop the leading zero bits of the first hex digit
+			ref, _ := hex.DecodeString(tt.want)
+			skip := 4 - tt.intBits
+			n := 8*len(ref) - skip - 4
+			var want strings.Builder
+			for i := skip; i < skip+n; i++ {
+				want.WriteByte('0' + ref[i/8]>>(7-i%8)&1)
+			}
+
+			got := tt.expand(n)
+			var gotBits strings.Builder
+			for i := range n {
+				gotBits.WriteByte('0' + got[i/8]>>(7-i%8)&1)
+			}
+			if gotBits.String() != want.String() {
+				t.Errorf("expected %s, got %s", want.String(), gotBits.String())
+			}
+
+			// Longer expansions extend shorter ones; pad bits are zero
+			long := tt.expand(100_003)
+			if short := tt.expand(20_000); !bytes.Equal(short, long[:len(short)]) {
+				t.Error("expansion of 20,000 bits is not a prefix of 100,003 bits")
+			}
+			if long[len(long)-1]&0x1f != 0 {
+				t.Errorf("pad bits are not zero: %08b", long[len(long)-1])
+			}
+		})
+	}
+}
+
+func TestBits(t *testing.T) {
+	names := Names()
+	if strings.Join(names, ",") != "data.e,data.pi,data.sqrt2,data.sqrt3" {
+		t.Fatalf("unexpected names %v", names)
+	}
+	data, err := Bits("data.pi", 16)
+	if err != nil || !bytes.Equal(data, []byte{0xc9, 0x0f}) {
+		t.Errorf("expected c90f, got %x (%v)", data, err)
+	}
+	if _, err := Bits("data.bad_rng", 16); err == nil {
+		t.Error("expected error for unknown dataset")
+	}
+	if _, err := Bits("data.e", 0); err == nil {
+		t.Error("expected error for zero bits")
+	}
+}
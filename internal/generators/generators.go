@@ -0,0 +1,136 @@
+// Package generators implements the sample generators shipped with the NIST STS
+// (SP 800-22 Rev. 1a, Appendix D and the STS generators.c) with their standard seeds, so
+// that known-good and known-bad bit streams can be produced reproducibly in-tree.
+package generators
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrUnknownGenerator is returned for generator names that are not implemented.
+var ErrUnknownGenerator = errors.New("unknown generator")
+
+// Generator names
+const (
+	LCG                    = "lcg"
+	QuadraticCongruential1 = "qcg1"
+	QuadraticCongruential2 = "qcg2"
+	CubicCongruential      = "ccg"
+	XOR                    = "xor"
+	ModExp                 = "modexp"
+	BlumBlumShub           = "bbs"
+	MicaliSchnorr          = "micali-schnorr"
+	GSHA1                  = "g-sha1"
+)
+
+// source is one generator algorithm. Each step yields the bits of one iteration,
+// MSB-first in out.
+type source interface {
+	step() (out []byte, bits int)
+}
+
+var sources = map[string]struct {
+	description string
+	new         func() source
+}{
+	LCG:                    {"Linear Congruential: x(i+1) = 950706376 x(i) mod 2^31-1", newLCG},
+	QuadraticCongruential1: {"Quadratic Congruential I: x(i+1) = x(i)^2 mod p (512-bit prime)", newQCG1},
+	QuadraticCongruential2: {"Quadratic Congruential II: x(i+1) = 2 x(i)^2 + 3 x(i) + 1 mod 2^512", newQCG2},
+	CubicCongruential:      {"Cubic Congruential: x(i+1) = x(i)^3 mod 2^512", newCCG},
+	XOR:                    {"Exclusive OR: x(i) = x(i-1) xor x(i-127)", newXOR},
+	ModExp:                 {"Modular Exponentiation: x(i+1) = g^y(i) mod p (512-bit prime)", newModExp},
+	BlumBlumShub:           {"Blum-Blum-Shub: x(i+1) = x(i)^2 mod pq (1024-bit modulus)", newBBS},
+	MicaliSchnorr:          {"Micali-Schnorr: y(i) = x(i)^11 mod n (1024-bit modulus), 837 bits per step", newMicaliSchnorr},
+	GSHA1:                  {"G using SHA-1: the FIPS 186 G function on a 160-bit key", newGSHA1},
+}
+
+// Names returns the names of all generators in sorted order.
+func Names() []string {
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Description returns a one-line description of the named generator.
+func Description(name string) string {
+	return sources[name].description
+}
+
+// Generator produces the bit stream of one STS generator from its standard seed.
+type Generator struct {
+	name string
+	src  source
+}
+
+// New returns the named generator, seeded as in the STS.
+func New(name string) (*Generator, error) {
+	s, ok := sources[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownGenerator, name)
+	}
+	return &Generator{name: name, src: s.new()}, nil
+}
+
+// Name returns the generator name.
+func (g *Generator) Name() string {
+	return g.name
+}
+
+// Sequence returns the next sequence of n bits, packed MSB-first (the last byte is
+// zero-padded). As in the STS, each sequence starts with a new generator step: the
+// bits of the last step that exceed n are discarded.
+func (g *Generator) Sequence(ctx context.Context, n int) ([]byte, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("invalid sequence length %d", n)
+	}
+	buf := make([]byte, (n+7)/8)
+	pos := 0
+	for steps := 0; pos < n; steps++ {
+		// The expensive generators need a few ms per 1000 steps
+		if steps%1024 == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		out, bits := g.src.step()
+		for i := 0; i < bits && pos < n; i++ {
+			if out[i/8]&(0x80>>(i%8)) != 0 {
+				buf[pos/8] |= 0x80 >> (pos % 8)
+			}
+			pos++
+		}
+	}
+	return buf, nil
+}
+
+// Generate returns num consecutive sequences of n bits from a freshly seeded generator,
+// concatenated as the STS would read them from a file. n must be a multiple of 8 if
+// num > 1.
+func Generate(ctx context.Context, name string, n, num int) ([]byte, error) {
+	if num <= 0 {
+		return nil, fmt.Errorf("invalid number of sequences %d", num)
+	}
+	if num > 1 && n%8 != 0 {
+		return nil, fmt.Errorf("sequence length must be a multiple of 8 bits for %d sequences, got %d", num, n)
+	}
+	g, err := New(name)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, 0, num*((n+7)/8))
+	for range num {
+		seq, err := g.Sequence(ctx, n)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, seq...)
+	}
+	return data, nil
+}
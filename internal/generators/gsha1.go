@@ -0,0 +1,72 @@
+package generators
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math/bits"
+)
+
+// sha1IV is the initial hash value of SHA-1.
+var sha1IV = [5]uint32{0x67452301, 0xEFCDAB89, 0x98BADCFE, 0x10325476, 0xC3D2E1F0}
+
+// sha1Block applies the SHA-1 compression function to one 64-byte block. Unlike
+// crypto/sha1 it does not pad the input, as required by the FIPS 186 G function.
+func sha1Block(h [5]uint32, block *[64]byte) [5]uint32 {
+	var w [80]uint32
+	for i := range 16 {
+		w[i] = binary.BigEndian.Uint32(block[4*i:])
+	}
+	for i := 16; i < 80; i++ {
+		w[i] = bits.RotateLeft32(w[i-3]^w[i-8]^w[i-14]^w[i-16], 1)
+	}
+
+	a, b, c, d, e := h[0], h[1], h[2], h[3], h[4]
+	for i := range 80 {
+		var f, k uint32
+		switch {
+		case i < 20:
+			f, k = b&c|^b&d, 0x5A827999
+		case i < 40:
+			f, k = b^c^d, 0x6ED9EBA1
+		case i < 60:
+			f, k = b&c|b&d|c&d, 0x8F1BBCDC
+		default:
+			f, k = b^c^d, 0xCA62C1D6
+		}
+		t := bits.RotateLeft32(a, 5) + f + e + k + w[i]
+		a, b, c, d, e = t, a, bits.RotateLeft32(b, 30), c, d
+	}
+	return [5]uint32{h[0] + a, h[1] + b, h[2] + c, h[3] + d, h[4] + e}
+}
+
+// gSHA1 is the FIPS 186 G function generator: G = SHA-1 compression of XKEY padded
+// with zeros to one block, and XKEY(i+1) = XKEY(i) + G + 1 mod 2^160.
+type gSHA1 struct {
+	xkey  [20]byte
+	block [64]byte
+	out   [20]byte
+}
+
+func newGSHA1() source {
+	g := &gSHA1{}
+	if _, err := hex.Decode(g.xkey[:], []byte(gSHA1Seed)); err != nil {
+		panic("generators: invalid G-SHA-1 seed")
+	}
+	return g
+}
+
+func (g *gSHA1) step() ([]byte, int) {
+	copy(g.block[:], g.xkey[:])
+	h := sha1Block(sha1IV, &g.block)
+	for i, v := range h {
+		binary.BigEndian.PutUint32(g.out[4*i:], v)
+	}
+
+	// XKEY = XKEY + G + 1, big-endian with carry
+	carry := uint(1)
+	for i := len(g.xkey) - 1; i >= 0; i-- {
+		sum := uint(g.xkey[i]) + uint(g.out[i]) + carry
+		g.xkey[i], carry = byte(sum), sum>>8
+	}
+	return g.out[:], 8 * len(g.out)
+}
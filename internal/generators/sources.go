@@ -0,0 +1,211 @@
+package generators
+
+import (
+	"math/big"
+)
+
+// Standard parameters and seeds of the STS generators.c
+const (
+	lcgSeed       = 23482349
+	lcgMultiplier = 950706376
+	lcgModulus    = 1<<31 - 1
+
+	// 512-bit prime p and base g of Quadratic Congruential I and Modular Exponentiation
+	primeP512 = "987b6a6bf2c56a97291c445409920032499f9ee7ad128301b5d0254aa1a9633fdbd378d40149f1e23a13849f3d45992f5c4c6b7104099bc301f6005f9d8115e1"
+	baseG512  = "3844506a9456c564b8b8538e0cc15aff46c95e69600f084f0657c2401b3c244734b62ea9bb95be4923b9b7e84eeaf1a224894ef0328d44bc3eb3e983644da3f5"
+	// Seed of Quadratic Congruential II and Cubic Congruential
+	seed512 = "7844506a9456c564b8b8538e0cc15aff46c95e69600f084f0657c2401b3c244734b62ea9bb95be4923b9b7e84eeaf1a224894ef0328d44bc3eb3e983644da3f5"
+	// Initial 160-bit exponent of Modular Exponentiation
+	modExpSeed = "7AB36982CE1ADF832019CDFEB2393CABDF0214EC"
+
+	// 512-bit primes p, q of Blum-Blum-Shub and Micali-Schnorr
+	primeP1024 = "E65097BAEC92E70478CAF4ED0ED94E1C94B154466BFB9EC9BE37B2B0FF8526C222B76E0E915017535AE8B9207250257D0A0C87C0DACEF78E17D1EF9DC44FD91F"
+	primeQ1024 = "E029AEFCF8EA2C29D99CB53DD5FA9BC1D0176F5DF8D9110FD16EE21F32E37BA86FF42F00531AD5B8A43073182CC2E15F5C86E8DA059E346777C9A985F7D8A867"
+	bbsSeed    = "10d6333cfac8e30e808d2192f7c0439480da79db9bbca1667d73be9a677ed31311f3b830937763837cb7b1b1dc75f14eea417f84d9625628750de99e7ef1e976"
+
+	// Micali-Schnorr with e = 11: of every 1024-bit y, the top r = 187 bits are the next
+	// x and the low k = 837 bits are output
+	msExponent = 11
+	msOutBits  = 837
+	msSeed     = "237c5f791c2cfe47bfb16d2d54a0d60665b20904ec822a6"
+
+	gSHA1Seed = "ec822a619d6ed5d9492218a7a4c5b15d57c61601"
+
+	// Seed of the Exclusive OR generator
+	xorSeed = "0001011011011001000101111001001010011011101101000100000010101111111010100100001010110110000000000100110000101110011111111100111"
+)
+
+func hexInt(s string) *big.Int {
+	x, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("generators: invalid constant " + s)
+	}
+	return x
+}
+
+// bitStep holds the output of generators that yield one bit per step.
+type bitStep [1]byte
+
+func (b *bitStep) bit(set bool) ([]byte, int) {
+	b[0] = 0
+	if set {
+		b[0] = 0x80
+	}
+	return b[:], 1
+}
+
+// lcg is the Linear Congruential generator. The STS outputs 1 when x/(2^31-1) >= 0.5.
+type lcg struct {
+	x   uint64
+	out bitStep
+}
+
+func newLCG() source { return &lcg{x: lcgSeed} }
+
+func (g *lcg) step() ([]byte, int) {
+	g.x = g.x * lcgMultiplier % lcgModulus
+	return g.out.bit(2*g.x > lcgModulus)
+}
+
+// bigStep holds the state of generators that output their 512-bit state every step.
+type bigStep struct {
+	x   *big.Int
+	out [64]byte
+}
+
+func (b *bigStep) emit() ([]byte, int) {
+	b.x.FillBytes(b.out[:])
+	return b.out[:], 512
+}
+
+// qcg1 is Quadratic Congruential I: x(i+1) = x(i)^2 mod p.
+type qcg1 struct {
+	bigStep
+	p *big.Int
+}
+
+func newQCG1() source { return &qcg1{bigStep: bigStep{x: hexInt(baseG512)}, p: hexInt(primeP512)} }
+
+func (g *qcg1) step() ([]byte, int) {
+	g.x.Mul(g.x, g.x).Mod(g.x, g.p)
+	return g.emit()
+}
+
+// mask512 reduces modulo 2^512.
+var mask512 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 512), big.NewInt(1))
+
+// qcg2 is Quadratic Congruential II: x(i+1) = 2 x(i)^2 + 3 x(i) + 1 mod 2^512.
+type qcg2 struct {
+	bigStep
+	t *big.Int
+}
+
+func newQCG2() source { return &qcg2{bigStep: bigStep{x: hexInt(seed512)}, t: new(big.Int)} }
+
+func (g *qcg2) step() ([]byte, int) {
+	// (2x + 3) x + 1
+	g.t.Lsh(g.x, 1).Add(g.t, big.NewInt(3))
+	g.x.Mul(g.t, g.x).Add(g.x, big.NewInt(1)).And(g.x, mask512)
+	return g.emit()
+}
+
+// ccg is the Cubic Congruential generator: x(i+1) = x(i)^3 mod 2^512.
+type ccg struct {
+	bigStep
+	t *big.Int
+}
+
+func newCCG() source { return &ccg{bigStep: bigStep{x: hexInt(seed512)}, t: new(big.Int)} }
+
+func (g *ccg) step() ([]byte, int) {
+	g.t.Mul(g.x, g.x)
+	g.x.Mul(g.t, g.x).And(g.x, mask512)
+	return g.emit()
+}
+
+// modExp is Modular Exponentiation: x(i) = g^y(i) mod p, where y(i+1) is the low 160
+// bits of x(i).
+type modExp struct {
+	bigStep
+	g, p, y *big.Int
+}
+
+func newModExp() source {
+	return &modExp{bigStep: bigStep{x: new(big.Int)}, g: hexInt(baseG512), p: hexInt(primeP512), y: hexInt(modExpSeed)}
+}
+
+func (g *modExp) step() ([]byte, int) {
+	g.x.Exp(g.g, g.y, g.p)
+	out, bits := g.emit()
+	g.y.SetBytes(g.out[44:])
+	return out, bits
+}
+
+// xor is the Exclusive OR generator: after the 127 seed bits, x(i) = x(i-1) xor x(i-127).
+type xor struct {
+	// ring holds the last 127 bits; ring[i%127] is x(i-127) before step i
+	ring [127]bool
+	i    int
+	out  bitStep
+}
+
+func newXOR() source {
+	g := &xor{}
+	for i, c := range xorSeed {
+		g.ring[i] = c == '1'
+	}
+	return g
+}
+
+func (g *xor) step() ([]byte, int) {
+	k := g.i % len(g.ring)
+	if g.i >= len(g.ring) {
+		g.ring[k] = g.ring[(g.i-1)%len(g.ring)] != g.ring[k]
+	}
+	g.i++
+	return g.out.bit(g.ring[k])
+}
+
+// bbs is Blum-Blum-Shub: x(0) = s^2 mod n and x(i+1) = x(i)^2 mod n, outputting the
+// least significant bit of every x(i+1).
+type bbs struct {
+	x, n *big.Int
+	out  bitStep
+}
+
+func newBBS() source {
+	n := new(big.Int).Mul(hexInt(primeP1024), hexInt(primeQ1024))
+	s := hexInt(bbsSeed)
+	return &bbs{x: s.Mul(s, s).Mod(s, n), n: n}
+}
+
+func (g *bbs) step() ([]byte, int) {
+	g.x.Mul(g.x, g.x).Mod(g.x, g.n)
+	return g.out.bit(g.x.Bit(0) == 1)
+}
+
+// micaliSchnorr is the Micali-Schnorr generator: y(i) = x(i)^e mod n, outputting the low
+// 837 bits of y(i) and continuing with x(i+1) = y(i) >> 837.
+type micaliSchnorr struct {
+	x, y, n, e, mask, low *big.Int
+	out                   [(msOutBits + 7) / 8]byte
+}
+
+func newMicaliSchnorr() source {
+	return &micaliSchnorr{
+		x:    hexInt(msSeed),
+		y:    new(big.Int),
+		n:    new(big.Int).Mul(hexInt(primeP1024), hexInt(primeQ1024)),
+		e:    big.NewInt(msExponent),
+		mask: new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), msOutBits), big.NewInt(1)),
+		low:  new(big.Int),
+	}
+}
+
+func (g *micaliSchnorr) step() ([]byte, int) {
+	g.y.Exp(g.x, g.e, g.n)
+	// Left-align the low 837 bits in 105 bytes
+	g.low.And(g.y, g.mask).Lsh(g.low, uint(8*len(g.out)-msOutBits)).FillBytes(g.out[:])
+	g.x.Rsh(g.y, msOutBits)
+	return g.out[:], msOutBits
+}
@@ -37,6 +37,35 @@ func UnaryRequestIDInterceptor() grpc.UnaryServerInterceptor {
 	}
 }
 
+// StreamRequestIDInterceptor adds a unique request ID to each streaming gRPC call
+func StreamRequestIDInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		requestID := uuid.New().String()
+
+		// Send to the client as header metadata; failure is not critical
+		_ = ss.SetHeader(metadata.Pairs("x-request-id", requestID))
+
+		ctx := context.WithValue(ss.Context(), RequestIDKey, requestID)
+		return handler(srv, &requestIDStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// requestIDStream overrides the context of a server stream to carry the request ID
+type requestIDStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the stream context including the request ID
+func (s *requestIDStream) Context() context.Context {
+	return s.ctx
+}
+
 // GetRequestID retrieves the request ID from the context
 func GetRequestID(ctx context.Context) string {
 	if id, ok := ctx.Value(RequestIDKey).(string); ok {
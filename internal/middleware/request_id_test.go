@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 func TestUnaryRequestIDInterceptor(t *testing.T) {
@@ -40,6 +41,42 @@ func TestUnaryRequestIDInterceptor(t *testing.T) {
 	}
 }
 
+// mockServerStream is a minimal grpc.ServerStream for interceptor tests
+type mockServerStream struct {
+	grpc.ServerStream
+	header metadata.MD
+}
+
+func (m *mockServerStream) Context() context.Context { return context.Background() }
+
+func (m *mockServerStream) SetHeader(md metadata.MD) error {
+	m.header = metadata.Join(m.header, md)
+	return nil
+}
+
+func TestStreamRequestIDInterceptor(t *testing.T) {
+	interceptor := StreamRequestIDInterceptor()
+	ss := &mockServerStream{}
+
+	var requestID string
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		requestID = GetRequestID(stream.Context())
+		return nil
+	}
+
+	info := &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream", IsClientStream: true}
+	if err := interceptor(nil, ss, info, handler); err != nil {
+		t.Fatalf("interceptor returned error: %v", err)
+	}
+
+	if len(requestID) != 36 {
+		t.Errorf("request ID has invalid format: %q", requestID)
+	}
+	if got := ss.header.Get("x-request-id"); len(got) != 1 || got[0] != requestID {
+		t.Errorf("expected x-request-id header %q, got %v", requestID, got)
+	}
+}
+
 func TestGetRequestID_NoID(t *testing.T) {
 	ctx := context.Background()
 	requestID := GetRequestID(ctx)
@@ -1,6 +1,7 @@
 package nist
 
 import (
+	"context"
 	"math"
 
 	"gonum.org/v1/gonum/mathext"
@@ -9,44 +10,47 @@ import (
 // ApproximateEntropyTest implements the NIST Approximate Entropy test.
 // It returns the p-value and whether it passes at Alpha.
 func ApproximateEntropyTest(bitstream []byte, m int) (float64, bool) {
-	bits := expandBits(bitstream)
-	n := len(bits)
-	if n == 0 || m < 1 {
-		return 0, false
-	}
+	o, _ := ApproximateEntropyTestSequence(context.Background(), BitSequenceFromBytes(bitstream), m)
+	return o.PValue, o.Passed()
+}
 
-	var apEn [2]float64
-	for r := 0; r < 2; r++ {
-		blockSize := m + r
-		numBlocks := n
-		powLen := (1 << (blockSize + 1)) - 1
-		P := make([]int, powLen)
-
-		for i := 0; i < numBlocks; i++ {
-			k := 1
-			for j := 0; j < blockSize; j++ {
-				k <<= 1
-				if bits[(i+j)%n] == 1 {
-					k++
-				}
-			}
-			P[k-1]++
-		}
+// ApproximateEntropyTestSequence runs the Approximate Entropy test on a BitSequence of any length.
+// The outcome's Values hold phi^(m), phi^(m+1) and ApEn(m).
+func ApproximateEntropyTestSequence(ctx context.Context, bits BitSequence, m int) (Outcome, error) {
+	n := bits.n
+	if m < 1 {
+		return errorOutcome("invalid block length m=%d", m), nil
+	}
+	if n == 0 {
+		return notApplicable("empty bitstream"), nil
+	}
 
-		sum := 0.0
-		index := (1 << blockSize) - 1
-		for i := 0; i < (1 << blockSize); i++ {
-			if P[index] > 0 {
-				sum += float64(P[index]) * math.Log(float64(P[index])/float64(numBlocks))
-			}
-			index++
-		}
-		apEn[r] = sum / float64(numBlocks)
+	// A single pass counts the (m+1)-bit patterns; those of m bits are their prefixes.
+	counts, err := patternCounts(ctx, bits, m+1)
+	if err != nil {
+		return Outcome{}, err
 	}
+	var apEn [2]float64
+	apEn[1] = phi(counts, n)
+	apEn[0] = phi(marginalCounts(counts), n)
 
 	apen := apEn[0] - apEn[1]
 	chiSquared := 2.0 * float64(n) * (math.Log(2) - apen)
 	pValue := mathext.GammaIncRegComp(math.Pow(2, float64(m-1)), chiSquared/2.0)
 
-	return pValue, pValue >= Alpha
+	o := statisticOutcome(pValue, chiSquared)
+	o.Values = []float64{apEn[0], apEn[1], apen}
+	return o, nil
+}
+
+// phi computes phi^(m) = sum pi_i log pi_i of the Approximate Entropy test from the
+// pattern counts of m bits in a sequence of n bits.
+func phi(counts []int, n int) float64 {
+	sum := 0.0
+	for _, c := range counts {
+		if c > 0 {
+			sum += float64(c) * math.Log(float64(c)/float64(n))
+		}
+	}
+	return sum / float64(n)
 }
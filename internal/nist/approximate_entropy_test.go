@@ -2,6 +2,8 @@ package nist
 
 import (
 	"testing"
+
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/testrand"
 )
 
 func TestApproximateEntropy(t *testing.T) {
@@ -15,12 +17,7 @@ func TestApproximateEntropy(t *testing.T) {
 
 	t.Run("valid_input", func(t *testing.T) {
 		// Deterministic pseudo-random stream for stability
-		data := make([]byte, 10000)
-		state := uint64(42)
-		for i := range data {
-			state = state*6364136223846793005 + 1442695040888963407
-			data[i] = byte(state >> 56)
-		}
+		data := testrand.Bytes(10000, 42)
 		p, pass := ApproximateEntropyTest(data, 5)
 		if p <= 0 || p > 1 {
 			t.Fatalf("p-value out of range: %.6f", p)
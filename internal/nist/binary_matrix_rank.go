@@ -1,146 +1,182 @@
 package nist
 
-import "math"
+import (
+	"context"
+	"math"
+)
 
 // BinaryMatrixRankTest implements the NIST Binary Matrix Rank test (32x32).
 // It returns the p-value and whether it passes at Alpha.
 func BinaryMatrixRankTest(bitstream []byte) (float64, bool) {
-	bits := expandBits(bitstream)
-	n := len(bits)
-
-	const (
-		m = 32
-		q = 32
-	)
+	o, _ := BinaryMatrixRankTestSequence(context.Background(), BitSequenceFromBytes(bitstream),
+		DefaultMatrixRows, DefaultMatrixColumns)
+	return o.PValue, o.Passed()
+}
 
-	N := n / (m * q)
+const (
+	// DefaultMatrixRows and DefaultMatrixColumns are M and Q of the Binary Matrix Rank
+	// test, as fixed in the NIST STS.
+	DefaultMatrixRows    = 32
+	DefaultMatrixColumns = 32
+
+	// MinBinaryMatrices is the minimum number of matrices N = n/(MQ) that SP 800-22
+	// section 2.5.7 recommends.
+	MinBinaryMatrices = 38
+)
+
+// BinaryMatrixRankTestSequence runs the Binary Matrix Rank test on a BitSequence of any
+// length with M x Q matrices (M rows of Q bits each), which the STS supports by changing
+// its compile-time constants. Both must be at least 2. The outcome's Counts hold F_M,
+// F_M-1 and N - F_M - F_M-1, the number of matrices of full rank, of rank one less and
+// of lower rank.
+func BinaryMatrixRankTestSequence(ctx context.Context, bits BitSequence, M, Q int) (Outcome, error) {
+	if M < 2 || Q < 2 {
+		return errorOutcome("invalid matrix size %dx%d: need at least 2x2", M, Q), nil
+	}
+	n := bits.n
+	N := n / (M * Q)
 	if N == 0 {
-		return 0, false
+		return notApplicable("insufficient bits for %dx%d matrices: got %d, need at least %d", M, Q, n, M*Q), nil
 	}
 
-	p32 := binaryRankProbability(32)
-	p31 := binaryRankProbability(31)
-	p30 := 1 - (p32 + p31)
+	// Ranks are counted in three classes: full rank m, m-1 and below.
+	m := min(M, Q)
+	pFull := binaryRankProbability(m, M, Q)
+	pFull1 := binaryRankProbability(m-1, M, Q)
+	pRest := 1 - (pFull + pFull1)
 
-	var f32, f31 float64
+	var fFull, fFull1 float64
+	matrix := newBitMatrix(M, Q)
+	done := ctx.Done()
 	for k := 0; k < N; k++ {
-		matrix := make([][]uint8, m)
-		for i := range matrix {
-			matrix[i] = make([]uint8, q)
-			for j := 0; j < q; j++ {
-				matrix[i][j] = bits[k*(m*q)+i*q+j]
-			}
+		if isDone(done) {
+			return Outcome{}, ctx.Err()
 		}
+		matrix.load(bits, k*M*Q)
 
-		rank := computeRank(matrix)
-		if rank == 32 {
-			f32++
-		} else if rank == 31 {
-			f31++
+		switch matrix.rank() {
+		case m:
+			fFull++
+		case m - 1:
+			fFull1++
 		}
 	}
-	f30 := float64(N) - (f32 + f31)
+	fRest := float64(N) - (fFull + fFull1)
 
-	chiSquared := math.Pow(f32-float64(N)*p32, 2)/(float64(N)*p32) +
-		math.Pow(f31-float64(N)*p31, 2)/(float64(N)*p31) +
-		math.Pow(f30-float64(N)*p30, 2)/(float64(N)*p30)
+	chiSquared := math.Pow(fFull-float64(N)*pFull, 2)/(float64(N)*pFull) +
+		math.Pow(fFull1-float64(N)*pFull1, 2)/(float64(N)*pFull1) +
+		math.Pow(fRest-float64(N)*pRest, 2)/(float64(N)*pRest)
 
 	pValue := math.Exp(-chiSquared / 2.0)
 
-	return pValue, pValue >= Alpha
+	o := statisticOutcome(pValue, chiSquared)
+	o.Counts = []int{int(fFull), int(fFull1), int(fRest)}
+	return o, nil
 }
 
-func binaryRankProbability(r int) float64 {
+// binaryRankProbability returns the probability that a random M x Q binary matrix has
+// rank r (SP 800-22 section 3.5).
+func binaryRankProbability(r, M, Q int) float64 {
 	product := 1.0
 	for i := 0; i <= r-1; i++ {
-		num := (1.0 - math.Pow(2, float64(i-32))) * (1.0 - math.Pow(2, float64(i-32)))
+		num := (1.0 - math.Pow(2, float64(i-Q))) * (1.0 - math.Pow(2, float64(i-M)))
 		den := 1.0 - math.Pow(2, float64(i-r))
 		product *= num / den
 	}
-	return math.Pow(2, float64(r*(32+32-r)-32*32)) * product
+	return math.Pow(2, float64(r*(Q+M-r)-M*Q)) * product
 }
 
-func computeRank(matrix [][]uint8) int {
-	M := len(matrix)
-	Q := len(matrix[0])
-	m := M
-	if Q < m {
-		m = Q
-	}
+// bitMatrix is a binary matrix whose rows are packed into uint32 words, the first
+// column in the most significant bit of a row's first word. It is reused for every
+// block of the sequence.
+type bitMatrix struct {
+	rows, cols int
+	// stride is the number of words per row.
+	stride int
+	data   []uint32
+}
 
-	for i := 0; i < m-1; i++ {
-		if matrix[i][i] == 1 {
-			performRowOps(matrix, i, true)
-		} else if findUnitAndSwap(matrix, i, true) {
-			performRowOps(matrix, i, true)
+func newBitMatrix(rows, cols int) *bitMatrix {
+	stride := (cols + 31) / 32
+	return &bitMatrix{rows: rows, cols: cols, stride: stride, data: make([]uint32, rows*stride)}
+}
+
+// load fills the matrix row by row with the rows*cols bits of s starting at idx.
+func (a *bitMatrix) load(s BitSequence, idx int) {
+	for i := 0; i < a.rows; i++ {
+		row := a.data[i*a.stride : (i+1)*a.stride]
+		for w := range row {
+			k := min(32, a.cols-32*w)
+			row[w] = uint32(s.bitsAt(idx+i*a.cols+32*w, k) << (32 - uint(k))) //nolint:gosec // k <= 32 bits
 		}
 	}
+}
 
-	for i := m - 1; i > 0; i-- {
-		if matrix[i][i] == 1 {
-			performRowOps(matrix, i, false)
-		} else if findUnitAndSwap(matrix, i, false) {
-			performRowOps(matrix, i, false)
-		}
+// rank reduces the matrix to row echelon form by Gaussian elimination over GF(2) and
+// returns its rank. The contents of the matrix are lost.
+func (a *bitMatrix) rank() int {
+	if a.stride == 1 {
+		return rankRows(a.data, a.cols)
 	}
 
-	rank := m
-	for i := 0; i < M; i++ {
-		allZero := true
-		for j := 0; j < Q; j++ {
-			if matrix[i][j] == 1 {
-				allZero = false
+	rank := 0
+	for col := 0; col < a.cols && rank < a.rows; col++ {
+		w := col / 32
+		mask := uint32(1) << (31 - uint(col%32))
+
+		pivot := -1
+		for i := rank; i < a.rows; i++ {
+			if a.data[i*a.stride+w]&mask != 0 {
+				pivot = i
 				break
 			}
 		}
-		if allZero {
-			rank--
+		if pivot < 0 {
+			continue
 		}
-	}
 
-	return rank
-}
-
-func performRowOps(matrix [][]uint8, i int, forward bool) {
-	M := len(matrix)
-	Q := len(matrix[0])
-	if forward {
-		for j := i + 1; j < M; j++ {
-			if matrix[j][i] == 1 {
-				for k := i; k < Q; k++ {
-					matrix[j][k] ^= matrix[i][k]
-				}
+		// The pivot row is zero before col, so only its words from w on are combined.
+		top := a.data[rank*a.stride+w : (rank+1)*a.stride]
+		if pivot != rank {
+			row := a.data[pivot*a.stride+w : (pivot+1)*a.stride]
+			for j := range top {
+				top[j], row[j] = row[j], top[j]
 			}
 		}
-	} else {
-		for j := i - 1; j >= 0; j-- {
-			if matrix[j][i] == 1 {
-				for k := 0; k < Q; k++ {
-					matrix[j][k] ^= matrix[i][k]
+		for i := rank + 1; i < a.rows; i++ {
+			row := a.data[i*a.stride+w : (i+1)*a.stride]
+			if row[0]&mask != 0 {
+				for j := range row {
+					row[j] ^= top[j]
 				}
 			}
 		}
+		rank++
 	}
+	return rank
 }
 
-func findUnitAndSwap(matrix [][]uint8, i int, forward bool) bool {
-	M := len(matrix)
-
-	if forward {
-		for idx := i + 1; idx < M; idx++ {
-			if matrix[idx][i] == 1 {
-				matrix[i], matrix[idx] = matrix[idx], matrix[i]
-				return true
-			}
+// rankRows is bitMatrix.rank for rows of at most 32 columns, one word each.
+func rankRows(rows []uint32, cols int) int {
+	rank := 0
+	for col := 0; col < cols && rank < len(rows); col++ {
+		mask := uint32(1) << (31 - uint(col))
+		pivot := rank
+		for pivot < len(rows) && rows[pivot]&mask == 0 {
+			pivot++
 		}
-	} else {
-		for idx := i - 1; idx >= 0; idx-- {
-			if matrix[idx][i] == 1 {
-				matrix[i], matrix[idx] = matrix[idx], matrix[i]
-				return true
-			}
+		if pivot == len(rows) {
+			continue
 		}
-	}
 
-	return false
+		rows[rank], rows[pivot] = rows[pivot], rows[rank]
+		// Branch-free: the pivot column of a random row is set half of the time
+		top := rows[rank]
+		shift := 31 - uint(col)
+		for i := rank + 1; i < len(rows); i++ {
+			rows[i] ^= top & -(rows[i] >> shift & 1)
+		}
+		rank++
+	}
+	return rank
 }
@@ -1,7 +1,12 @@
 package nist
 
 import (
+	"context"
+	"errors"
+	"math"
 	"testing"
+
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/testrand"
 )
 
 func TestBinaryMatrixRank(t *testing.T) {
@@ -24,3 +29,100 @@ func TestBinaryMatrixRank(t *testing.T) {
 		}
 	})
 }
+
+// naiveRank computes the rank over GF(2) of a matrix of one bit per element.
+func naiveRank(m [][]uint8) int {
+	rank := 0
+	for col := 0; col < len(m[0]) && rank < len(m); col++ {
+		pivot := rank
+		for pivot < len(m) && m[pivot][col] == 0 {
+			pivot++
+		}
+		if pivot == len(m) {
+			continue
+		}
+		m[rank], m[pivot] = m[pivot], m[rank]
+		for i := range m {
+			if i != rank && m[i][col] == 1 {
+				for j := range m[i] {
+					m[i][j] ^= m[rank][j]
+				}
+			}
+		}
+		rank++
+	}
+	return rank
+}
+
+func TestBitMatrixRank(t *testing.T) {
+	data := testrand.Bytes(4096, 5)
+	// Sparse rows make rank deficiency likely
+	for i := 0; i < len(data)/2; i++ {
+		data[i] &= data[len(data)-1-i]
+	}
+	bits := BitSequenceFromBytes(data)
+
+	for _, size := range []struct{ M, Q int }{{32, 32}, {5, 7}, {7, 5}, {40, 70}, {3, 100}, {2, 2}} {
+		a := newBitMatrix(size.M, size.Q)
+		for k := 0; (k+1)*size.M*size.Q <= bits.Len(); k += 3 {
+			idx := k * size.M * size.Q
+			a.load(bits, idx)
+			ref := make([][]uint8, size.M)
+			for i := range ref {
+				ref[i] = make([]uint8, size.Q)
+				for j := range ref[i] {
+					ref[i][j] = bits.Bit(idx + i*size.Q + j)
+				}
+			}
+			if got, want := a.rank(), naiveRank(ref); got != want {
+				t.Fatalf("%dx%d matrix %d: expected rank %d, got %d", size.M, size.Q, k, want, got)
+			}
+		}
+	}
+}
+
+func TestBinaryRankProbability(t *testing.T) {
+	// SP 800-22 section 3.5
+	for r, want := range map[int]float64{32: 0.2888, 31: 0.5776} {
+		if got := binaryRankProbability(r, 32, 32); math.Abs(got-want) > 1e-4 {
+			t.Errorf("rank %d: expected %.4f, got %.4f", r, want, got)
+		}
+	}
+	for _, size := range []struct{ M, Q int }{{32, 32}, {6, 8}, {8, 6}, {2, 40}} {
+		sum := 0.0
+		for r := 0; r <= min(size.M, size.Q); r++ {
+			sum += binaryRankProbability(r, size.M, size.Q)
+		}
+		if math.Abs(sum-1) > 1e-12 {
+			t.Errorf("%dx%d: rank probabilities sum to %v", size.M, size.Q, sum)
+		}
+	}
+}
+
+func TestBinaryMatrixRankTestSequence(t *testing.T) {
+	data := testrand.Bytes(12500, 3)
+	bits := BitSequenceFromBytes(data)
+	ctx := context.Background()
+
+	o, err := BinaryMatrixRankTestSequence(ctx, bits, 6, 8)
+	if err != nil || !o.Status.Completed() || o.PValue <= 0 || o.PValue > 1 {
+		t.Fatalf("unexpected 6x8 outcome %+v (%v)", o, err)
+	}
+	def, _ := BinaryMatrixRankTest(data)
+	if o, _ := BinaryMatrixRankTestSequence(ctx, bits, DefaultMatrixRows, DefaultMatrixColumns); o.PValue != def {
+		t.Errorf("expected the default size to match, got %v and %v", o.PValue, def)
+	}
+
+	if o, _ := BinaryMatrixRankTestSequence(ctx, bits, 1, 32); o.Status != StatusError {
+		t.Errorf("expected an error for 1x32 matrices, got %+v", o)
+	}
+	if o, _ := BinaryMatrixRankTestSequence(ctx, bits, 400, 400); o.Status != StatusNotApplicable {
+		t.Errorf("expected not applicable for 400x400 matrices, got %+v", o)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := BinaryMatrixRankTestSequence(cancelled, bits, 6, 8); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package nist
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// BitSequence is an immutable sequence of n bits packed 64 per word, the first bit in
+// the most significant position of the first word. Unlike a []byte bitstream its length
+// need not be a multiple of 8. The pad bits after the last bit are always zero.
+type BitSequence struct {
+	words []uint64
+	n     int
+}
+
+// BitOrder selects the order in which the bits of each byte of a bitstream are read.
+type BitOrder int
+
+const (
+	// MSBFirst reads the most significant bit of each byte first, as the NIST STS does.
+	MSBFirst BitOrder = iota
+	// LSBFirst reads the least significant bit of each byte first.
+	LSBFirst
+)
+
+// String returns the name of the bit order.
+func (o BitOrder) String() string {
+	switch o {
+	case MSBFirst:
+		return "msb-first"
+	case LSBFirst:
+		return "lsb-first"
+	default:
+		return fmt.Sprintf("BitOrder(%d)", int(o))
+	}
+}
+
+// NewBitSequence returns the first n bits of data, taken most significant bit first
+// within each byte. Trailing bits beyond n are ignored.
+func NewBitSequence(data []byte, n int) (BitSequence, error) {
+	return NewBitSequenceOrder(data, n, MSBFirst)
+}
+
+// NewBitSequenceOrder is like NewBitSequence but reads the bits of each byte in the given order.
+func NewBitSequenceOrder(data []byte, n int, order BitOrder) (BitSequence, error) {
+	if n < 0 || n > len(data)*8 {
+		return BitSequence{}, fmt.Errorf("invalid bit length %d for %d bytes", n, len(data))
+	}
+	if order != MSBFirst && order != LSBFirst {
+		return BitSequence{}, fmt.Errorf("invalid bit order %v", order)
+	}
+	return packWords(data, n, order), nil
+}
+
+// BitSequenceFromBytes returns all len(data)*8 bits of data, most significant bit first.
+func BitSequenceFromBytes(data []byte) BitSequence {
+	return packWords(data, len(data)*8, MSBFirst)
+}
+
+// BitSequenceFromBits packs one bit per element of b; any non-zero element is a one.
+func BitSequenceFromBits(b []uint8) BitSequence {
+	words := make([]uint64, (len(b)+63)/64)
+	for i, v := range b {
+		if v != 0 {
+			words[i>>6] |= 1 << (63 - uint(i&63))
+		}
+	}
+	return BitSequence{words: words, n: len(b)}
+}
+
+// packWords packs the first n bits of data, read in the given order, into words and
+// clears the pad bits.
+func packWords(data []byte, n int, order BitOrder) BitSequence {
+	words := make([]uint64, (n+63)/64)
+	for i, b := range data[:(n+7)/8] {
+		if order == LSBFirst {
+			b = bits.Reverse8(b)
+		}
+		words[i>>3] |= uint64(b) << (56 - 8*uint(i&7))
+	}
+	if r := n & 63; r != 0 {
+		words[len(words)-1] &^= math.MaxUint64 >> uint(r)
+	}
+	return BitSequence{words: words, n: n}
+}
+
+// Len returns the number of bits in s.
+func (s BitSequence) Len() int {
+	return s.n
+}
+
+// Bit returns the bit (0 or 1) at position idx.
+func (s BitSequence) Bit(idx int) uint8 {
+	return uint8(s.words[idx>>6]>>(63-uint(idx&63))) & 1
+}
+
+// bitsAt returns the k <= 64 bits starting at idx right-aligned, the first of them in the
+// most significant position; idx+k must not exceed the length of s.
+func (s BitSequence) bitsAt(idx, k int) uint64 {
+	off := uint(idx & 63)
+	v := s.words[idx>>6] << off
+	if off+uint(k) > 64 {
+		v |= s.words[idx>>6+1] >> (64 - off)
+	}
+	return v >> (64 - uint(k))
+}
+
+// OnesCount returns the number of ones in s.
+func (s BitSequence) OnesCount() int {
+	ones := 0
+	for _, w := range s.words {
+		ones += bits.OnesCount64(w)
+	}
+	return ones
+}
+
+// Bytes returns s packed eight bits per byte, most significant bit first; the pad
+// bits of the last byte are zero.
+func (s BitSequence) Bytes() []byte {
+	out := make([]byte, (s.n+7)/8)
+	for i := range out {
+		out[i] = byte(s.words[i>>3] >> (56 - 8*uint(i&7)))
+	}
+	return out
+}
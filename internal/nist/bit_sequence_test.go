@@ -0,0 +1,196 @@
+package nist
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"math/bits"
+	"testing"
+
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/testrand"
+)
+
+func TestNewBitSequence(t *testing.T) {
+	data := []byte{0xA5, 0xFF}
+
+	for _, n := range []int{-1, 17} {
+		if _, err := NewBitSequence(data, n); err == nil {
+			t.Errorf("expected error for bit length %d", n)
+		}
+	}
+
+	seq, err := NewBitSequence(data, 11)
+	if err != nil {
+		t.Fatalf("NewBitSequence failed: %v", err)
+	}
+	if seq.Len() != 11 {
+		t.Fatalf("expected 11 bits, got %d", seq.Len())
+	}
+	want := []uint8{1, 0, 1, 0, 0, 1, 0, 1, 1, 1, 1}
+	for i, b := range want {
+		if seq.Bit(i) != b {
+			t.Errorf("bit %d: expected %d, got %d", i, b, seq.Bit(i))
+		}
+	}
+	// The five trailing ones of the second byte are pad bits
+	if got := seq.OnesCount(); got != 7 {
+		t.Errorf("expected 7 ones, got %d", got)
+	}
+	if got := seq.Bytes(); !bytes.Equal(got, []byte{0xA5, 0xE0}) {
+		t.Errorf("expected cleared pad bits, got %x", got)
+	}
+}
+
+func TestNewBitSequenceOrder(t *testing.T) {
+	seq, err := NewBitSequenceOrder([]byte{0x01, 0xF0}, 12, LSBFirst)
+	if err != nil {
+		t.Fatalf("NewBitSequenceOrder failed: %v", err)
+	}
+	want := []uint8{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	for i, b := range want {
+		if seq.Bit(i) != b {
+			t.Errorf("bit %d: expected %d, got %d", i, b, seq.Bit(i))
+		}
+	}
+	// The pad bits are the four high-order bits of the last byte
+	if got := seq.OnesCount(); got != 1 {
+		t.Errorf("expected 1 one, got %d", got)
+	}
+
+	if _, err := NewBitSequenceOrder([]byte{0}, 8, BitOrder(2)); err == nil {
+		t.Error("expected error for an invalid bit order")
+	}
+}
+
+func TestRunAllTestsBitOrder(t *testing.T) {
+	data := testrand.Bytes(MinBits/8, 7)
+	reversed := make([]byte, len(data))
+	for i, b := range data {
+		reversed[i] = bits.Reverse8(b)
+	}
+
+	lsb, err := RunAllTestsWithConfig(data, SuiteConfig{BitOrder: LSBFirst})
+	if err != nil {
+		t.Fatalf("RunAllTestsWithConfig failed: %v", err)
+	}
+	msb, err := RunAllTestsWithConfig(reversed, SuiteConfig{})
+	if err != nil {
+		t.Fatalf("RunAllTestsWithConfig failed: %v", err)
+	}
+	for i := range lsb {
+		if lsb[i].Status != msb[i].Status || lsb[i].PValue != msb[i].PValue || len(lsb[i].SubResults) != len(msb[i].SubResults) {
+			t.Errorf("%s: LSB-first input differs from the bit-reversed MSB-first input", lsb[i].Name)
+		}
+		for j := range lsb[i].SubResults {
+			if lsb[i].SubResults[j].PValue != msb[i].SubResults[j].PValue {
+				t.Errorf("%s/%s: sub-result differs", lsb[i].Name, lsb[i].SubResults[j].Name)
+			}
+		}
+	}
+
+	// Reading the same bytes MSB-first changes e.g. the runs and template statistics
+	orig, err := RunAllTests(data)
+	if err != nil {
+		t.Fatalf("RunAllTests failed: %v", err)
+	}
+	differs := false
+	for i := range orig {
+		if orig[i].PValue != lsb[i].PValue {
+			differs = true
+		}
+	}
+	if !differs {
+		t.Error("bit order had no effect")
+	}
+
+	if _, err := RunAllTestsWithConfig(data, SuiteConfig{BitOrder: 2}); !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("expected ErrInvalidParameter for an invalid bit order, got %v", err)
+	}
+}
+
+func TestBitSequenceFromBits(t *testing.T) {
+	data := make([]byte, 100)
+	for i := range data {
+		data[i] = byte(i * 37)
+	}
+	fromBytes := BitSequenceFromBytes(data)
+
+	b := make([]uint8, len(data)*8)
+	for i := range b {
+		b[i] = fromBytes.Bit(i)
+	}
+	fromBits := BitSequenceFromBits(b)
+
+	if fromBits.Len() != fromBytes.Len() || !bytes.Equal(fromBits.Bytes(), data) {
+		t.Fatalf("BitSequenceFromBits does not match BitSequenceFromBytes")
+	}
+	if fromBits.OnesCount() != fromBytes.OnesCount() {
+		t.Errorf("ones count mismatch: %d vs %d", fromBits.OnesCount(), fromBytes.OnesCount())
+	}
+}
+
+func TestBitSequenceBitsAt(t *testing.T) {
+	data := make([]byte, 24)
+	for i := range data {
+		data[i] = byte(i*73 + 5)
+	}
+	s := BitSequenceFromBytes(data)
+	for _, k := range []int{1, 7, 32, 64} {
+		for idx := 0; idx+k <= s.Len(); idx += 5 {
+			var want uint64
+			for j := range k {
+				want = want<<1 | uint64(s.Bit(idx+j))
+			}
+			if got := s.bitsAt(idx, k); got != want {
+				t.Fatalf("bitsAt(%d, %d): expected %#x, got %#x", idx, k, want, got)
+			}
+		}
+	}
+}
+
+func TestRunAllTestsSequenceUnaligned(t *testing.T) {
+	// Three pad bits; n has no large prime factors, which keeps the DFT fast
+	const n = 388773
+	const pad = byte(1)<<(8-n%8) - 1
+	data := testrand.Bytes((n+7)/8, 7)
+
+	// The pad bits of the last byte must not influence the results
+	zeroPad := append([]byte(nil), data...)
+	zeroPad[len(zeroPad)-1] &^= pad
+	onePad := append([]byte(nil), data...)
+	onePad[len(onePad)-1] |= pad
+
+	run := func(data []byte) []TestResult {
+		seq, err := NewBitSequence(data, n)
+		if err != nil {
+			t.Fatalf("NewBitSequence failed: %v", err)
+		}
+		results, err := RunAllTestsSequence(context.Background(), seq, SuiteConfig{})
+		if err != nil {
+			t.Fatalf("RunAllTestsSequence failed: %v", err)
+		}
+		return results
+	}
+	a, b := run(zeroPad), run(onePad)
+
+	if len(a) != 15 {
+		t.Fatalf("expected 15 results, got %d", len(a))
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name || a[i].Status != b[i].Status || a[i].PValue != b[i].PValue {
+			t.Errorf("%s: results depend on pad bits: %+v vs %+v", a[i].Name, a[i], b[i])
+		}
+	}
+
+	// The monobit statistic counts exactly n bits
+	seq, _ := NewBitSequence(onePad, n)
+	sObs := math.Abs(float64(2*seq.OnesCount()-n)) / math.Sqrt(n)
+	got, err := FrequencyTestSequence(context.Background(), seq)
+	if err != nil {
+		t.Fatalf("FrequencyTestSequence failed: %v", err)
+	}
+	if want := math.Erfc(sObs / math.Sqrt2); math.Abs(got.PValue-want) > 1e-12 {
+		t.Errorf("expected p-value %f, got %f", want, got.PValue)
+	}
+}
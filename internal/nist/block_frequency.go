@@ -1,31 +1,48 @@
 package nist
 
-import "gonum.org/v1/gonum/mathext"
+import (
+	"context"
+
+	"gonum.org/v1/gonum/mathext"
+)
 
 // BlockFrequencyTest implements the NIST Block Frequency test.
 // blockSize is the length of each block in bits (M in the NIST documentation).
 // It returns the p-value and whether it passes at Alpha.
 func BlockFrequencyTest(bitstream []byte, blockSize int) (float64, bool) {
-	n := len(bitstream) * 8
-	if blockSize <= 0 || n < blockSize {
-		return 0, false
+	o, _ := BlockFrequencyTestSequence(context.Background(), BitSequenceFromBytes(bitstream), blockSize)
+	return o.PValue, o.Passed()
+}
+
+// BlockFrequencyTestSequence runs the Block Frequency test on a BitSequence of any length.
+// The outcome's Values hold the proportion of ones pi_i of every block.
+func BlockFrequencyTestSequence(ctx context.Context, bits BitSequence, blockSize int) (Outcome, error) {
+	n := bits.n
+	if blockSize <= 0 {
+		return errorOutcome("invalid block length M=%d", blockSize), nil
+	}
+	if n < blockSize {
+		return notApplicable("insufficient bits for block length M=%d: got %d", blockSize, n), nil
 	}
 
 	N := n / blockSize // number of complete blocks
-	if N == 0 {
-		return 0, false
-	}
 
 	var sum float64
+	pis := make([]float64, N)
+	done := ctx.Done()
 	for block := 0; block < N; block++ {
+		if isDone(done) {
+			return Outcome{}, ctx.Err()
+		}
 		blockSum := 0
 		offset := block * blockSize
 		for j := 0; j < blockSize; j++ {
-			bit := bitAt(bitstream, offset+j)
+			bit := bits.Bit(offset + j)
 			blockSum += int(bit)
 		}
 
 		pi := float64(blockSum) / float64(blockSize)
+		pis[block] = pi
 		v := pi - 0.5
 		sum += v * v
 	}
@@ -33,5 +50,7 @@ func BlockFrequencyTest(bitstream []byte, blockSize int) (float64, bool) {
 	chiSquared := 4 * float64(blockSize) * sum
 	pValue := mathext.GammaIncRegComp(float64(N)/2.0, chiSquared/2.0)
 
-	return pValue, pValue >= Alpha
+	o := statisticOutcome(pValue, chiSquared)
+	o.Values = pis
+	return o, nil
 }
@@ -1,25 +1,31 @@
 package nist
 
-import "math"
+import (
+	"context"
+	"errors"
+	"math"
+)
 
 // Alpha is the default significance level used by the NIST SP800-22 tests.
 const Alpha = 0.01
 
-// bitAt returns the bit (0 or 1) at position idx in big-endian bit order.
-func bitAt(data []byte, idx int) uint8 {
-	byteIdx := idx >> 3
-	bitIdx := 7 - (idx & 7)
-	return (data[byteIdx] >> bitIdx) & 1
-}
+// ErrNotApplicable is returned (wrapped) when a test's preconditions are not met by
+// the input sequence, e.g. too few cycles for the Random Excursions tests.
+var ErrNotApplicable = errors.New("test not applicable")
+
+// ctxCheckInterval is the number of loop iterations between cancellation checks in
+// loops without a natural block structure.
+const ctxCheckInterval = 1 << 16
 
-// expandBits converts a byte slice to a slice of individual bits (0 or 1).
-func expandBits(bitstream []byte) []uint8 {
-	n := len(bitstream) * 8
-	bits := make([]uint8, n)
-	for i := 0; i < n; i++ {
-		bits[i] = bitAt(bitstream, i)
+// isDone reports, without blocking, whether done has been closed.
+// A nil channel (context.Background) is never done.
+func isDone(done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
 	}
-	return bits
 }
 
 // normal computes the normal (Gaussian) cumulative distribution function.
@@ -27,34 +33,79 @@ func normal(x float64) float64 {
 	return 0.5 * math.Erfc(-x/math.Sqrt2)
 }
 
-// psi2 computes the psi_m statistic used by the Serial and Approximate Entropy tests.
-func psi2(bits []uint8, m int) float64 {
-	if m <= 0 {
-		return 0
+// sidakPValue combines the smallest of k p-values into a single p-value with the Sidak
+// correction 1-(1-minP)^k. A multi-valued test whose statistics are independent then
+// fails at Alpha with probability Alpha on random data, and with at most that when they
+// are positively dependent; the plain minimum would fail with probability 1-(1-Alpha)^k.
+func sidakPValue(minP float64, k int) float64 {
+	if k <= 1 {
+		return minP
 	}
+	return -math.Expm1(float64(k) * math.Log1p(-minP))
+}
 
-	n := len(bits)
-	powLen := (1 << (m + 1)) - 1
-	P := make([]int, powLen)
+// patternCounts returns how often each m-bit pattern occurs at the n positions of bits,
+// which wraps around so that the last m-1 positions continue with its first bits. The
+// counts are indexed by the value of the pattern, its first bit most significant. The
+// packed words are read in a single pass with a rolling window; it returns ctx.Err() if
+// ctx is done before the pass completes.
+func patternCounts(ctx context.Context, bits BitSequence, m int) ([]int, error) {
+	n := bits.n
+	counts := make([]int, 1<<m)
+	mask := uint64(1)<<m - 1
 
-	for i := 0; i < n; i++ {
-		k := 1
-		for j := 0; j < m; j++ {
-			if bits[(i+j)%n] == 0 {
-				k *= 2
-			} else {
-				k = 2*k + 1
-			}
+	// The window ending at bit j of the wrapped sequence starts at j-m+1; it is counted
+	// from j = m-1 on.
+	var w uint64
+	j := 0
+	for ; j < min(m-1, n); j++ {
+		w = w<<1 | uint64(bits.Bit(j))
+	}
+	done := ctx.Done()
+	for j < n {
+		if isDone(done) {
+			return nil, ctx.Err()
+		}
+		word := bits.words[j>>6] << uint(j&63)
+		k := min(64-j&63, n-j)
+		for range k {
+			w = (w<<1 | word>>63) & mask
+			counts[w]++
+			word <<= 1
+		}
+		j += k
+	}
+	for ; j < n+m-1; j++ {
+		w = (w<<1 | uint64(bits.Bit(j%n))) & mask
+		if j >= m-1 {
+			counts[w]++
 		}
-		P[k-1]++
+	}
+	return counts, nil
+}
+
+// marginalCounts folds the counts of patternCounts for m bits into those for m-1 bits,
+// a pattern of m-1 bits being the prefix of two patterns of m bits. The folded counts
+// overwrite the first half of counts.
+func marginalCounts(counts []int) []int {
+	half := counts[:len(counts)/2]
+	for i := range half {
+		half[i] = counts[2*i] + counts[2*i+1]
+	}
+	return half
+}
+
+// psi2 computes the psi_m statistic used by the Serial test from the pattern counts
+// of m bits in a sequence of n bits.
+func psi2(counts []int, n int) float64 {
+	if len(counts) <= 1 {
+		return 0
 	}
 
 	sum := 0.0
-	start := (1 << m) - 1
-	end := (1 << (m + 1)) - 1
-	for i := start; i < end; i++ {
-		sum += math.Pow(float64(P[i]), 2)
+	for _, c := range counts {
+		sum += math.Pow(float64(c), 2)
 	}
 
-	return sum*math.Pow(2, float64(m))/float64(n) - float64(n)
+	return sum*float64(len(counts))/float64(n) - float64(n)
 }
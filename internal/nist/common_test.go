@@ -0,0 +1,85 @@
+package nist
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/testrand"
+)
+
+func TestPatternCounts(t *testing.T) {
+	data := testrand.Bytes(300, 9)
+	full := BitSequenceFromBytes(data)
+
+	for _, n := range []int{1, 3, 64, 65, 1000, full.Len()} {
+		b := make([]uint8, n)
+		for i := range b {
+			b[i] = full.Bit(i)
+		}
+		bits := BitSequenceFromBits(b)
+		for _, m := range []int{0, 1, 2, 5, 10, 17} {
+			want := make([]int, 1<<m)
+			for i := 0; i < n; i++ {
+				v := 0
+				for j := 0; j < m; j++ {
+					v = v<<1 | int(b[(i+j)%n])
+				}
+				want[v]++
+			}
+			got, err := patternCounts(context.Background(), bits, m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, want) {
+				t.Fatalf("n=%d m=%d: pattern counts differ from a direct scan", n, m)
+			}
+			if m > 0 {
+				shorter, _ := patternCounts(context.Background(), bits, m-1)
+				if !slices.Equal(marginalCounts(got), shorter) {
+					t.Fatalf("n=%d m=%d: marginal counts differ from the counts of m-1 bits", n, m)
+				}
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := patternCounts(ctx, full, 4); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestSidakPValue(t *testing.T) {
+	if p := sidakPValue(0.3, 1); p != 0.3 {
+		t.Errorf("expected a single p-value unchanged, got %v", p)
+	}
+	if p := sidakPValue(0.005374, 148); math.Abs(p-0.549545) > 1e-6 {
+		t.Errorf("unexpected combined p-value %.6f", p)
+	}
+	// The per-statistic threshold 1-(1-Alpha)^(1/k) maps back to Alpha.
+	if p := sidakPValue(-math.Expm1(math.Log1p(-Alpha)/148), 148); math.Abs(p-Alpha) > 1e-12 {
+		t.Errorf("expected Alpha at the corrected threshold, got %v", p)
+	}
+
+	// With 148 independent uniform p-values the minimum falls below Alpha in about 77%
+	// of the trials, the combined p-value in about 1%.
+	const trials, k = 4000, 148
+	rng := rand.New(rand.NewPCG(1, 2))
+	failed := 0
+	for range trials {
+		minP := 1.0
+		for range k {
+			minP = min(minP, rng.Float64())
+		}
+		if sidakPValue(minP, k) < Alpha {
+			failed++
+		}
+	}
+	if rate := float64(failed) / trials; rate > 2*Alpha {
+		t.Errorf("false failure rate %.4f, want about %v", rate, Alpha)
+	}
+}
@@ -1,30 +1,79 @@
 package nist
 
-import "math"
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// CusumDirection is the outcome of the Cumulative Sums test in one direction.
+type CusumDirection struct {
+	// Z is the largest absolute partial sum max |S_k| of the +/-1 walk.
+	Z      int
+	PValue float64
+	Passed bool
+}
+
+// CumulativeSumsResult is the detailed outcome of the Cumulative Sums test, which the
+// NIST STS reports as two p-values.
+type CumulativeSumsResult struct {
+	Forward CusumDirection
+	Reverse CusumDirection
+}
+
+// MinPValue returns the smaller of the forward and reverse p-values.
+func (r CumulativeSumsResult) MinPValue() float64 {
+	return math.Min(r.Forward.PValue, r.Reverse.PValue)
+}
+
+// CombinedPValue returns the Sidak-corrected minimum 1-(1-p_min)^2 over both directions, the p-value that
+// decides whether the test as a whole passes.
+func (r CumulativeSumsResult) CombinedPValue() float64 {
+	return sidakPValue(r.MinPValue(), 2)
+}
 
 // CumulativeSumsTest implements the NIST Cumulative Sums (Cusum) test.
-// It returns the minimum p-value across forward and reverse runs and whether it passes at Alpha.
+// It returns the Sidak-corrected minimum of the forward and reverse p-values and whether
+// it passes at Alpha.
 func CumulativeSumsTest(bitstream []byte) (float64, bool) {
-	bits := expandBits(bitstream)
-	n := len(bits)
-	if n == 0 {
+	res, err := CumulativeSumsTestSequence(context.Background(), BitSequenceFromBytes(bitstream))
+	if err != nil {
 		return 0, false
 	}
 
-	pForward := cumulativeSums(bits, false)
-	pReverse := cumulativeSums(bits, true)
-	pValue := math.Min(pForward, pReverse)
+	p := res.CombinedPValue()
+	return p, p >= Alpha
+}
+
+// CumulativeSumsTestSequence runs the Cumulative Sums test on a BitSequence of any length
+// and reports the statistic z and p-value of the forward and the reverse walk. It returns
+// an error wrapping ErrNotApplicable for an empty bitstream.
+func CumulativeSumsTestSequence(ctx context.Context, bits BitSequence) (CumulativeSumsResult, error) {
+	if bits.n == 0 {
+		return CumulativeSumsResult{}, fmt.Errorf("%w: empty bitstream", ErrNotApplicable)
+	}
+
+	// Each pass is a single linear scan; check for cancellation between them.
+	if err := ctx.Err(); err != nil {
+		return CumulativeSumsResult{}, err
+	}
+	forward := cumulativeSums(bits, false)
+	if err := ctx.Err(); err != nil {
+		return CumulativeSumsResult{}, err
+	}
+	reverse := cumulativeSums(bits, true)
 
-	return pValue, pValue >= Alpha
+	return CumulativeSumsResult{Forward: forward, Reverse: reverse}, nil
 }
 
-func cumulativeSums(bits []uint8, reverse bool) float64 {
-	n := len(bits)
+// cumulativeSums runs the test on the walk over bits in one direction.
+func cumulativeSums(bits BitSequence, reverse bool) CusumDirection {
+	n := bits.n
 	var sup, inf, sum float64
 
 	if reverse {
 		for i := n - 1; i >= 0; i-- {
-			if bits[i] == 1 {
+			if bits.Bit(i) == 1 {
 				sum++
 			} else {
 				sum--
@@ -38,7 +87,7 @@ func cumulativeSums(bits []uint8, reverse bool) float64 {
 		}
 	} else {
 		for i := 0; i < n; i++ {
-			if bits[i] == 1 {
+			if bits.Bit(i) == 1 {
 				sum++
 			} else {
 				sum--
@@ -71,5 +120,5 @@ func cumulativeSums(bits []uint8, reverse bool) float64 {
 		sum1 += term2
 	}
 
-	return sum1
+	return CusumDirection{Z: int(z), PValue: sum1, Passed: sum1 >= Alpha}
 }
@@ -1,6 +1,8 @@
 package nist
 
 import (
+	"context"
+	"errors"
 	"testing"
 )
 
@@ -23,3 +25,26 @@ func TestCumulativeSums(t *testing.T) {
 		}
 	})
 }
+
+func TestCumulativeSumsTestSequence(t *testing.T) {
+	// 1011010111 and six zeros: the walk reaches 4 forward and -6 in reverse
+	res, err := CumulativeSumsTestSequence(context.Background(), BitSequenceFromBytes([]byte{0b10110101, 0b11000000}))
+	if err != nil {
+		t.Fatalf("CumulativeSumsTestSequence failed: %v", err)
+	}
+	if res.Forward.Z != 4 || res.Reverse.Z != 6 {
+		t.Errorf("unexpected maximum excursions: %+v", res)
+	}
+	if p, _ := CumulativeSumsTest([]byte{0b10110101, 0b11000000}); p != res.CombinedPValue() || p < res.MinPValue() {
+		t.Errorf("p-value %.6f is not the combined minimum of %+v", p, res)
+	}
+
+	if _, err := CumulativeSumsTestSequence(context.Background(), BitSequenceFromBytes(nil)); !errors.Is(err, ErrNotApplicable) {
+		t.Errorf("expected ErrNotApplicable for an empty bitstream, got %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := CumulativeSumsTestSequence(ctx, BitSequenceFromBytes([]byte{0xAA})); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
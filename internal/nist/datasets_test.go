@@ -0,0 +1,160 @@
+package nist
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/datasets"
+)
+
+// datasetPValues are the p-values that SP 800-22 Appendix B reports for the first
+// 1,000,000 bits of the STS sample data files with the default parameters. results holds
+// the single-valued tests; sub holds the individual statistics of the multi-valued tests,
+// keyed by sub-result name (the template name for the Non-overlapping Template test).
+// Appendix B lists a single Serial p-value per dataset, that of del psi^2_m (p_value1).
+var datasetPValues = map[string]struct {
+	results map[string]float64
+	sub     map[string]float64
+}{
+	"data.e": {
+		results: map[string]float64{
+			"frequency_monobit":          0.953749,
+			"block_frequency":            0.211072,
+			"runs":                       0.561917,
+			"longest_run":                0.718945,
+			"binary_matrix_rank":         0.306156,
+			"discrete_fourier_transform": 0.847187,
+			"overlapping_template":       0.110434,
+			"universal_statistical":      0.282568,
+			"approximate_entropy":        0.700073,
+			"linear_complexity":          0.826335,
+		},
+		sub: map[string]float64{
+			"000000001":                      0.078790,
+			"cumulative_sums/forward":        0.669887,
+			"cumulative_sums/reverse":        0.724266,
+			"random_excursions/x=1":          0.786868,
+			"random_excursions_variant/x=-1": 0.826009,
+			"serial/p_value1":                0.766182,
+		},
+	},
+	"data.pi": {
+		results: map[string]float64{
+			"frequency_monobit":          0.578211,
+			"block_frequency":            0.380615,
+			"runs":                       0.419268,
+			"longest_run":                0.024390,
+			"binary_matrix_rank":         0.083553,
+			"discrete_fourier_transform": 0.010186,
+			"overlapping_template":       0.296897,
+			"universal_statistical":      0.669012,
+			"approximate_entropy":        0.361595,
+			"linear_complexity":          0.255475,
+		},
+		sub: map[string]float64{
+			"000000001":                      0.165757,
+			"cumulative_sums/forward":        0.628308,
+			"cumulative_sums/reverse":        0.663369,
+			"random_excursions/x=1":          0.844143,
+			"random_excursions_variant/x=-1": 0.760966,
+			"serial/p_value1":                0.143005,
+		},
+	},
+	"data.sqrt2": {
+		results: map[string]float64{
+			"frequency_monobit":          0.811881,
+			"block_frequency":            0.833222,
+			"runs":                       0.313427,
+			"longest_run":                0.012117,
+			"binary_matrix_rank":         0.823810,
+			"discrete_fourier_transform": 0.581909,
+			"overlapping_template":       0.791982,
+			"universal_statistical":      0.130805,
+			"approximate_entropy":        0.884740,
+			"linear_complexity":          0.317127,
+		},
+		sub: map[string]float64{
+			"000000001":                      0.569461,
+			"cumulative_sums/forward":        0.879009,
+			"cumulative_sums/reverse":        0.957206,
+			"random_excursions/x=1":          0.216235,
+			"random_excursions_variant/x=-1": 0.566118,
+			"serial/p_value1":                0.861925,
+		},
+	},
+	"data.sqrt3": {
+		results: map[string]float64{
+			"frequency_monobit":          0.610051,
+			"block_frequency":            0.473961,
+			"runs":                       0.261123,
+			"longest_run":                0.446726,
+			"binary_matrix_rank":         0.314498,
+			"discrete_fourier_transform": 0.776046,
+			"overlapping_template":       0.082716,
+			"universal_statistical":      0.165981,
+			"approximate_entropy":        0.180481,
+			"linear_complexity":          0.346469,
+		},
+		sub: map[string]float64{
+			"000000001":                      0.532235,
+			"cumulative_sums/forward":        0.917121,
+			"cumulative_sums/reverse":        0.689519,
+			"random_excursions/x=1":          0.783283,
+			"random_excursions_variant/x=-1": 0.155066,
+			"serial/p_value1":                0.157500,
+		},
+	},
+}
+
+// TestSampleDatasets runs the suite on the computed STS sample data and compares all
+// p-values with the reference values, so the engine is validated without the STS files.
+func TestSampleDatasets(t *testing.T) {
+	if testing.Short() {
+		t.Skip("computing 1,000,000 bits of every dataset takes several seconds")
+	}
+
+	const n = 1_000_000
+	for _, name := range datasets.Names() {
+		t.Run(name, func(t *testing.T) {
+			want, ok := datasetPValues[name]
+			if !ok {
+				t.Fatalf("no reference p-values for %s", name)
+			}
+			data, err := datasets.Bits(name, n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			results, err := RunAllTestsSequence(context.Background(), BitSequenceFromBytes(data), SuiteConfig{})
+			if err != nil {
+				t.Fatalf("RunAllTestsSequence failed: %v", err)
+			}
+			if len(results) != len(suiteTests) {
+				t.Fatalf("expected %d results, got %d", len(suiteTests), len(results))
+			}
+
+			got := make(map[string]float64)
+			sub := make(map[string]float64)
+			for _, r := range results {
+				got[r.Name] = r.PValue
+				for _, s := range r.SubResults {
+					if r.Name == "non_overlapping_template" {
+						sub[s.Name] = s.PValue
+					} else {
+						sub[r.Name+"/"+s.Name] = s.PValue
+					}
+				}
+			}
+			for name, p := range want.results {
+				if r, ok := got[name]; !ok || math.Abs(r-p) > 1e-6 {
+					t.Errorf("%s: expected p-value %.6f, got %.6f (present: %v)", name, p, r, ok)
+				}
+			}
+			for key, p := range want.sub {
+				if got, ok := sub[key]; !ok || math.Abs(got-p) > 1e-6 {
+					t.Errorf("%s: expected p-value %.6f, got %.6f (present: %v)", key, p, got, ok)
+				}
+			}
+		})
+	}
+}
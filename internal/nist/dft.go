@@ -1,6 +1,7 @@
 package nist
 
 import (
+	"context"
 	"math"
 	"math/cmplx"
 
@@ -10,38 +11,52 @@ import (
 // DiscreteFourierTransformTest implements the NIST Spectral (FFT) test.
 // It returns the p-value and whether it passes at Alpha.
 func DiscreteFourierTransformTest(bitstream []byte) (float64, bool) {
-	n := len(bitstream) * 8
+	o, _ := DiscreteFourierTransformTestSequence(context.Background(), BitSequenceFromBytes(bitstream))
+	return o.PValue, o.Passed()
+}
+
+// DiscreteFourierTransformTestSequence runs the Spectral test on a BitSequence of any length.
+// The outcome's Statistic is d; its Values hold N_0, the expected number of peaks below
+// the threshold T, and N_1, the observed number.
+func DiscreteFourierTransformTestSequence(ctx context.Context, bits BitSequence) (Outcome, error) {
+	n := bits.n
 	if n == 0 {
-		return 0, false
+		return notApplicable("empty bitstream"), nil
 	}
 
 	series := make([]float64, n)
 	for i := 0; i < n; i++ {
-		if bitAt(bitstream, i) == 1 {
+		if bits.Bit(i) == 1 {
 			series[i] = 1
 		} else {
 			series[i] = -1
 		}
 	}
 
+	// The transform itself cannot be interrupted; check before and after it.
+	done := ctx.Done()
+	if isDone(done) {
+		return Outcome{}, ctx.Err()
+	}
 	fft := fourier.NewFFT(n)
 	coeffs := fft.Coefficients(nil, series)
-
-	magnitudes := make([]float64, n/2)
-	for i := 0; i < n/2; i++ {
-		magnitudes[i] = cmplx.Abs(coeffs[i])
+	if isDone(done) {
+		return Outcome{}, ctx.Err()
 	}
 
 	upperBound := math.Sqrt(2.995732274 * float64(n))
 	count := 0
-	for _, m := range magnitudes {
-		if m < upperBound {
+	for _, c := range coeffs[:n/2] {
+		if cmplx.Abs(c) < upperBound {
 			count++
 		}
 	}
 
-	d := (float64(count) - 0.95*float64(n)/2.0) / math.Sqrt(float64(n)/4.0*0.95*0.05)
+	n0 := 0.95 * float64(n) / 2.0
+	d := (float64(count) - n0) / math.Sqrt(float64(n)/4.0*0.95*0.05)
 	pValue := math.Erfc(math.Abs(d) / math.Sqrt2)
 
-	return pValue, pValue >= Alpha
+	o := statisticOutcome(pValue, d)
+	o.Values = []float64{n0, float64(count)}
+	return o, nil
 }
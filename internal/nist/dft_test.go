@@ -1,6 +1,8 @@
 package nist
 
 import (
+	"context"
+	"runtime"
 	"testing"
 )
 
@@ -18,4 +20,24 @@ func TestDiscreteFourierTransform(t *testing.T) {
 			t.Fatalf("expected periodic pattern to fail DFT test, got p=%.6f", p)
 		}
 	})
+
+	t.Run("memory_within_estimate", func(t *testing.T) {
+		data := make([]byte, 1<<17)
+		for i := range data {
+			data[i] = byte(i * 131)
+		}
+		seq := BitSequenceFromBytes(data)
+		n := seq.Len()
+
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		if _, err := DiscreteFourierTransformTestSequence(context.Background(), seq); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		runtime.ReadMemStats(&after)
+
+		if got := after.TotalAlloc - before.TotalAlloc; got > EstimateMemory(n) {
+			t.Fatalf("allocated %d bytes for %d bits, estimate is %d", got, n, EstimateMemory(n))
+		}
+	})
 }
@@ -1,26 +1,36 @@
 package nist
 
 import (
+	"context"
 	"math"
-	"math/bits"
 )
 
 // FrequencyTest implements the NIST Monobit (Frequency) test.
 // It returns the p-value and whether it passes at Alpha.
 func FrequencyTest(bitstream []byte) (float64, bool) {
-	n := len(bitstream) * 8
+	o, _ := FrequencyTestSequence(context.Background(), BitSequenceFromBytes(bitstream))
+	return o.PValue, o.Passed()
+}
+
+// FrequencyTestSequence runs the Frequency test on a BitSequence of any length.
+// The outcome's Values hold S_n, the sum of the +/-1 digits.
+func FrequencyTestSequence(ctx context.Context, bits BitSequence) (Outcome, error) {
+	n := bits.n
 	if n == 0 {
-		return 0, false
+		return notApplicable("empty bitstream"), nil
 	}
 
-	ones := 0
-	for _, b := range bitstream {
-		ones += bits.OnesCount8(b)
+	// A single word-wise pass; check for cancellation before it.
+	if err := ctx.Err(); err != nil {
+		return Outcome{}, err
 	}
+	ones := bits.OnesCount()
 
 	sum := float64(2*ones - n)
 	sObs := math.Abs(sum) / math.Sqrt(float64(n))
 	pValue := math.Erfc(sObs / math.Sqrt2)
 
-	return pValue, pValue >= Alpha
+	o := statisticOutcome(pValue, sObs)
+	o.Values = []float64{sum}
+	return o, nil
 }
@@ -0,0 +1,223 @@
+package nist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/datasets"
+)
+
+// goldenTolerance is the default absolute tolerance of the golden-vector comparisons;
+// a vector may set its own.
+var goldenTolerance = flag.Float64("golden.tolerance", 1e-6, "absolute tolerance of the golden-vector comparisons")
+
+// goldenDir holds one file of golden vectors per test, named after the test.
+const goldenDir = "testdata/golden"
+
+// goldenVector is a single conformance case: an input, the parameters that differ from
+// the STS defaults and the expected results.
+type goldenVector struct {
+	Name string `json:"name"`
+	// Source names the publication the expected values are taken from and which of them,
+	// if any, are regression values of this implementation.
+	Source    string       `json:"source"`
+	Input     goldenInput  `json:"input"`
+	Params    goldenParams `json:"params"`
+	Tolerance float64      `json:"tolerance"`
+	Want      goldenWant   `json:"want"`
+}
+
+// goldenInput is either a literal bit string or a prefix of an STS sample dataset.
+type goldenInput struct {
+	Bits    string `json:"bits"`
+	Dataset string `json:"dataset"`
+	Length  int    `json:"length"`
+}
+
+type goldenParams struct {
+	BlockFrequencyBlockLength     int `json:"block_frequency_block_length"`
+	NonOverlappingTemplateLength  int `json:"non_overlapping_template_length"`
+	NonOverlappingTemplateBlocks  int `json:"non_overlapping_template_blocks"`
+	OverlappingTemplateLength     int `json:"overlapping_template_length"`
+	ApproximateEntropyBlockLength int `json:"approximate_entropy_block_length"`
+	SerialBlockLength             int `json:"serial_block_length"`
+	LinearComplexityBlockLength   int `json:"linear_complexity_block_length"`
+}
+
+// goldenWant lists the expected values; absent values are not compared.
+type goldenWant struct {
+	Status    string   `json:"status"`
+	PValue    *float64 `json:"p_value"`
+	Statistic *float64 `json:"statistic"`
+	// Counts and Values are the intermediate statistics of the outcome; null values are
+	// those a worked example does not publish.
+	Counts     []int             `json:"counts"`
+	Values     []*float64        `json:"values"`
+	SubResults []goldenSubResult `json:"sub_results"`
+}
+
+type goldenSubResult struct {
+	Name      string   `json:"name"`
+	PValue    *float64 `json:"p_value"`
+	Statistic *float64 `json:"statistic"`
+	Counts    []int    `json:"counts"`
+	// Values are compared like the statistic.
+	Values []float64 `json:"values"`
+}
+
+// config returns the STS defaults overridden by the non-zero parameters.
+func (p goldenParams) config() SuiteConfig {
+	cfg := DefaultSuiteConfig()
+	override := func(dst *int, v int) {
+		if v != 0 {
+			*dst = v
+		}
+	}
+	override(&cfg.BlockFrequencyBlockLength, p.BlockFrequencyBlockLength)
+	override(&cfg.NonOverlappingTemplateLength, p.NonOverlappingTemplateLength)
+	override(&cfg.NonOverlappingTemplateBlocks, p.NonOverlappingTemplateBlocks)
+	override(&cfg.OverlappingTemplateLength, p.OverlappingTemplateLength)
+	override(&cfg.ApproximateEntropyBlockLength, p.ApproximateEntropyBlockLength)
+	override(&cfg.SerialBlockLength, p.SerialBlockLength)
+	override(&cfg.LinearComplexityBlockLength, p.LinearComplexityBlockLength)
+	return cfg
+}
+
+// goldenInputs caches the dataset prefixes, which several vectors share.
+var goldenInputs = make(map[goldenInput]BitSequence)
+
+func (in goldenInput) load() (BitSequence, error) {
+	if in.Dataset == "" {
+		b := make([]uint8, len(in.Bits))
+		for i, c := range in.Bits {
+			if c != '0' && c != '1' {
+				return BitSequence{}, fmt.Errorf("invalid bit %q at offset %d", c, i)
+			}
+			b[i] = uint8(c - '0')
+		}
+		return BitSequenceFromBits(b), nil
+	}
+	if seq, ok := goldenInputs[in]; ok {
+		return seq, nil
+	}
+	data, err := datasets.Bits(in.Dataset, in.Length)
+	if err != nil {
+		return BitSequence{}, err
+	}
+	seq, err := NewBitSequence(data, in.Length)
+	if err != nil {
+		return BitSequence{}, err
+	}
+	goldenInputs[in] = seq
+	return seq, nil
+}
+
+func loadGoldenVectors(name string) ([]goldenVector, error) {
+	data, err := os.ReadFile(filepath.Join(goldenDir, name+".json")) //nolint:gosec // fixed test data below testdata
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var vectors []goldenVector
+	if err := dec.Decode(&vectors); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return vectors, nil
+}
+
+// TestGoldenVectors checks every test against its vectors in testdata/golden: the
+// worked examples of SP 800-22 section 2 with the intermediate values they publish and
+// the Appendix B results of the STS sample data, including the statistics and counts of
+// the individual templates and states.
+// go test -run TestGoldenVectors -golden.tolerance 1e-4 relaxes the comparisons.
+func TestGoldenVectors(t *testing.T) {
+	for _, st := range suiteTests {
+		t.Run(st.name, func(t *testing.T) {
+			vectors, err := loadGoldenVectors(st.name)
+			if err != nil {
+				t.Fatalf("load golden vectors: %v", err)
+			}
+			if len(vectors) == 0 {
+				t.Fatal("no golden vectors")
+			}
+			for _, v := range vectors {
+				t.Run(v.Name, func(t *testing.T) {
+					if testing.Short() && v.Input.Length >= 1_000_000 {
+						t.Skip("skipping a 1,000,000-bit vector in short mode")
+					}
+					bits, err := v.Input.load()
+					if err != nil {
+						t.Fatalf("load input: %v", err)
+					}
+					in := suiteInput{bits: bits, cfg: v.Params.config()}
+					o, subResults, err := st.run(context.Background(), &in)
+					if err != nil {
+						t.Fatalf("run: %v", err)
+					}
+					checkGolden(t, v, o, subResults)
+				})
+			}
+		})
+	}
+}
+
+func checkGolden(t *testing.T, v goldenVector, o Outcome, subResults []SubResult) {
+	t.Helper()
+	tol := *goldenTolerance
+	if v.Tolerance > 0 {
+		tol = v.Tolerance
+	}
+	check := func(what string, want *float64, got float64) {
+		if want != nil && !(math.Abs(got-*want) <= tol) {
+			t.Errorf("%s: expected %.6f, got %.6f (tolerance %g, source: %s)", what, *want, got, tol, v.Source)
+		}
+	}
+
+	if v.Want.Status != "" && o.Status.String() != v.Want.Status {
+		t.Errorf("expected status %s, got %s (%s)", v.Want.Status, o.Status, o.Reason)
+	}
+	check("p-value", v.Want.PValue, o.PValue)
+	check("statistic", v.Want.Statistic, o.Statistic)
+	if v.Want.Counts != nil && fmt.Sprint(o.Counts) != fmt.Sprint(v.Want.Counts) {
+		t.Errorf("expected counts %v, got %v", v.Want.Counts, o.Counts)
+	}
+	if v.Want.Values != nil && len(o.Values) != len(v.Want.Values) {
+		t.Errorf("expected %d values, got %v", len(v.Want.Values), o.Values)
+	} else {
+		for i, want := range v.Want.Values {
+			check(fmt.Sprintf("value %d", i), want, o.Values[i])
+		}
+	}
+
+	byName := make(map[string]SubResult, len(subResults))
+	for _, s := range subResults {
+		byName[s.Name] = s
+	}
+	for _, want := range v.Want.SubResults {
+		got, ok := byName[want.Name]
+		if !ok {
+			t.Errorf("missing sub-result %s", want.Name)
+			continue
+		}
+		check(want.Name+" p-value", want.PValue, got.PValue)
+		check(want.Name+" statistic", want.Statistic, got.Statistic)
+		if want.Counts != nil && fmt.Sprint(got.Counts) != fmt.Sprint(want.Counts) {
+			t.Errorf("%s: expected counts %v, got %v", want.Name, want.Counts, got.Counts)
+		}
+		if want.Values != nil && len(got.Values) != len(want.Values) {
+			t.Errorf("%s: expected %d values, got %v", want.Name, len(want.Values), got.Values)
+			continue
+		}
+		for i := range want.Values {
+			check(fmt.Sprintf("%s value %d", want.Name, i), &want.Values[i], got.Values[i])
+		}
+	}
+}
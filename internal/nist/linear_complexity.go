@@ -1,100 +1,202 @@
 package nist
 
 import (
+	"context"
+	"fmt"
 	"math"
+	"math/bits"
 
 	"gonum.org/v1/gonum/mathext"
 )
 
+const (
+	// MinLinearComplexityBlockLength and MaxLinearComplexityBlockLength bound the block
+	// length M of the Linear Complexity test (SP 800-22 section 2.10.7).
+	MinLinearComplexityBlockLength = 500
+	MaxLinearComplexityBlockLength = 5000
+	// MinLinearComplexityBlocks is the minimum number of blocks N.
+	MinLinearComplexityBlocks = 200
+)
+
+// linearComplexityPi are the probabilities of the K+1 = 7 classes of T_i.
+var linearComplexityPi = [7]float64{0.01047, 0.03125, 0.125, 0.5, 0.25, 0.0625, 0.020833}
+
+// LinearComplexityResult is the detailed outcome of the Linear Complexity test.
+type LinearComplexityResult struct {
+	BlockLength int // M
+	NumBlocks   int // N
+	// Complexities holds L_i, the linear complexity of each block.
+	Complexities []int
+	// Nu holds the number of blocks in each class of T_i, from T_i <= -2.5 to T_i > 2.5.
+	Nu        [7]int
+	ChiSquare float64
+	PValue    float64
+	Passed    bool
+}
+
 // LinearComplexityTest implements the NIST Linear Complexity test.
 // It returns the p-value and whether it passes at Alpha.
 func LinearComplexityTest(bitstream []byte, M int) (float64, bool) {
-	bits := expandBits(bitstream)
-	n := len(bits)
+	res, err := LinearComplexityTestSequence(context.Background(), BitSequenceFromBytes(bitstream), M)
+	if err != nil {
+		return 0, false
+	}
+	return res.PValue, res.Passed
+}
+
+// LinearComplexityTestSequence runs the Linear Complexity test on a BitSequence of any
+// length with block length M and reports the linear complexity of every block and the
+// histogram nu of T_i. M must lie in 500..5000; it returns an error wrapping
+// ErrNotApplicable when the sequence has fewer than 200 blocks.
+func LinearComplexityTestSequence(ctx context.Context, bits BitSequence, M int) (LinearComplexityResult, error) {
+	if M < MinLinearComplexityBlockLength || M > MaxLinearComplexityBlockLength {
+		return LinearComplexityResult{}, fmt.Errorf("invalid block length M=%d: need %d <= M <= %d",
+			M, MinLinearComplexityBlockLength, MaxLinearComplexityBlockLength)
+	}
 
+	n := bits.n
 	N := n / M
-	if N == 0 {
-		return 0, false
+	if N < MinLinearComplexityBlocks {
+		return LinearComplexityResult{}, fmt.Errorf("%w: insufficient bits: got %d blocks of M=%d, need at least %d",
+			ErrNotApplicable, N, M, MinLinearComplexityBlocks)
 	}
 
-	K := 6
-	pi := []float64{0.01047, 0.03125, 0.125, 0.5, 0.25, 0.0625, 0.020833}
-	nu := make([]float64, K+1)
-
-	for ii := 0; ii < N; ii++ {
-		C := make([]uint8, M)
-		B := make([]uint8, M)
-		T := make([]uint8, M)
-		P := make([]uint8, M)
-		L := 0
-		m := -1
-		d := uint8(0)
-		C[0] = 1
-		B[0] = 1
-
-		N_ := 0
-		for N_ < M {
-			d = bits[ii*M+N_]
-			for i := 1; i <= L; i++ {
-				d ^= C[i] & bits[ii*M+N_-i]
-			}
-			if d == 1 {
-				copy(T, C)
-				for j := 0; j < M; j++ {
-					if B[j] == 1 {
-						P[j+N_-m] = 1
-					}
-				}
-				for i := 0; i < M; i++ {
-					C[i] ^= P[i]
-					P[i] = 0
-				}
-				if L <= N_/2 {
-					L = N_ + 1 - L
-					m = N_
-					copy(B, T)
-				}
-			}
-			N_++
-		}
+	// mu is the theoretical mean of L_i, T_i = (-1)^M (L_i - mu) + 2/9.
+	sign := 1.0
+	if (M+1)%2 == 0 {
+		sign = -1.0
+	}
+	mean := float64(M)/2.0 + (9.0+sign)/36.0 - (float64(M)/3.0+2.0/9.0)/math.Pow(2, float64(M))
+	if M%2 == 0 {
+		sign = 1.0
+	} else {
+		sign = -1.0
+	}
 
-		sign := 1.0
-		if (M+1)%2 == 0 {
-			sign = -1.0
-		}
-		mean := float64(M)/2.0 + (9.0+sign)/36.0 - (float64(M)/3.0+2.0/9.0)/math.Pow(2, float64(M))
-		if M%2 == 0 {
-			sign = 1.0
-		} else {
-			sign = -1.0
+	res := LinearComplexityResult{
+		BlockLength:  M,
+		NumBlocks:    N,
+		Complexities: make([]int, N),
+	}
+	lfsr := newBerlekampMassey(M)
+	done := ctx.Done()
+	for i := 0; i < N; i++ {
+		if isDone(done) {
+			return LinearComplexityResult{}, ctx.Err()
 		}
-		Tval := sign*(float64(L)-mean) + 2.0/9.0
+		L := lfsr.complexity(bits, i*M)
+		res.Complexities[i] = L
 
+		T := sign*(float64(L)-mean) + 2.0/9.0
 		switch {
-		case Tval <= -2.5:
-			nu[0]++
-		case Tval <= -1.5:
-			nu[1]++
-		case Tval <= -0.5:
-			nu[2]++
-		case Tval <= 0.5:
-			nu[3]++
-		case Tval <= 1.5:
-			nu[4]++
-		case Tval <= 2.5:
-			nu[5]++
+		case T <= -2.5:
+			res.Nu[0]++
+		case T <= -1.5:
+			res.Nu[1]++
+		case T <= -0.5:
+			res.Nu[2]++
+		case T <= 0.5:
+			res.Nu[3]++
+		case T <= 1.5:
+			res.Nu[4]++
+		case T <= 2.5:
+			res.Nu[5]++
 		default:
-			nu[6]++
+			res.Nu[6]++
 		}
 	}
 
+	const K = len(linearComplexityPi) - 1
 	chi2 := 0.0
-	for i := 0; i < K+1; i++ {
-		expected := float64(N) * pi[i]
-		diff := nu[i] - expected
+	for i, pi := range linearComplexityPi {
+		expected := float64(N) * pi
+		diff := float64(res.Nu[i]) - expected
 		chi2 += diff * diff / expected
 	}
 
-	pValue := mathext.GammaIncRegComp(float64(K)/2.0, chi2/2.0)
-	return pValue, pValue >= Alpha
+	res.ChiSquare = chi2
+	res.PValue = mathext.GammaIncRegComp(float64(K)/2.0, chi2/2.0)
+	res.Passed = res.PValue >= Alpha
+	return res, nil
+}
+
+// berlekampMassey computes the linear complexity of blocks of M bits with the
+// Berlekamp-Massey algorithm on polynomials packed 64 coefficients per word, the
+// coefficient of x^i in bit i&63 of word i/64. Its buffers are reused for every block.
+type berlekampMassey struct {
+	M int
+	// c is the connection polynomial C(x), b the one before the last length change and
+	// t scratch space for C(x) while it is updated.
+	c, b, t []uint64
+	// rev holds the block in reverse, s_{M-1-j} in bit j, so that the discrepancy
+	// s_N + sum C_i s_{N-i} is the parity of C(x) and the bits of rev from M-1-N on.
+	// It has a zero word of padding.
+	rev []uint64
+}
+
+func newBerlekampMassey(M int) *berlekampMassey {
+	words := M/64 + 1
+	return &berlekampMassey{
+		M:   M,
+		c:   make([]uint64, words),
+		b:   make([]uint64, words),
+		t:   make([]uint64, words),
+		rev: make([]uint64, words+1),
+	}
+}
+
+// complexity returns the linear complexity of the M bits of s starting at idx.
+func (bm *berlekampMassey) complexity(s BitSequence, idx int) int {
+	M := bm.M
+	// Word q of rev holds s_{M-64-64q..M-1-64q}, which bitsAt returns with the last
+	// bit in bit 0; the first word of the block may be partial.
+	for q := range bm.rev {
+		switch start := M - 64 - 64*q; {
+		case start >= 0:
+			bm.rev[q] = s.bitsAt(idx+start, 64)
+		case start > -64:
+			bm.rev[q] = s.bitsAt(idx, 64+start)
+		default:
+			bm.rev[q] = 0
+		}
+	}
+	clear(bm.c)
+	clear(bm.b)
+	bm.c[0], bm.b[0] = 1, 1
+
+	L, m := 0, -1
+	for N := 0; N < M; N++ {
+		// C(x) has degree at most L, so only its first L/64+1 words contribute.
+		off := M - 1 - N
+		w, sh := off>>6, uint(off&63)
+		d := 0
+		for k := 0; k <= L>>6; k++ {
+			window := bm.rev[w+k]>>sh | bm.rev[w+k+1]<<(64-sh)
+			d += bits.OnesCount64(bm.c[k] & window)
+		}
+		if d&1 == 0 {
+			continue
+		}
+
+		newL := L
+		if L <= N/2 {
+			newL = N + 1 - L
+			copy(bm.t, bm.c)
+		}
+		// C(x) += x^(N-m) B(x); the result has degree at most newL.
+		shift := N - m
+		ws, bs := shift>>6, uint(shift&63)
+		for k := ws; k <= newL>>6; k++ {
+			v := bm.b[k-ws] << bs
+			if bs != 0 && k > ws {
+				v |= bm.b[k-ws-1] >> (64 - bs)
+			}
+			bm.c[k] ^= v
+		}
+		if newL != L {
+			L, m = newL, N
+			bm.b, bm.t = bm.t, bm.b
+		}
+	}
+	return L
 }
@@ -1,7 +1,11 @@
 package nist
 
 import (
+	"context"
+	"errors"
 	"testing"
+
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/testrand"
 )
 
 func TestLinearComplexity(t *testing.T) {
@@ -14,7 +18,8 @@ func TestLinearComplexity(t *testing.T) {
 	})
 
 	t.Run("valid_input", func(t *testing.T) {
-		data := make([]byte, 10000)
+		// 200 blocks of M=500 bits
+		data := make([]byte, 12500)
 		for i := range data {
 			data[i] = byte(i % 256)
 		}
@@ -24,3 +29,103 @@ func TestLinearComplexity(t *testing.T) {
 		}
 	})
 }
+
+// naiveLinearComplexity is the bit-by-bit Berlekamp-Massey algorithm of the NIST STS.
+func naiveLinearComplexity(block []uint8) int {
+	M := len(block)
+	C := make([]uint8, M+1)
+	B := make([]uint8, M+1)
+	C[0], B[0] = 1, 1
+	L, m := 0, -1
+	for N := 0; N < M; N++ {
+		d := block[N]
+		for i := 1; i <= L; i++ {
+			d ^= C[i] & block[N-i]
+		}
+		if d == 0 {
+			continue
+		}
+		T := append([]uint8(nil), C...)
+		for j := 0; j+N-m <= M; j++ {
+			C[j+N-m] ^= B[j]
+		}
+		if L <= N/2 {
+			L, m, B = N+1-L, N, T
+		}
+	}
+	return L
+}
+
+func TestBerlekampMassey(t *testing.T) {
+	data := testrand.Bytes(4000, 11)
+	// An LFSR of degree 17, x(i) = x(i-17) ^ x(i-3), has linear complexity 17
+	lfsr := make([]uint8, 8*len(data))
+	for i := range lfsr {
+		if i < 17 {
+			lfsr[i] = uint8(i % 2)
+		} else {
+			lfsr[i] = lfsr[i-17] ^ lfsr[i-3]
+		}
+	}
+
+	for _, s := range []BitSequence{BitSequenceFromBytes(data), BitSequenceFromBits(lfsr)} {
+		for _, M := range []int{500, 511, 640, 1000, 4999} {
+			bm := newBerlekampMassey(M)
+			for idx := 0; idx+M <= s.Len(); idx += M + 37 {
+				block := make([]uint8, M)
+				for i := range block {
+					block[i] = s.Bit(idx + i)
+				}
+				if got, want := bm.complexity(s, idx), naiveLinearComplexity(block); got != want {
+					t.Fatalf("M=%d block at %d: expected linear complexity %d, got %d", M, idx, want, got)
+				}
+			}
+		}
+	}
+
+	if L := newBerlekampMassey(500).complexity(BitSequenceFromBits(lfsr), 123); L != 17 {
+		t.Errorf("expected linear complexity 17 for the LFSR, got %d", L)
+	}
+}
+
+func TestLinearComplexityTestSequence(t *testing.T) {
+	data := testrand.Bytes(25000, 7)
+
+	res, err := LinearComplexityTestSequence(context.Background(), BitSequenceFromBytes(data), 1000)
+	if err != nil {
+		t.Fatalf("LinearComplexityTestSequence failed: %v", err)
+	}
+	if res.BlockLength != 1000 || res.NumBlocks != 200 || len(res.Complexities) != 200 {
+		t.Fatalf("unexpected shape: M=%d N=%d with %d complexities", res.BlockLength, res.NumBlocks, len(res.Complexities))
+	}
+	total := 0
+	for _, nu := range res.Nu {
+		total += nu
+	}
+	if total != res.NumBlocks {
+		t.Errorf("expected nu to count %d blocks, got %d", res.NumBlocks, total)
+	}
+	for i, L := range res.Complexities {
+		if L < 480 || L > 520 {
+			t.Errorf("block %d: unexpected linear complexity %d of random data", i, L)
+		}
+	}
+	if p, pass := LinearComplexityTest(data, 1000); p != res.PValue || pass != res.Passed {
+		t.Errorf("p-value %.6f (pass=%v) does not match the detailed result", p, pass)
+	}
+
+	for _, M := range []int{499, 5001} {
+		if _, err := LinearComplexityTestSequence(context.Background(), BitSequenceFromBytes(data), M); err == nil || errors.Is(err, ErrNotApplicable) {
+			t.Errorf("M=%d: expected an invalid parameter error, got %v", M, err)
+		}
+	}
+	if _, err := LinearComplexityTestSequence(context.Background(), BitSequenceFromBytes(data[:24999]), 1000); !errors.Is(err, ErrNotApplicable) {
+		t.Errorf("expected ErrNotApplicable for 199 blocks, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := LinearComplexityTestSequence(ctx, BitSequenceFromBytes(data), 1000); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package nist
 
 import (
+	"context"
 	"math"
 
 	"gonum.org/v1/gonum/mathext"
@@ -9,10 +10,16 @@ import (
 // LongestRunOfOnesTest implements the NIST Longest Run of Ones test.
 // It returns the p-value and whether it passes at Alpha.
 func LongestRunOfOnesTest(bitstream []byte) (float64, bool) {
-	bits := expandBits(bitstream)
-	n := len(bits)
+	o, _ := LongestRunOfOnesTestSequence(context.Background(), BitSequenceFromBytes(bitstream))
+	return o.PValue, o.Passed()
+}
+
+// LongestRunOfOnesTestSequence runs the Longest Run of Ones test on a BitSequence of any length.
+// The outcome's Counts hold nu_0..nu_K, the number of blocks in each longest-run class.
+func LongestRunOfOnesTestSequence(ctx context.Context, bits BitSequence) (Outcome, error) {
+	n := bits.n
 	if n < 128 {
-		return 0, false
+		return notApplicable("insufficient bits: got %d, need at least 128", n), nil
 	}
 
 	var K, M int
@@ -35,17 +42,17 @@ func LongestRunOfOnesTest(bitstream []byte) (float64, bool) {
 	}
 
 	N := n / M
-	if N == 0 {
-		return 0, false
-	}
-
 	nu := make([]float64, K+1)
+	done := ctx.Done()
 	for block := 0; block < N; block++ {
+		if isDone(done) {
+			return Outcome{}, ctx.Err()
+		}
 		longest := 0
 		run := 0
 		base := block * M
 		for j := 0; j < M; j++ {
-			if bits[base+j] == 1 {
+			if bits.Bit(base+j) == 1 {
 				run++
 				if run > longest {
 					longest = run
@@ -77,5 +84,10 @@ func LongestRunOfOnesTest(bitstream []byte) (float64, bool) {
 
 	pValue := mathext.GammaIncRegComp(float64(K)/2.0, chiSquared/2.0)
 
-	return pValue, pValue >= Alpha
+	o := statisticOutcome(pValue, chiSquared)
+	o.Counts = make([]int, K+1)
+	for i, v := range nu {
+		o.Counts[i] = int(v)
+	}
+	return o, nil
 }
@@ -0,0 +1,180 @@
+package nist
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mathext"
+)
+
+const (
+	// UniformityAlpha is the significance level for the uniformity of p-values (P-value_T)
+	// in the second-level analysis of SP 800-22 section 4.2.2.
+	UniformityAlpha = 0.0001
+
+	// numUniformityBins is the number of p-value bins C1..C10 used by the uniformity check.
+	numUniformityBins = 10
+)
+
+// SequenceAnalysis is the second-level analysis of a single statistic across all sequences.
+// It corresponds to one line of the NIST STS finalAnalysisReport.txt.
+type SequenceAnalysis struct {
+	// Name is the test name, suffixed with "/<sub-result>" for multi-valued tests.
+	Name string
+	// Histogram holds C1..C10, the number of p-values in [0, 0.1), [0.1, 0.2), ..., [0.9, 1].
+	Histogram [numUniformityBins]int
+	// PValueT is the chi-square p-value for the uniformity of the histogram.
+	PValueT          float64
+	UniformityPassed bool
+	// PassedSequences and SampleSize give the proportion of sequences passing at Alpha.
+	// SampleSize counts only sequences for which the statistic could be computed.
+	PassedSequences  int
+	SampleSize       int
+	Proportion       float64
+	ProportionLower  float64
+	ProportionUpper  float64
+	ProportionPassed bool
+}
+
+// MultiSequenceReport is the result of RunMultiSequence.
+type MultiSequenceReport struct {
+	SequenceLength int
+	NumSequences   int
+	// Config holds the effective parameters (defaults filled in).
+	Config  SuiteConfig
+	Results []SequenceAnalysis
+}
+
+// RunMultiSequence splits bitstream into numSequences consecutive sequences of sequenceLength
+// bits, runs the full battery on each and evaluates every statistic as described in
+// SP 800-22 section 4.2: the proportion of passing sequences must lie within
+// (1-Alpha) ± 3*sqrt(Alpha*(1-Alpha)/s) and the p-values must be uniform (P-value_T >= UniformityAlpha).
+func RunMultiSequence(bitstream []byte, sequenceLength, numSequences int, cfg SuiteConfig) (*MultiSequenceReport, error) {
+	return RunMultiSequenceContext(context.Background(), bitstream, sequenceLength, numSequences, cfg)
+}
+
+// RunMultiSequenceContext is like RunMultiSequence but stops once ctx is done, in which
+// case it returns ctx.Err() and no report.
+func RunMultiSequenceContext(ctx context.Context, bitstream []byte, sequenceLength, numSequences int, cfg SuiteConfig) (*MultiSequenceReport, error) {
+	return RunMultiSequenceFunc(ctx, bitstream, sequenceLength, numSequences, cfg, nil)
+}
+
+// SequenceFunc receives the results of one sequence of a multi-sequence run; index
+// counts the sequences from zero.
+type SequenceFunc func(index int, results []TestResult) error
+
+// RunMultiSequenceFunc is like RunMultiSequenceContext but also passes the results of
+// each sequence, in order, to fn (if not nil). An error returned by fn stops the run.
+func RunMultiSequenceFunc(ctx context.Context, bitstream []byte, sequenceLength, numSequences int, cfg SuiteConfig, fn SequenceFunc) (*MultiSequenceReport, error) {
+	if sequenceLength <= 0 || sequenceLength%8 != 0 {
+		return nil, fmt.Errorf("sequence length must be a positive multiple of 8 bits, got %d", sequenceLength)
+	}
+	if numSequences <= 0 {
+		return nil, fmt.Errorf("number of sequences must be positive, got %d", numSequences)
+	}
+	if available := len(bitstream) * 8; sequenceLength*numSequences > available {
+		return nil, fmt.Errorf("insufficient bits: %d sequences of %d bits need %d, got %d",
+			numSequences, sequenceLength, sequenceLength*numSequences, available)
+	}
+	if err := cfg.Validate(sequenceLength); err != nil {
+		return nil, err
+	}
+
+	// Collect the p-values of every statistic, keeping the suite's ordering.
+	var names []string
+	pValues := make(map[string][]float64)
+	add := func(name string, p float64) {
+		if _, ok := pValues[name]; !ok {
+			names = append(names, name)
+		}
+		pValues[name] = append(pValues[name], p)
+	}
+
+	stride := sequenceLength / 8
+	for i := 0; i < numSequences; i++ {
+		results, err := RunAllTestsContext(ctx, bitstream[i*stride:(i+1)*stride], cfg)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			return nil, fmt.Errorf("sequence %d: %w", i, err)
+		}
+		if fn != nil {
+			if err := fn(i, results); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, r := range results {
+			// Statistics that could not be computed do not count towards the sample size.
+			if !r.Status.Completed() {
+				continue
+			}
+			if !r.MultiValued() {
+				add(r.Name, r.PValue)
+				continue
+			}
+			for _, sub := range r.SubResults {
+				add(r.Name+"/"+sub.Name, sub.PValue)
+			}
+		}
+	}
+
+	report := &MultiSequenceReport{
+		SequenceLength: sequenceLength,
+		NumSequences:   numSequences,
+		Config:         cfg.WithDefaults(),
+		Results:        make([]SequenceAnalysis, 0, len(names)),
+	}
+	for _, name := range names {
+		a := AnalyzePValues(pValues[name])
+		a.Name = name
+		report.Results = append(report.Results, a)
+	}
+
+	return report, nil
+}
+
+// AnalyzePValues computes the proportion and uniformity statistics for the p-values
+// obtained by one statistic over a set of sequences.
+func AnalyzePValues(pValues []float64) SequenceAnalysis {
+	var a SequenceAnalysis
+	a.SampleSize = len(pValues)
+	if a.SampleSize == 0 {
+		return a
+	}
+
+	for _, p := range pValues {
+		bin := int(math.Floor(p * numUniformityBins))
+		if bin >= numUniformityBins {
+			bin = numUniformityBins - 1
+		}
+		if bin < 0 {
+			bin = 0
+		}
+		a.Histogram[bin]++
+		if p >= Alpha {
+			a.PassedSequences++
+		}
+	}
+
+	s := float64(a.SampleSize)
+	expected := s / numUniformityBins
+	chi2 := 0.0
+	for _, c := range a.Histogram {
+		diff := float64(c) - expected
+		chi2 += diff * diff / expected
+	}
+	a.PValueT = mathext.GammaIncRegComp(float64(numUniformityBins-1)/2.0, chi2/2.0)
+	a.UniformityPassed = a.PValueT >= UniformityAlpha
+
+	pHat := 1 - Alpha
+	margin := 3 * math.Sqrt(pHat*Alpha/s)
+	a.Proportion = float64(a.PassedSequences) / s
+	a.ProportionLower = pHat - margin
+	a.ProportionUpper = pHat + margin
+	a.ProportionPassed = a.Proportion >= a.ProportionLower && a.Proportion <= a.ProportionUpper
+
+	return a
+}
@@ -0,0 +1,144 @@
+package nist
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/testrand"
+)
+
+func TestAnalyzePValues(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		a := AnalyzePValues(nil)
+		if a.SampleSize != 0 || a.UniformityPassed || a.ProportionPassed {
+			t.Fatalf("expected empty analysis, got %+v", a)
+		}
+	})
+
+	t.Run("uniform_and_passing", func(t *testing.T) {
+		p := make([]float64, 100)
+		for i := range p {
+			p[i] = (float64(i) + 0.5) / 100
+		}
+		a := AnalyzePValues(p)
+		for i, c := range a.Histogram {
+			if c != 10 {
+				t.Fatalf("bin C%d: expected 10, got %d", i+1, c)
+			}
+		}
+		if a.PValueT != 1 || !a.UniformityPassed {
+			t.Fatalf("expected perfectly uniform p-values, got P-value_T=%.6f", a.PValueT)
+		}
+		if a.PassedSequences != 99 || a.Proportion != 0.99 || !a.ProportionPassed { // p=0.005 fails at Alpha
+			t.Fatalf("unexpected proportion: %+v", a)
+		}
+		// Interval for s=100 is 0.99 ± 0.0298 (96/100 minimum pass rate in the STS report).
+		if math.Abs(a.ProportionLower-0.960150) > 1e-6 {
+			t.Fatalf("unexpected lower bound %.6f", a.ProportionLower)
+		}
+	})
+
+	t.Run("skewed_and_failing", func(t *testing.T) {
+		p := make([]float64, 100)
+		for i := range p {
+			p[i] = 0.001
+		}
+		p[99] = 1.0 // lands in the last bin
+		a := AnalyzePValues(p)
+		if a.Histogram[0] != 99 || a.Histogram[9] != 1 {
+			t.Fatalf("unexpected histogram %v", a.Histogram)
+		}
+		if a.UniformityPassed || a.ProportionPassed {
+			t.Fatalf("expected both criteria to fail: %+v", a)
+		}
+	})
+}
+
+func TestRunMultiSequence(t *testing.T) {
+	const seqLen = MinBits
+	data := testrand.Bytes(3*seqLen/8, 2024)
+
+	t.Run("invalid_arguments", func(t *testing.T) {
+		if _, err := RunMultiSequence(data, seqLen+1, 1, SuiteConfig{}); err == nil {
+			t.Error("expected error for non byte-aligned sequence length")
+		}
+		if _, err := RunMultiSequence(data, seqLen, 0, SuiteConfig{}); err == nil {
+			t.Error("expected error for zero sequences")
+		}
+		if _, err := RunMultiSequence(data, seqLen, 4, SuiteConfig{}); err == nil {
+			t.Error("expected error for insufficient bits")
+		}
+		if _, err := RunMultiSequence(data, seqLen, 1, SuiteConfig{SerialBlockLength: 30}); !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("expected ErrInvalidParameter, got %v", err)
+		}
+	})
+
+	t.Run("report", func(t *testing.T) {
+		report, err := RunMultiSequence(data, seqLen, 3, SuiteConfig{})
+		if err != nil {
+			t.Fatalf("RunMultiSequence failed: %v", err)
+		}
+		if report.NumSequences != 3 || report.SequenceLength != seqLen || report.Config != DefaultSuiteConfig() {
+			t.Fatalf("unexpected report header: %+v", report)
+		}
+
+		seen := make(map[string]bool)
+		templates := 0
+		for _, a := range report.Results {
+			if seen[a.Name] {
+				t.Fatalf("duplicate statistic %s", a.Name)
+			}
+			seen[a.Name] = true
+			if len(a.Name) > 25 && a.Name[:25] == "non_overlapping_template/" {
+				templates++
+			}
+			total := 0
+			for _, c := range a.Histogram {
+				total += c
+			}
+			if total != a.SampleSize || a.SampleSize > 3 {
+				t.Fatalf("%s: histogram total %d does not match sample size %d", a.Name, total, a.SampleSize)
+			}
+		}
+		if !seen["frequency_monobit"] || !seen["linear_complexity"] {
+			t.Fatalf("missing single-valued statistics in %v", seen)
+		}
+		// The STS counts both Serial and both Cusum p-values separately
+		for _, name := range []string{"serial/p_value1", "serial/p_value2", "cumulative_sums/forward", "cumulative_sums/reverse"} {
+			if !seen[name] {
+				t.Fatalf("missing statistic %s in %v", name, seen)
+			}
+		}
+		if templates != 148 {
+			t.Fatalf("expected 148 template statistics, got %d", templates)
+		}
+	})
+	t.Run("sequence_func", func(t *testing.T) {
+		var indexes []int
+		report, err := RunMultiSequenceFunc(context.Background(), data, seqLen, 2, SuiteConfig{}, func(i int, results []TestResult) error {
+			indexes = append(indexes, i)
+			if len(results) != 15 {
+				t.Errorf("sequence %d: expected 15 results, got %d", i, len(results))
+			}
+			return nil
+		})
+		if err != nil || report.NumSequences != 2 {
+			t.Fatalf("RunMultiSequenceFunc failed: %v", err)
+		}
+		if len(indexes) != 2 || indexes[0] != 0 || indexes[1] != 1 {
+			t.Fatalf("unexpected sequence indexes %v", indexes)
+		}
+
+		stop := errors.New("stop")
+		calls := 0
+		_, err = RunMultiSequenceFunc(context.Background(), data, seqLen, 3, SuiteConfig{}, func(int, []TestResult) error {
+			calls++
+			return stop
+		})
+		if !errors.Is(err, stop) || calls != 1 {
+			t.Fatalf("expected the callback error after one sequence, got %v after %d calls", err, calls)
+		}
+	})
+}
@@ -1,32 +1,92 @@
 package nist
 
 import (
+	"context"
+	"fmt"
 	"math"
+	"sync"
 
 	"gonum.org/v1/gonum/mathext"
 )
 
-// NonOverlappingTemplateTest implements the NIST Non-overlapping Template Matching test for m=9.
-// It returns the minimum p-value across all templates and whether it passes at Alpha.
+// TemplateResult is the outcome of the Non-overlapping Template test for a single template.
+type TemplateResult struct {
+	// Template is the bit pattern B, e.g. "000000001".
+	Template string
+	// Counts holds W_j, the number of non-overlapping matches in each of the N blocks.
+	Counts    []int
+	ChiSquare float64
+	PValue    float64
+	Passed    bool
+}
+
+// NonOverlappingTemplateResult is the detailed outcome of the Non-overlapping Template test.
+type NonOverlappingTemplateResult struct {
+	TemplateLength int // m
+	BlockLength    int // M
+	NumBlocks      int // N
+	Lambda         float64
+	Variance       float64
+	Templates      []TemplateResult
+}
+
+// MinPValue returns the smallest p-value across all templates.
+func (r NonOverlappingTemplateResult) MinPValue() float64 {
+	minP := 1.0
+	for _, t := range r.Templates {
+		if t.PValue < minP {
+			minP = t.PValue
+		}
+	}
+	return minP
+}
+
+// CombinedPValue returns the Sidak-corrected minimum 1-(1-p_min)^k over the k templates,
+// the p-value that decides whether the test as a whole passes.
+func (r NonOverlappingTemplateResult) CombinedPValue() float64 {
+	return sidakPValue(r.MinPValue(), len(r.Templates))
+}
+
+// NonOverlappingTemplateTest implements the NIST Non-overlapping Template Matching test
+// with template length m and the default number of blocks N=8.
+// It returns the Sidak-corrected minimum p-value across all templates and whether it
+// passes at Alpha.
 func NonOverlappingTemplateTest(bitstream []byte, m int) (float64, bool) {
-	if m != 9 {
+	res, err := NonOverlappingTemplateTestSequence(context.Background(), BitSequenceFromBytes(bitstream),
+		m, DefaultNonOverlappingTemplateBlocks)
+	if err != nil {
 		return 0, false
 	}
 
-	bits := expandBits(bitstream)
-	n := len(bits)
+	p := res.CombinedPValue()
+	return p, p >= Alpha
+}
+
+// NonOverlappingTemplateTestSequence runs the Non-overlapping Template Matching test on a
+// BitSequence of any length with template length m over numBlocks blocks (N in the NIST
+// documentation) and reports the block counts W_j, chi-square and p-value of every
+// template. It returns an error wrapping ErrNotApplicable when the sequence is too short
+// for numBlocks blocks of at least m bits.
+func NonOverlappingTemplateTestSequence(ctx context.Context, bits BitSequence, m, numBlocks int) (NonOverlappingTemplateResult, error) {
+	if m < MinTemplateLength || m > MaxTemplateLength {
+		return NonOverlappingTemplateResult{}, fmt.Errorf("template length m=%d not supported (need %d..%d)",
+			m, MinTemplateLength, MaxTemplateLength)
+	}
+	if numBlocks <= 0 {
+		return NonOverlappingTemplateResult{}, fmt.Errorf("invalid number of blocks N=%d", numBlocks)
+	}
+
+	n := bits.n
 	if n < m {
-		return 0, false
+		return NonOverlappingTemplateResult{}, fmt.Errorf("%w: insufficient bits: got %d, need at least %d", ErrNotApplicable, n, m)
 	}
 
-	const (
-		N = 8
-		K = 5
-	)
+	const K = 5
 
+	N := numBlocks
 	M := n / N
-	if M == 0 {
-		return 0, false
+	if M < m {
+		return NonOverlappingTemplateResult{}, fmt.Errorf("%w: insufficient bits for %d blocks of at least %d bits", ErrNotApplicable, N, m)
 	}
 
 	lambda := float64(M-m+1) / math.Pow(2, float64(m))
@@ -45,27 +105,23 @@ func NonOverlappingTemplateTest(bitstream []byte, m int) (float64, bool) {
 	}
 	pi[K] = 1 - sum
 
-	minP := 1.0
-	for t := 0; t < len(template9); t++ {
-		Wj := make([]int, N)
-		for block := 0; block < N; block++ {
-			wObs := 0
-			for j := 0; j < M-m+1; j++ {
-				match := true
-				for k := 0; k < m; k++ {
-					if template9[t][k] != bits[block*M+j+k] {
-						match = false
-						break
-					}
-				}
-				if match {
-					wObs++
-					j += m - 1
-				}
-			}
-			Wj[block] = wObs
-		}
+	templates := nonPeriodicTemplates(m)
+	res := NonOverlappingTemplateResult{
+		TemplateLength: m,
+		BlockLength:    M,
+		NumBlocks:      N,
+		Lambda:         lambda,
+		Variance:       varWj,
+		Templates:      make([]TemplateResult, 0, len(templates)),
+	}
 
+	counts, err := countTemplates(ctx, bits, m, N, M)
+	if err != nil {
+		return NonOverlappingTemplateResult{}, err
+	}
+
+	for t, template := range templates {
+		Wj := counts[t*N : (t+1)*N : (t+1)*N]
 		chi2 := 0.0
 		for i := 0; i < N; i++ {
 			diff := (float64(Wj[i]) - lambda) / math.Sqrt(varWj)
@@ -73,12 +129,70 @@ func NonOverlappingTemplateTest(bitstream []byte, m int) (float64, bool) {
 		}
 
 		p := mathext.GammaIncRegComp(float64(N)/2.0, chi2/2.0)
-		if p < minP {
-			minP = p
+		res.Templates = append(res.Templates, TemplateResult{
+			Template:  templateString(template, m),
+			Counts:    Wj,
+			ChiSquare: chi2,
+			PValue:    p,
+			Passed:    p >= Alpha,
+		})
+	}
+
+	return res, nil
+}
+
+// countTemplates counts the non-overlapping matches of all nonPeriodicTemplates(m) in each
+// of the N blocks of M bits in a single pass: an m-bit window slides over the packed
+// words of every block and is looked up in templateSlots(m). The result holds W_j of
+// template t and block j at index t*N+j.
+//
+// After a match, a template cannot match again before the window has been refilled with
+// m new bits. For the aperiodic templates of the STS this never excludes a match, since
+// they cannot overlap themselves, but the rule is applied as the test defines it.
+func countTemplates(ctx context.Context, bits BitSequence, m, N, M int) ([]int, error) {
+	slots := templateSlots(m)
+	numTemplates := len(nonPeriodicTemplates(m))
+	counts := make([]int, numTemplates*N)
+	// next[t] is the number of block bits after which template t may match again
+	next := make([]int, numTemplates)
+
+	mask := uint32(1)<<m - 1
+	done := ctx.Done()
+	for block := 0; block < N; block++ {
+		clear(next)
+		window := uint32(0)
+		filled := 0
+		for i, end := block*M, (block+1)*M; i < end; {
+			if isDone(done) {
+				return nil, ctx.Err()
+			}
+			word := bits.words[i>>6] << uint(i&63)
+			n := min(64-(i&63), end-i)
+			for range n {
+				window = (window<<1 | uint32(word>>63)) & mask
+				word <<= 1
+				filled++
+				if filled < m {
+					continue
+				}
+				if t := slots[window]; t >= 0 && filled >= next[t] {
+					counts[int(t)*N+block]++
+					next[t] = filled + m
+				}
+			}
+			i += n
 		}
 	}
+	return counts, nil
+}
 
-	return minP, minP >= Alpha
+// templateString renders an m-bit template as a string of '0' and '1' characters.
+func templateString(template uint32, m int) string {
+	b := make([]byte, m)
+	for i := range b {
+		b[i] = '0' + byte(template>>(m-1-i)&1)
+	}
+	return string(b)
 }
 
 func logGamma(x float64) float64 {
@@ -86,154 +200,76 @@ func logGamma(x float64) float64 {
 	return v
 }
 
-// template9 embeds the 148 templates for m=9 from the NIST suite.
-var template9 = [][9]uint8{
-	{0, 0, 0, 0, 0, 0, 0, 0, 1},
-	{0, 0, 0, 0, 0, 0, 0, 1, 1},
-	{0, 0, 0, 0, 0, 0, 1, 0, 1},
-	{0, 0, 0, 0, 0, 0, 1, 1, 1},
-	{0, 0, 0, 0, 0, 1, 0, 0, 1},
-	{0, 0, 0, 0, 0, 1, 0, 1, 1},
-	{0, 0, 0, 0, 0, 1, 1, 0, 1},
-	{0, 0, 0, 0, 0, 1, 1, 1, 1},
-	{0, 0, 0, 0, 1, 0, 0, 0, 1},
-	{0, 0, 0, 0, 1, 0, 0, 1, 1},
-	{0, 0, 0, 0, 1, 0, 1, 0, 1},
-	{0, 0, 0, 0, 1, 0, 1, 1, 1},
-	{0, 0, 0, 0, 1, 1, 0, 0, 1},
-	{0, 0, 0, 0, 1, 1, 0, 1, 1},
-	{0, 0, 0, 0, 1, 1, 1, 0, 1},
-	{0, 0, 0, 0, 1, 1, 1, 1, 1},
-	{0, 0, 0, 1, 0, 0, 0, 1, 1},
-	{0, 0, 0, 1, 0, 0, 1, 0, 1},
-	{0, 0, 0, 1, 0, 0, 1, 1, 1},
-	{0, 0, 0, 1, 0, 1, 0, 0, 1},
-	{0, 0, 0, 1, 0, 1, 0, 1, 1},
-	{0, 0, 0, 1, 0, 1, 1, 0, 1},
-	{0, 0, 0, 1, 0, 1, 1, 1, 1},
-	{0, 0, 0, 1, 1, 0, 0, 1, 1},
-	{0, 0, 0, 1, 1, 0, 1, 0, 1},
-	{0, 0, 0, 1, 1, 0, 1, 1, 1},
-	{0, 0, 0, 1, 1, 1, 0, 0, 1},
-	{0, 0, 0, 1, 1, 1, 0, 1, 1},
-	{0, 0, 0, 1, 1, 1, 1, 0, 1},
-	{0, 0, 0, 1, 1, 1, 1, 1, 1},
-	{0, 0, 1, 0, 0, 0, 0, 1, 1},
-	{0, 0, 1, 0, 0, 0, 1, 0, 1},
-	{0, 0, 1, 0, 0, 0, 1, 1, 1},
-	{0, 0, 1, 0, 0, 1, 0, 1, 1},
-	{0, 0, 1, 0, 0, 1, 1, 0, 1},
-	{0, 0, 1, 0, 0, 1, 1, 1, 1},
-	{0, 0, 1, 0, 1, 0, 0, 1, 1},
-	{0, 0, 1, 0, 1, 0, 1, 0, 1},
-	{0, 0, 1, 0, 1, 0, 1, 1, 1},
-	{0, 0, 1, 0, 1, 1, 0, 1, 1},
-	{0, 0, 1, 0, 1, 1, 1, 0, 1},
-	{0, 0, 1, 0, 1, 1, 1, 1, 1},
-	{0, 0, 1, 1, 0, 0, 1, 0, 1},
-	{0, 0, 1, 1, 0, 0, 1, 1, 1},
-	{0, 0, 1, 1, 0, 1, 0, 1, 1},
-	{0, 0, 1, 1, 0, 1, 1, 0, 1},
-	{0, 0, 1, 1, 0, 1, 1, 1, 1},
-	{0, 0, 1, 1, 1, 0, 1, 0, 1},
-	{0, 0, 1, 1, 1, 0, 1, 1, 1},
-	{0, 0, 1, 1, 1, 1, 0, 1, 1},
-	{0, 0, 1, 1, 1, 1, 1, 0, 1},
-	{0, 0, 1, 1, 1, 1, 1, 1, 1},
-	{0, 1, 0, 0, 0, 0, 0, 1, 1},
-	{0, 1, 0, 0, 0, 0, 1, 1, 1},
-	{0, 1, 0, 0, 0, 1, 0, 1, 1},
-	{0, 1, 0, 0, 0, 1, 1, 1, 1},
-	{0, 1, 0, 0, 1, 0, 0, 1, 1},
-	{0, 1, 0, 0, 1, 0, 1, 1, 1},
-	{0, 1, 0, 0, 1, 1, 0, 1, 1},
-	{0, 1, 0, 0, 1, 1, 1, 1, 1},
-	{0, 1, 0, 1, 0, 0, 0, 1, 1},
-	{0, 1, 0, 1, 0, 0, 1, 1, 1},
-	{0, 1, 0, 1, 0, 1, 0, 1, 1},
-	{0, 1, 0, 1, 0, 1, 1, 1, 1},
-	{0, 1, 0, 1, 1, 0, 0, 1, 1},
-	{0, 1, 0, 1, 1, 0, 1, 1, 1},
-	{0, 1, 0, 1, 1, 1, 0, 1, 1},
-	{0, 1, 0, 1, 1, 1, 1, 1, 1},
-	{0, 1, 1, 0, 0, 0, 1, 1, 1},
-	{0, 1, 1, 0, 0, 1, 1, 1, 1},
-	{0, 1, 1, 0, 1, 0, 1, 1, 1},
-	{0, 1, 1, 0, 1, 1, 1, 1, 1},
-	{0, 1, 1, 1, 0, 1, 1, 1, 1},
-	{0, 1, 1, 1, 1, 1, 1, 1, 1},
-	{1, 0, 0, 0, 0, 0, 0, 0, 0},
-	{1, 0, 0, 0, 1, 0, 0, 0, 0},
-	{1, 0, 0, 1, 0, 0, 0, 0, 0},
-	{1, 0, 0, 1, 0, 1, 0, 0, 0},
-	{1, 0, 0, 1, 1, 0, 0, 0, 0},
-	{1, 0, 0, 1, 1, 1, 0, 0, 0},
-	{1, 0, 1, 0, 0, 0, 0, 0, 0},
-	{1, 0, 1, 0, 0, 0, 1, 0, 0},
-	{1, 0, 1, 0, 0, 1, 0, 0, 0},
-	{1, 0, 1, 0, 0, 1, 1, 0, 0},
-	{1, 0, 1, 0, 1, 0, 0, 0, 0},
-	{1, 0, 1, 0, 1, 0, 1, 0, 0},
-	{1, 0, 1, 0, 1, 1, 0, 0, 0},
-	{1, 0, 1, 0, 1, 1, 1, 0, 0},
-	{1, 0, 1, 1, 0, 0, 0, 0, 0},
-	{1, 0, 1, 1, 0, 0, 1, 0, 0},
-	{1, 0, 1, 1, 0, 1, 0, 0, 0},
-	{1, 0, 1, 1, 0, 1, 1, 0, 0},
-	{1, 0, 1, 1, 1, 0, 0, 0, 0},
-	{1, 0, 1, 1, 1, 0, 1, 0, 0},
-	{1, 0, 1, 1, 1, 1, 0, 0, 0},
-	{1, 0, 1, 1, 1, 1, 1, 0, 0},
-	{1, 1, 0, 0, 0, 0, 0, 0, 0},
-	{1, 1, 0, 0, 0, 0, 0, 1, 0},
-	{1, 1, 0, 0, 0, 0, 1, 0, 0},
-	{1, 1, 0, 0, 0, 1, 0, 0, 0},
-	{1, 1, 0, 0, 0, 1, 0, 1, 0},
-	{1, 1, 0, 0, 1, 0, 0, 0, 0},
-	{1, 1, 0, 0, 1, 0, 0, 1, 0},
-	{1, 1, 0, 0, 1, 0, 1, 0, 0},
-	{1, 1, 0, 0, 1, 1, 0, 0, 0},
-	{1, 1, 0, 0, 1, 1, 0, 1, 0},
-	{1, 1, 0, 1, 0, 0, 0, 0, 0},
-	{1, 1, 0, 1, 0, 0, 0, 1, 0},
-	{1, 1, 0, 1, 0, 0, 1, 0, 0},
-	{1, 1, 0, 1, 0, 1, 0, 0, 0},
-	{1, 1, 0, 1, 0, 1, 0, 1, 0},
-	{1, 1, 0, 1, 0, 1, 1, 0, 0},
-	{1, 1, 0, 1, 1, 0, 0, 0, 0},
-	{1, 1, 0, 1, 1, 0, 0, 1, 0},
-	{1, 1, 0, 1, 1, 0, 1, 0, 0},
-	{1, 1, 0, 1, 1, 1, 0, 0, 0},
-	{1, 1, 0, 1, 1, 1, 0, 1, 0},
-	{1, 1, 0, 1, 1, 1, 1, 0, 0},
-	{1, 1, 1, 0, 0, 0, 0, 0, 0},
-	{1, 1, 1, 0, 0, 0, 0, 1, 0},
-	{1, 1, 1, 0, 0, 0, 1, 0, 0},
-	{1, 1, 1, 0, 0, 0, 1, 1, 0},
-	{1, 1, 1, 0, 0, 1, 0, 0, 0},
-	{1, 1, 1, 0, 0, 1, 0, 1, 0},
-	{1, 1, 1, 0, 0, 1, 1, 0, 0},
-	{1, 1, 1, 0, 1, 0, 0, 0, 0},
-	{1, 1, 1, 0, 1, 0, 0, 1, 0},
-	{1, 1, 1, 0, 1, 0, 1, 0, 0},
-	{1, 1, 1, 0, 1, 0, 1, 1, 0},
-	{1, 1, 1, 0, 1, 1, 0, 0, 0},
-	{1, 1, 1, 0, 1, 1, 0, 1, 0},
-	{1, 1, 1, 0, 1, 1, 1, 0, 0},
-	{1, 1, 1, 1, 0, 0, 0, 0, 0},
-	{1, 1, 1, 1, 0, 0, 0, 1, 0},
-	{1, 1, 1, 1, 0, 0, 1, 0, 0},
-	{1, 1, 1, 1, 0, 0, 1, 1, 0},
-	{1, 1, 1, 1, 0, 1, 0, 0, 0},
-	{1, 1, 1, 1, 0, 1, 0, 1, 0},
-	{1, 1, 1, 1, 0, 1, 1, 0, 0},
-	{1, 1, 1, 1, 0, 1, 1, 1, 0},
-	{1, 1, 1, 1, 1, 0, 0, 0, 0},
-	{1, 1, 1, 1, 1, 0, 0, 1, 0},
-	{1, 1, 1, 1, 1, 0, 1, 0, 0},
-	{1, 1, 1, 1, 1, 0, 1, 1, 0},
-	{1, 1, 1, 1, 1, 1, 0, 0, 0},
-	{1, 1, 1, 1, 1, 1, 0, 1, 0},
-	{1, 1, 1, 1, 1, 1, 1, 0, 0},
-	{1, 1, 1, 1, 1, 1, 1, 1, 0},
+const (
+	// MinTemplateLength and MaxTemplateLength bound the template length m,
+	// matching the template libraries shipped with the NIST STS (template2..template21).
+	MinTemplateLength = 2
+	MaxTemplateLength = 21
+
+	// maxTemplates mirrors MAXNUMOFTEMPLATES of the NIST STS. When more aperiodic
+	// templates exist (m >= 10) only every SKIP-th one is tested, up to this many.
+	maxTemplates = 148
+)
+
+var (
+	templateOnce  [MaxTemplateLength + 1]sync.Once
+	templateCache [MaxTemplateLength + 1][]uint32
+	// slotCache maps every m-bit word to the index of the template it equals, or -1.
+	slotCache [MaxTemplateLength + 1][]int16
+)
+
+// nonPeriodicTemplates returns the templates tested for length m, in the same order and
+// with the same selection as the NIST STS template files. Each template is an m-bit word,
+// most significant bit first. Results are computed once per m and cached together with
+// the lookup table of templateSlots.
+func nonPeriodicTemplates(m int) []uint32 {
+	templateOnce[m].Do(func() {
+		all := aperiodicWords(m)
+		skip := 1
+		if len(all) >= maxTemplates {
+			skip = len(all) / maxTemplates
+		}
+		count := min(len(all)/skip, maxTemplates)
+		selected := make([]uint32, count)
+		for i := range selected {
+			selected[i] = all[i*skip]
+		}
+		templateCache[m] = selected
+
+		slots := make([]int16, 1<<m)
+		for i := range slots {
+			slots[i] = -1
+		}
+		for t, template := range selected {
+			slots[template] = int16(t) //nolint:gosec // at most maxTemplates
+		}
+		slotCache[m] = slots
+	})
+	return templateCache[m]
+}
+
+// templateSlots returns the lookup table from m-bit words to the index of the equal
+// template in nonPeriodicTemplates(m), or -1 if the word is not tested.
+func templateSlots(m int) []int16 {
+	nonPeriodicTemplates(m)
+	return slotCache[m]
+}
+
+// aperiodicWords enumerates, in ascending order, every m-bit word B that cannot overlap
+// a shifted copy of itself, i.e. no proper prefix of B equals the suffix of the same length.
+func aperiodicWords(m int) []uint32 {
+	var words []uint32
+	for w := uint32(0); w < 1<<m; w++ {
+		aperiodic := true
+		for k := 1; k < m; k++ {
+			if w>>k == w&(1<<(m-k)-1) {
+				aperiodic = false
+				break
+			}
+		}
+		if aperiodic {
+			words = append(words, w)
+		}
+	}
+	return words
 }
@@ -1,25 +1,24 @@
 package nist
 
 import (
+	"context"
+	"errors"
 	"testing"
+
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/testrand"
 )
 
 func TestNonOverlappingTemplate(t *testing.T) {
 	t.Run("wrong_template_size", func(t *testing.T) {
 		data := make([]byte, 1000)
-		p, pass := NonOverlappingTemplateTest(data, 5)
+		p, pass := NonOverlappingTemplateTest(data, MaxTemplateLength+1)
 		if p != 0 || pass {
 			t.Fatalf("expected reject on wrong template size, got p=%.6f pass=%v", p, pass)
 		}
 	})
 
 	t.Run("valid_input", func(t *testing.T) {
-		data := make([]byte, 10000)
-		state := uint64(99)
-		for i := range data {
-			state = state*6364136223846793005 + 1442695040888963407
-			data[i] = byte(state >> 56)
-		}
+		data := testrand.Bytes(10000, 99)
 		p, pass := NonOverlappingTemplateTest(data, 9)
 		if p < 0 || p > 1 {
 			t.Fatalf("p-value out of range: %.6f", p)
@@ -28,4 +27,128 @@ func TestNonOverlappingTemplate(t *testing.T) {
 			t.Fatalf("pass flag inconsistent with p-value %.6f", p)
 		}
 	})
+	t.Run("detailed_per_template", func(t *testing.T) {
+		data := testrand.Bytes(10000, 99)
+		res, err := NonOverlappingTemplateTestSequence(context.Background(), BitSequenceFromBytes(data), 9, 8)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(res.Templates) != 148 {
+			t.Fatalf("expected 148 templates, got %d", len(res.Templates))
+		}
+		if res.Templates[0].Template != "000000001" || res.Templates[147].Template != "111111110" {
+			t.Fatalf("unexpected template order: first=%s last=%s", res.Templates[0].Template, res.Templates[147].Template)
+		}
+		for _, tr := range res.Templates {
+			if len(tr.Counts) != res.NumBlocks {
+				t.Fatalf("template %s: expected %d block counts, got %d", tr.Template, res.NumBlocks, len(tr.Counts))
+			}
+			if tr.PValue < 0 || tr.PValue > 1 || tr.Passed != (tr.PValue >= Alpha) {
+				t.Fatalf("template %s: inconsistent p-value %.6f pass=%v", tr.Template, tr.PValue, tr.Passed)
+			}
+		}
+		p, _ := NonOverlappingTemplateTest(data, 9)
+		if p != res.CombinedPValue() || p < res.MinPValue() {
+			t.Fatalf("summary p-value %.6f differs from combined p-value %.6f", p, res.CombinedPValue())
+		}
+	})
+
+	t.Run("detailed_rejects_unsupported_length", func(t *testing.T) {
+		if _, err := NonOverlappingTemplateTestSequence(context.Background(), BitSequenceFromBytes(make([]byte, 1000)), 1, 8); err == nil {
+			t.Fatal("expected error for unsupported template length")
+		}
+		if _, err := NonOverlappingTemplateTestSequence(context.Background(), BitSequenceFromBytes(make([]byte, 1000)), 9, 0); err == nil {
+			t.Fatal("expected error for zero blocks")
+		}
+		if _, err := NonOverlappingTemplateTestSequence(context.Background(), BitSequenceFromBytes(make([]byte, 1)), 9, 8); !errors.Is(err, ErrNotApplicable) {
+			t.Fatalf("expected ErrNotApplicable for blocks shorter than the template, got %v", err)
+		}
+	})
+
+	t.Run("other_lengths_and_block_counts", func(t *testing.T) {
+		data := testrand.Bytes(20000, 7)
+		for _, tc := range []struct{ m, N, templates int }{
+			{2, 8, 2},
+			{5, 4, 12},
+			{10, 8, 148},
+			{14, 16, 148},
+		} {
+			res, err := NonOverlappingTemplateTestSequence(context.Background(), BitSequenceFromBytes(data), tc.m, tc.N)
+			if err != nil {
+				t.Fatalf("m=%d N=%d: unexpected error: %v", tc.m, tc.N, err)
+			}
+			if len(res.Templates) != tc.templates || res.NumBlocks != tc.N || res.BlockLength != len(data)*8/tc.N {
+				t.Fatalf("m=%d N=%d: unexpected shape %d templates, N=%d, M=%d", tc.m, tc.N, len(res.Templates), res.NumBlocks, res.BlockLength)
+			}
+			for _, tr := range res.Templates {
+				if len(tr.Template) != tc.m || len(tr.Counts) != tc.N || tr.PValue < 0 || tr.PValue > 1 {
+					t.Fatalf("m=%d N=%d: bad template result %+v", tc.m, tc.N, tr)
+				}
+			}
+		}
+	})
+}
+
+func TestNonPeriodicTemplates(t *testing.T) {
+	// Number of aperiodic templates per length, as listed by the NIST STS (numOfTemplates).
+	aperiodic := []int{0, 0, 2, 4, 6, 12, 20, 40, 74, 148, 284, 568, 1116, 2232, 4424, 8848, 17622, 35244, 70340, 140680, 281076, 562152}
+	for m := MinTemplateLength; m <= MaxTemplateLength; m++ {
+		if got := len(aperiodicWords(m)); got != aperiodic[m] {
+			t.Errorf("m=%d: expected %d aperiodic templates, got %d", m, aperiodic[m], got)
+		}
+	}
+
+	// STS tests every SKIP-th template, up to 148.
+	tpl := nonPeriodicTemplates(11)
+	if len(tpl) != 148 {
+		t.Fatalf("expected 148 templates for m=11, got %d", len(tpl))
+	}
+	all := aperiodicWords(11)
+	if tpl[0] != all[0] || tpl[1] != all[3] || tpl[147] != all[147*3] {
+		t.Fatalf("unexpected template selection for m=11")
+	}
+
+	if got := templateString(nonPeriodicTemplates(9)[0], 9); got != "000000001" {
+		t.Fatalf("unexpected first m=9 template %s", got)
+	}
+}
+
+// TestCountTemplates checks the single-pass count against a scan of every template on
+// its own, with block lengths that do not align with the packed words.
+func TestCountTemplates(t *testing.T) {
+	data := testrand.Bytes(3001, 11)
+	bits, err := NewBitSequence(data, len(data)*8-3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct{ m, N int }{{2, 3}, {3, 7}, {9, 8}, {13, 5}, {21, 1}} {
+		M := bits.Len() / tc.N
+		counts, err := countTemplates(context.Background(), bits, tc.m, tc.N, M)
+		if err != nil {
+			t.Fatalf("m=%d: %v", tc.m, err)
+		}
+		for i, template := range nonPeriodicTemplates(tc.m) {
+			for block := range tc.N {
+				// Restart the window after every match
+				want, filled, window := 0, 0, uint32(0)
+				for j := block * M; j < (block+1)*M; j++ {
+					window = (window<<1 | uint32(bits.Bit(j))) & (1<<tc.m - 1)
+					if filled++; filled >= tc.m && window == template {
+						want++
+						filled = 0
+					}
+				}
+				if got := counts[i*tc.N+block]; got != want {
+					t.Fatalf("m=%d template %s block %d: expected %d matches, got %d", tc.m, templateString(template, tc.m), block, want, got)
+				}
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := countTemplates(ctx, bits, 9, 8, bits.Len()/8); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
 }
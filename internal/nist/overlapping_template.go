@@ -1,6 +1,7 @@
 package nist
 
 import (
+	"context"
 	"math"
 
 	"gonum.org/v1/gonum/mathext"
@@ -9,17 +10,23 @@ import (
 // OverlappingTemplateTest implements the NIST Overlapping Template Matching test.
 // It returns the p-value and whether it passes at Alpha.
 func OverlappingTemplateTest(bitstream []byte, m int) (float64, bool) {
-	bits := expandBits(bitstream)
-	n := len(bits)
-	if n < m {
-		return 0, false
+	o, _ := OverlappingTemplateTestSequence(context.Background(), BitSequenceFromBytes(bitstream), m)
+	return o.PValue, o.Passed()
+}
+
+// OverlappingTemplateTestSequence runs the Overlapping Template test on a BitSequence of any length.
+// The outcome's Counts hold nu_0..nu_5, the number of blocks by count of matches.
+func OverlappingTemplateTestSequence(ctx context.Context, bits BitSequence, m int) (Outcome, error) {
+	n := bits.n
+	if m < 1 {
+		return errorOutcome("invalid template length m=%d", m), nil
 	}
 
 	const K = 5
 	M := 1032
 	N := n / M
-	if N == 0 {
-		return 0, false
+	if N == 0 || n < m {
+		return notApplicable("insufficient bits: got %d, need at least %d", n, M), nil
 	}
 
 	lambda := float64(M-m+1) / math.Pow(2, float64(m))
@@ -34,12 +41,16 @@ func OverlappingTemplateTest(bitstream []byte, m int) (float64, bool) {
 	pi[K] = 1 - sum
 
 	nu := make([]int, K+1)
+	done := ctx.Done()
 	for block := 0; block < N; block++ {
+		if isDone(done) {
+			return Outcome{}, ctx.Err()
+		}
 		wObs := 0
 		for j := 0; j < M-m+1; j++ {
 			match := true
 			for k := 0; k < m; k++ {
-				if bits[block*M+j+k] == 0 {
+				if bits.Bit(block*M+j+k) == 0 {
 					match = false
 					break
 				}
@@ -64,7 +75,9 @@ func OverlappingTemplateTest(bitstream []byte, m int) (float64, bool) {
 	}
 
 	pValue := mathext.GammaIncRegComp(float64(K)/2.0, chi2/2.0)
-	return pValue, pValue >= Alpha
+	o := statisticOutcome(pValue, chi2)
+	o.Counts = nu
+	return o, nil
 }
 
 func prHelper(u int, eta float64) float64 {
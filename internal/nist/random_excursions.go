@@ -1,44 +1,71 @@
 package nist
 
 import (
+	"context"
+	"fmt"
 	"math"
 
 	"gonum.org/v1/gonum/mathext"
 )
 
-// RandomExcursionsTest implements the NIST Random Excursions test.
-// It returns the minimum p-value across the 8 states and whether it passes at Alpha.
-func RandomExcursionsTest(bitstream []byte) (float64, bool) {
-	bits := expandBits(bitstream)
-	n := len(bits)
+// ExcursionState is the outcome of the Random Excursions test for a single state x.
+type ExcursionState struct {
+	State int
+	// Nu holds nu_k(x) for k = 0..5: the number of cycles visiting x exactly k times
+	// (k = 5 counts cycles with five or more visits).
+	Nu        [6]int
+	ChiSquare float64
+	PValue    float64
+	Passed    bool
+}
 
-	S := make([]int, n)
-	S[0] = 2*int(bits[0]) - 1
-	J := 0
-	for i := 1; i < n; i++ {
-		S[i] = S[i-1] + 2*int(bits[i]) - 1
-		if S[i] == 0 {
-			J++
+// RandomExcursionsResult is the detailed outcome of the Random Excursions test.
+type RandomExcursionsResult struct {
+	// Cycles is J, the number of zero-crossing cycles of the random walk.
+	Cycles int
+	States []ExcursionState
+}
+
+// MinPValue returns the smallest p-value across all states.
+func (r RandomExcursionsResult) MinPValue() float64 {
+	minP := 1.0
+	for _, s := range r.States {
+		if s.PValue < minP {
+			minP = s.PValue
 		}
 	}
-	if S[n-1] != 0 {
-		J++
-	}
+	return minP
+}
 
-	constraint := int(math.Max(0.005*math.Sqrt(float64(n)), 500))
-	if J < constraint {
+// CombinedPValue returns the Sidak-corrected minimum 1-(1-p_min)^k over the k = 8 states, the p-value that
+// decides whether the test as a whole passes.
+func (r RandomExcursionsResult) CombinedPValue() float64 {
+	return sidakPValue(r.MinPValue(), len(r.States))
+}
+
+// RandomExcursionsTest implements the NIST Random Excursions test.
+// It returns the Sidak-corrected minimum p-value across the 8 states and whether it
+// passes at Alpha.
+func RandomExcursionsTest(bitstream []byte) (float64, bool) {
+	res, err := RandomExcursionsTestSequence(context.Background(), BitSequenceFromBytes(bitstream))
+	if err != nil {
 		return 0, false
 	}
 
-	cycle := make([]int, J+1)
-	idx := 1
-	for i := 0; i < n; i++ {
-		if S[i] == 0 {
-			cycle[idx] = i
-			idx++
-		}
+	p := res.CombinedPValue()
+	return p, p >= Alpha
+}
+
+// RandomExcursionsTestSequence runs the Random Excursions test on a BitSequence of any
+// length and reports the visit histogram, chi-square and p-value of each state
+// x = -4..-1, 1..4. It returns an error wrapping ErrNotApplicable when the walk has fewer
+// than max(0.005*sqrt(n), 500) cycles. The walk is evaluated in a single pass without
+// storing the partial sums.
+func RandomExcursionsTestSequence(ctx context.Context, bits BitSequence) (RandomExcursionsResult, error) {
+	n := bits.n
+	if n == 0 {
+		return RandomExcursionsResult{}, fmt.Errorf("%w: empty bitstream", ErrNotApplicable)
 	}
-	cycle[J] = n - 1
 
 	stateX := []int{-4, -3, -2, -1, 1, 2, 3, 4}
 	pi := [][]float64{
@@ -49,60 +76,64 @@ func RandomExcursionsTest(bitstream []byte) (float64, bool) {
 		{0.875, 0.015625, 0.013671875, 0.01196289063, 0.0104675293, 0.0732727051},
 	}
 
-	minP := 1.0
-	cycleStart := 0
-	cycleStop := cycle[1]
-
-	nu := make([][]float64, 6)
-	for i := range nu {
-		nu[i] = make([]float64, 8)
-	}
-
-	for j := 1; j <= J; j++ {
-		counter := make([]int, 8)
-		for i := cycleStart; i <= cycleStop; i++ {
-			val := S[i]
-			if val >= 1 && val <= 4 {
-				counter[val+3]++
-			} else if val <= -1 && val >= -4 {
-				idx := val + 4
-				if idx >= 0 && idx < len(counter) {
-					counter[idx]++
-				}
-			}
+	// A cycle ends at each return to zero and at the end of the sequence. counter
+	// holds the visits to each state within the current cycle, nu[k][i] the number of
+	// cycles with exactly k (5 = at least 5) visits to state stateX[i].
+	var nu [6][8]float64
+	var counter [8]int
+	J := 0
+	S := 0
+	done := ctx.Done()
+	for i := 0; i < n; i++ {
+		if i%ctxCheckInterval == 0 && isDone(done) {
+			return RandomExcursionsResult{}, ctx.Err()
 		}
-
-		cycleStart = cycle[j] + 1
-		if j < J {
-			cycleStop = cycle[j+1]
+		S += 2*int(bits.Bit(i)) - 1
+		switch {
+		case S >= 1 && S <= 4:
+			counter[S+3]++
+		case S <= -1 && S >= -4:
+			counter[S+4]++
 		}
 
-		for i := 0; i < 8; i++ {
-			if counter[i] >= 0 && counter[i] <= 4 {
-				nu[counter[i]][i]++
-			} else if counter[i] >= 5 {
-				nu[5][i]++
-			}
+		if S != 0 && i != n-1 {
+			continue
+		}
+		J++
+		for k := range counter {
+			nu[min(counter[k], 5)][k]++
+			counter[k] = 0
 		}
 	}
 
+	constraint := int(math.Max(0.005*math.Sqrt(float64(n)), 500))
+	if J < constraint {
+		return RandomExcursionsResult{Cycles: J}, fmt.Errorf("%w: insufficient cycles J=%d < %d", ErrNotApplicable, J, constraint)
+	}
+
+	res := RandomExcursionsResult{
+		Cycles: J,
+		States: make([]ExcursionState, 0, len(stateX)),
+	}
 	for i := 0; i < 8; i++ {
 		x := stateX[i]
 		idx := int(math.Abs(float64(x)))
 		if idx >= len(pi) {
 			continue
 		}
+		state := ExcursionState{State: x}
 		sum := 0.0
 		for k := 0; k < 6; k++ {
 			expected := float64(J) * pi[idx][k] // #nosec G602: idx guarded within pi bounds
 			diff := nu[k][i] - expected
 			sum += diff * diff / expected
+			state.Nu[k] = int(nu[k][i])
 		}
-		p := mathext.GammaIncRegComp(2.5, sum/2.0)
-		if p < minP {
-			minP = p
-		}
+		state.ChiSquare = sum
+		state.PValue = mathext.GammaIncRegComp(2.5, sum/2.0)
+		state.Passed = state.PValue >= Alpha
+		res.States = append(res.States, state)
 	}
 
-	return minP, minP >= Alpha
+	return res, nil
 }
@@ -1,6 +1,8 @@
 package nist
 
 import (
+	"context"
+	"errors"
 	"testing"
 )
 
@@ -30,4 +32,66 @@ func TestRandomExcursions(t *testing.T) {
 			t.Fatalf("expecting periodic walk to fail uniformity, got p=%.6f", p)
 		}
 	})
+	t.Run("detailed_per_state", func(t *testing.T) {
+		data := make([]byte, 125) // 1000 bits alternating: S oscillates between 1 and 0
+		for i := range data {
+			data[i] = 0xAA
+		}
+		res, err := RandomExcursionsTestSequence(context.Background(), BitSequenceFromBytes(data))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res.Cycles != 500 {
+			t.Fatalf("expected 500 cycles, got %d", res.Cycles)
+		}
+		if len(res.States) != 8 || res.States[0].State != -4 || res.States[7].State != 4 {
+			t.Fatalf("unexpected states: %+v", res.States)
+		}
+		for _, st := range res.States {
+			total := 0
+			for _, c := range st.Nu {
+				total += c
+			}
+			if total != res.Cycles {
+				t.Errorf("state %d: nu histogram sums to %d, want %d", st.State, total, res.Cycles)
+			}
+			want := [6]int{500}
+			if st.State == 1 {
+				want = [6]int{0, 500}
+			}
+			if st.Nu != want {
+				t.Errorf("state %d: nu=%v, want %v", st.State, st.Nu, want)
+			}
+		}
+		if res.MinPValue() != res.States[4].PValue {
+			t.Fatalf("expected minimum p-value at x=1, got %.6f", res.MinPValue())
+		}
+	})
+
+	t.Run("detailed_not_applicable", func(t *testing.T) {
+		data := make([]byte, 100)
+		res, err := RandomExcursionsTestSequence(context.Background(), BitSequenceFromBytes(data))
+		if !errors.Is(err, ErrNotApplicable) {
+			t.Fatalf("expected ErrNotApplicable, got %v", err)
+		}
+		if res.Cycles != 1 || len(res.States) != 0 {
+			t.Fatalf("unexpected result: %+v", res)
+		}
+	})
+
+	t.Run("constant_memory", func(t *testing.T) {
+		data := make([]byte, 125000)
+		for i := range data {
+			data[i] = 0xAA
+		}
+		// Only the result is allocated; the walk is not stored.
+		allocs := testing.AllocsPerRun(3, func() {
+			if _, err := RandomExcursionsTestSequence(context.Background(), BitSequenceFromBytes(data)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+		if allocs > 4 {
+			t.Fatalf("expected a constant number of allocations, got %.0f", allocs)
+		}
+	})
 }
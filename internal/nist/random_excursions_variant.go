@@ -1,46 +1,113 @@
 package nist
 
-import "math"
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// ExcursionVariantState is the outcome of the Random Excursions Variant test for a single state x.
+type ExcursionVariantState struct {
+	State int
+	// Visits is xi(x), the total number of times the random walk visits x.
+	Visits int
+	// Statistic is |xi(x) - J| / sqrt(2J(4|x| - 2)).
+	Statistic float64
+	PValue    float64
+	Passed    bool
+}
+
+// RandomExcursionsVariantResult is the detailed outcome of the Random Excursions Variant test.
+type RandomExcursionsVariantResult struct {
+	// Cycles is J, the number of zero-crossing cycles of the random walk.
+	Cycles int
+	States []ExcursionVariantState
+}
+
+// MinPValue returns the smallest p-value across all states.
+func (r RandomExcursionsVariantResult) MinPValue() float64 {
+	minP := 1.0
+	for _, s := range r.States {
+		if s.PValue < minP {
+			minP = s.PValue
+		}
+	}
+	return minP
+}
+
+// CombinedPValue returns the Sidak-corrected minimum 1-(1-p_min)^k over the k = 18 states, the p-value that
+// decides whether the test as a whole passes.
+func (r RandomExcursionsVariantResult) CombinedPValue() float64 {
+	return sidakPValue(r.MinPValue(), len(r.States))
+}
 
 // RandomExcursionsVariantTest implements the NIST Random Excursions Variant test.
-// It returns the minimum p-value across the 18 states and whether it passes at Alpha.
+// It returns the Sidak-corrected minimum p-value across the 18 states and whether it
+// passes at Alpha.
 func RandomExcursionsVariantTest(bitstream []byte) (float64, bool) {
-	bits := expandBits(bitstream)
-	n := len(bits)
+	res, err := RandomExcursionsVariantTestSequence(context.Background(), BitSequenceFromBytes(bitstream))
+	if err != nil {
+		return 0, false
+	}
+
+	p := res.CombinedPValue()
+	return p, p >= Alpha
+}
 
-	S := make([]int, n)
-	S[0] = 2*int(bits[0]) - 1
+// RandomExcursionsVariantTestSequence runs the Random Excursions Variant test on a
+// BitSequence of any length and reports the visit count, statistic and p-value of each
+// state x = -9..-1, 1..9. It returns an error wrapping ErrNotApplicable when the walk has
+// fewer than max(0.005*sqrt(n), 500) cycles. The walk is evaluated in a single pass
+// without storing the partial sums.
+func RandomExcursionsVariantTestSequence(ctx context.Context, bits BitSequence) (RandomExcursionsVariantResult, error) {
+	n := bits.n
+	if n == 0 {
+		return RandomExcursionsVariantResult{}, fmt.Errorf("%w: empty bitstream", ErrNotApplicable)
+	}
+
+	// visits[x+9] counts the visits to state x over the whole walk.
+	var visits [19]int
 	J := 0
-	for i := 1; i < n; i++ {
-		S[i] = S[i-1] + 2*int(bits[i]) - 1
-		if S[i] == 0 {
+	S := 0
+	done := ctx.Done()
+	for i := 0; i < n; i++ {
+		if i%ctxCheckInterval == 0 && isDone(done) {
+			return RandomExcursionsVariantResult{}, ctx.Err()
+		}
+		S += 2*int(bits.Bit(i)) - 1
+		if S == 0 {
 			J++
+		} else if S >= -9 && S <= 9 {
+			visits[S+9]++
 		}
 	}
-	if S[n-1] != 0 {
+	if S != 0 {
 		J++
 	}
 
 	constraint := int(math.Max(0.005*math.Sqrt(float64(n)), 500))
 	if J < constraint {
-		return 0, false
+		return RandomExcursionsVariantResult{Cycles: J}, fmt.Errorf("%w: insufficient cycles J=%d < %d", ErrNotApplicable, J, constraint)
 	}
 
 	stateX := []int{-9, -8, -7, -6, -5, -4, -3, -2, -1, 1, 2, 3, 4, 5, 6, 7, 8, 9}
-	minP := 1.0
+	res := RandomExcursionsVariantResult{
+		Cycles: J,
+		States: make([]ExcursionVariantState, 0, len(stateX)),
+	}
 
 	for _, x := range stateX {
-		count := 0
-		for i := 0; i < n; i++ {
-			if S[i] == x {
-				count++
-			}
-		}
-		p := math.Erfc(math.Abs(float64(count)-float64(J)) / math.Sqrt(2*float64(J)*(4*math.Abs(float64(x))-2)))
-		if p < minP {
-			minP = p
-		}
+		count := visits[x+9]
+		stat := math.Abs(float64(count)-float64(J)) / math.Sqrt(2*float64(J)*(4*math.Abs(float64(x))-2))
+		p := math.Erfc(stat)
+		res.States = append(res.States, ExcursionVariantState{
+			State:     x,
+			Visits:    count,
+			Statistic: stat,
+			PValue:    p,
+			Passed:    p >= Alpha,
+		})
 	}
 
-	return minP, minP >= Alpha
+	return res, nil
 }
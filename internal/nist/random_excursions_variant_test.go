@@ -1,6 +1,8 @@
 package nist
 
 import (
+	"context"
+	"errors"
 	"testing"
 )
 
@@ -26,4 +28,37 @@ func TestRandomExcursionsVariant(t *testing.T) {
 			t.Fatalf("expecting periodic walk to fail uniformity, got p=%.6f", p)
 		}
 	})
+	t.Run("detailed_per_state", func(t *testing.T) {
+		data := make([]byte, 125) // 1000 bits alternating: S oscillates between 1 and 0
+		for i := range data {
+			data[i] = 0xAA
+		}
+		res, err := RandomExcursionsVariantTestSequence(context.Background(), BitSequenceFromBytes(data))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res.Cycles != 500 || len(res.States) != 18 {
+			t.Fatalf("unexpected result: cycles=%d states=%d", res.Cycles, len(res.States))
+		}
+		for _, st := range res.States {
+			want := 0
+			if st.State == 1 {
+				want = 500
+			}
+			if st.Visits != want {
+				t.Errorf("state %d: visits=%d, want %d", st.State, st.Visits, want)
+			}
+		}
+		if x1 := res.States[9]; x1.State != 1 || x1.Statistic != 0 || x1.PValue != 1 || !x1.Passed {
+			t.Fatalf("unexpected x=1 result: %+v", x1)
+		}
+	})
+
+	t.Run("detailed_not_applicable", func(t *testing.T) {
+		data := make([]byte, 100)
+		_, err := RandomExcursionsVariantTestSequence(context.Background(), BitSequenceFromBytes(data))
+		if !errors.Is(err, ErrNotApplicable) {
+			t.Fatalf("expected ErrNotApplicable, got %v", err)
+		}
+	})
 }
@@ -1,142 +1,355 @@
 package nist
 
 import (
+	"context"
 	"fmt"
+	"runtime"
+	"sync"
+	"time"
 )
 
 // TestResult represents the outcome of a single NIST test.
+// For multi-valued tests PValue is the Sidak-corrected minimum across SubResults; the
+// Linear Complexity test reports its per-block details in a single sub-result.
 type TestResult struct {
-	Name       string
-	PValue     float64
-	Passed     bool
-	Proportion float64
+	Name   string
+	PValue float64
+	// Passed is true iff Status is StatusPassed.
+	Passed bool
+	Status Status
+	// Warning explains a StatusNotApplicable or StatusError result.
 	Warning    string
+	SubResults []SubResult
+	// Duration is the wall time spent in the test itself.
+	Duration time.Duration
+}
+
+// MultiValued reports whether the test reports one statistic per sub-result. The
+// single sub-result of the Linear Complexity test only details its one p-value.
+func (r TestResult) MultiValued() bool {
+	return len(r.SubResults) > 0 && r.Name != "linear_complexity"
+}
+
+// SubResult is one of several statistics reported by a multi-valued test,
+// e.g. a single template of the Non-overlapping Template test.
+type SubResult struct {
+	Name string
+	// State is the random walk state x for the Random Excursions tests.
+	State     int
+	PValue    float64
+	Passed    bool
+	Statistic float64
+	Counts    []int
+	// Values holds further statistics, e.g. psi^2_m, psi^2_{m-1} and psi^2_{m-2} for
+	// the Serial test or the linear complexity L_i of every block.
+	Values []float64
 }
 
 const (
 	// MinBits is the minimum required bits for the full 15-test suite (Universal test).
 	MinBits = 387840
-	// MaxBits is a safety cap to avoid unbounded allocations.
+	// MaxBits is the default safety cap to avoid unbounded allocations (see SuiteConfig.MaxBits).
 	MaxBits = 10000000
 )
 
-// RunAllTests executes the full NIST SP 800-22 battery in pure Go.
+// dftBytesPerBit is the working memory of the Discrete Fourier Transform test per input
+// bit: the +/-1 series, the scratch space of the transform and its n/2+1 coefficients.
+const dftBytesPerBit = 40
+
+// EstimateMemory returns the approximate peak memory in bytes needed by RunAllTestsContext
+// for a sequence of n bits, including the packed input. All tests except the Discrete Fourier
+// Transform read the packed input in place; the transform needs float64 working storage.
+func EstimateMemory(n int) uint64 {
+	return uint64(n)/8 + dftBytesPerBit*uint64(n) //nolint:gosec // n is a non-negative bit count
+}
+
+// RunAllTests executes the full NIST SP 800-22 battery in pure Go with the default parameters.
 func RunAllTests(bitstream []byte) ([]TestResult, error) {
-	numBits := len(bitstream) * 8
+	return RunAllTestsWithConfig(bitstream, SuiteConfig{})
+}
+
+// RunAllTestsWithConfig executes the full NIST SP 800-22 battery using the parameters in cfg.
+// Zero fields in cfg select the defaults; explicit values are validated against the
+// SP 800-22 recommendations and rejected with an error wrapping ErrInvalidParameter.
+func RunAllTestsWithConfig(bitstream []byte, cfg SuiteConfig) ([]TestResult, error) {
+	return RunAllTestsContext(context.Background(), bitstream, cfg)
+}
+
+// RunAllTestsContext is like RunAllTestsWithConfig but stops once ctx is done, in which
+// case it returns ctx.Err() (context.Canceled or context.DeadlineExceeded) and no results.
+func RunAllTestsContext(ctx context.Context, bitstream []byte, cfg SuiteConfig) ([]TestResult, error) {
+	seq, err := NewBitSequenceOrder(bitstream, len(bitstream)*8, cfg.BitOrder)
+	if err != nil {
+		return nil, invalidParam("%v", err)
+	}
+	return RunAllTestsSequence(ctx, seq, cfg)
+}
+
+// RunAllTestsSequence is like RunAllTestsContext for a BitSequence, whose length need not
+// be a multiple of 8.
+func RunAllTestsSequence(ctx context.Context, seq BitSequence, cfg SuiteConfig) ([]TestResult, error) {
+	numBits := seq.Len()
 	if numBits < MinBits {
 		return nil, fmt.Errorf("insufficient bits: got %d, need at least %d", numBits, MinBits)
 	}
-	if numBits > MaxBits {
-		return nil, fmt.Errorf("too many bits: got %d, maximum %d", numBits, MaxBits)
+	if maxBits := cfg.maxBits(); numBits > maxBits {
+		return nil, fmt.Errorf("too many bits: got %d, maximum %d", numBits, maxBits)
 	}
-
-	results := make([]TestResult, 0, 15)
-
-	appendResult := func(name string, p float64, passed bool, warning string) {
-		r := TestResult{
-			Name:       name,
-			PValue:     p,
-			Passed:     passed,
-			Proportion: 0,
-			Warning:    warning,
-		}
-		if passed {
-			r.Proportion = 1.0
-		}
-		results = append(results, r)
+	if err := cfg.Validate(numBits); err != nil {
+		return nil, err
 	}
+	cfg = cfg.WithDefaults()
 
-	// 1. Frequency (Monobit)
-	p, pass := FrequencyTest(bitstream)
-	appendResult("frequency_monobit", p, pass, "")
+	// The tests below only read from the shared input.
+	in := suiteInput{bits: seq, cfg: cfg}
 
-	// 2. Block Frequency (M = 128)
-	p, pass = BlockFrequencyTest(bitstream, 128)
-	warn := ""
-	if !pass && p == 0 {
-		warn = "insufficient bits for block size"
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
 	}
-	appendResult("block_frequency", p, pass, warn)
-
-	// 3. Cumulative Sums
-	p, pass = CumulativeSumsTest(bitstream)
-	appendResult("cumulative_sums", p, pass, "")
+	workers = min(workers, len(suiteTests))
 
-	// 4. Runs
-	p, pass = RunsTest(bitstream)
-	warn = ""
-	if p == 0 && !pass {
-		warn = "Pi estimator criteria not met"
+	// Each worker writes only to its own slot, so the ordering follows suiteTests.
+	results := make([]TestResult, len(suiteTests))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				t := suiteTests[i]
+				start := time.Now()
+				o, subResults, err := t.run(ctx, &in)
+				if err != nil {
+					// ctx is done; the dispatcher stops handing out jobs.
+					return
+				}
+				results[i] = newTestResult(t.name, o, subResults)
+				results[i].Duration = time.Since(start)
+			}
+		}()
 	}
-	appendResult("runs", p, pass, warn)
+dispatch:
+	for i := range suiteTests {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
 
-	// 5. Longest Run of Ones
-	p, pass = LongestRunOfOnesTest(bitstream)
-	warn = ""
-	if p == 0 && !pass {
-		warn = "insufficient bits for test"
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
-	appendResult("longest_run", p, pass, warn)
+	return results, nil
+}
 
-	// 6. Binary Matrix Rank
-	p, pass = BinaryMatrixRankTest(bitstream)
-	warn = ""
-	if p == 0 && !pass {
-		warn = "insufficient bits for 32x32 matrices"
+// CombinedPValue returns the Sidak-corrected minimum p-value of the completed results
+// and their number. Deciding a whole suite run on it at Alpha rejects random data with
+// probability close to Alpha, whereas requiring every test to pass would reject about
+// 1-(1-Alpha)^15 of it. Without completed results it returns 1 and 0.
+func CombinedPValue(results []TestResult) (float64, int) {
+	minP, k := 1.0, 0
+	for _, r := range results {
+		if r.Status.Completed() {
+			minP = min(minP, r.PValue)
+			k++
+		}
 	}
-	appendResult("binary_matrix_rank", p, pass, warn)
+	return sidakPValue(minP, k), k
+}
 
-	// 7. Discrete Fourier Transform
-	p, pass = DiscreteFourierTransformTest(bitstream)
-	appendResult("discrete_fourier_transform", p, pass, "")
+// suiteInput is the read-only input shared by all tests of a suite run.
+type suiteInput struct {
+	bits BitSequence
+	cfg  SuiteConfig
+}
 
-	// 8. Non-overlapping Template (m = 9)
-	p, pass = NonOverlappingTemplateTest(bitstream, 9)
-	warn = ""
-	if p == 0 && !pass {
-		warn = "only m=9 supported or insufficient bits"
-	}
-	appendResult("non_overlapping_template", p, pass, warn)
+// suiteTests lists the 15 tests in the order in which they are reported.
+var suiteTests = []struct {
+	name string
+	// run returns an error only if ctx is done before the test completes.
+	run func(ctx context.Context, in *suiteInput) (Outcome, []SubResult, error)
+}{
+	{"frequency_monobit", func(ctx context.Context, in *suiteInput) (Outcome, []SubResult, error) {
+		o, err := FrequencyTestSequence(ctx, in.bits)
+		return o, nil, err
+	}},
+	{"block_frequency", func(ctx context.Context, in *suiteInput) (Outcome, []SubResult, error) {
+		o, err := BlockFrequencyTestSequence(ctx, in.bits, in.cfg.BlockFrequencyBlockLength)
+		return o, nil, err
+	}},
+	// One sub-result per direction
+	{"cumulative_sums", func(ctx context.Context, in *suiteInput) (Outcome, []SubResult, error) {
+		r, err := CumulativeSumsTestSequence(ctx, in.bits)
+		if err != nil {
+			return errOutcome(err), nil, ctx.Err()
+		}
+		return pValueOutcome(r.CombinedPValue()), cusumSubResults(r), nil
+	}},
+	{"runs", func(ctx context.Context, in *suiteInput) (Outcome, []SubResult, error) {
+		o, err := RunsTestSequence(ctx, in.bits)
+		return o, nil, err
+	}},
+	{"longest_run", func(ctx context.Context, in *suiteInput) (Outcome, []SubResult, error) {
+		o, err := LongestRunOfOnesTestSequence(ctx, in.bits)
+		return o, nil, err
+	}},
+	{"binary_matrix_rank", func(ctx context.Context, in *suiteInput) (Outcome, []SubResult, error) {
+		o, err := BinaryMatrixRankTestSequence(ctx, in.bits, in.cfg.BinaryMatrixRows, in.cfg.BinaryMatrixColumns)
+		return o, nil, err
+	}},
+	{"discrete_fourier_transform", func(ctx context.Context, in *suiteInput) (Outcome, []SubResult, error) {
+		o, err := DiscreteFourierTransformTestSequence(ctx, in.bits)
+		return o, nil, err
+	}},
+	// One sub-result per template
+	{"non_overlapping_template", func(ctx context.Context, in *suiteInput) (Outcome, []SubResult, error) {
+		r, err := NonOverlappingTemplateTestSequence(ctx, in.bits, in.cfg.NonOverlappingTemplateLength, in.cfg.NonOverlappingTemplateBlocks)
+		if err != nil {
+			return errOutcome(err), nil, ctx.Err()
+		}
+		return pValueOutcome(r.CombinedPValue()), templateSubResults(r), nil
+	}},
+	{"overlapping_template", func(ctx context.Context, in *suiteInput) (Outcome, []SubResult, error) {
+		o, err := OverlappingTemplateTestSequence(ctx, in.bits, in.cfg.OverlappingTemplateLength)
+		return o, nil, err
+	}},
+	{"universal_statistical", func(ctx context.Context, in *suiteInput) (Outcome, []SubResult, error) {
+		o, err := UniversalStatisticalTestSequence(ctx, in.bits)
+		return o, nil, err
+	}},
+	{"approximate_entropy", func(ctx context.Context, in *suiteInput) (Outcome, []SubResult, error) {
+		o, err := ApproximateEntropyTestSequence(ctx, in.bits, in.cfg.ApproximateEntropyBlockLength)
+		return o, nil, err
+	}},
+	// One sub-result per state
+	{"random_excursions", func(ctx context.Context, in *suiteInput) (Outcome, []SubResult, error) {
+		r, err := RandomExcursionsTestSequence(ctx, in.bits)
+		if err != nil {
+			return errOutcome(err), nil, ctx.Err()
+		}
+		return pValueOutcome(r.CombinedPValue()), excursionSubResults(r), nil
+	}},
+	// One sub-result per state
+	{"random_excursions_variant", func(ctx context.Context, in *suiteInput) (Outcome, []SubResult, error) {
+		r, err := RandomExcursionsVariantTestSequence(ctx, in.bits)
+		if err != nil {
+			return errOutcome(err), nil, ctx.Err()
+		}
+		return pValueOutcome(r.CombinedPValue()), excursionVariantSubResults(r), nil
+	}},
+	// One sub-result per p-value
+	{"serial", func(ctx context.Context, in *suiteInput) (Outcome, []SubResult, error) {
+		r, err := SerialTestSequence(ctx, in.bits, in.cfg.SerialBlockLength)
+		if err != nil {
+			return errOutcome(err), nil, ctx.Err()
+		}
+		return pValueOutcome(r.CombinedPValue()), serialSubResults(r), nil
+	}},
+	{"linear_complexity", func(ctx context.Context, in *suiteInput) (Outcome, []SubResult, error) {
+		r, err := LinearComplexityTestSequence(ctx, in.bits, in.cfg.LinearComplexityBlockLength)
+		if err != nil {
+			return errOutcome(err), nil, ctx.Err()
+		}
+		return statisticOutcome(r.PValue, r.ChiSquare), linearComplexitySubResults(r), nil
+	}},
+}
 
-	// 9. Overlapping Template (m = 9)
-	p, pass = OverlappingTemplateTest(bitstream, 9)
-	appendResult("overlapping_template", p, pass, "")
+func newTestResult(name string, o Outcome, subResults []SubResult) TestResult {
+	return TestResult{
+		Name:       name,
+		PValue:     o.PValue,
+		Passed:     o.Passed(),
+		Status:     o.Status,
+		Warning:    o.Reason,
+		SubResults: subResults,
+	}
+}
 
-	// 10. Universal Statistical
-	p, pass = UniversalStatisticalTest(bitstream)
-	warn = ""
-	if p == 0 && !pass {
-		warn = "insufficient bits or invalid parameters"
+// cusumSubResults names the directions like the STS stats.txt headings.
+func cusumSubResults(r CumulativeSumsResult) []SubResult {
+	sub := func(name string, d CusumDirection) SubResult {
+		return SubResult{Name: name, PValue: d.PValue, Passed: d.Passed, Statistic: float64(d.Z)}
 	}
-	appendResult("universal_statistical", p, pass, warn)
+	return []SubResult{sub("forward", r.Forward), sub("reverse", r.Reverse)}
+}
 
-	// 11. Approximate Entropy (m = 10)
-	p, pass = ApproximateEntropyTest(bitstream, 10)
-	appendResult("approximate_entropy", p, pass, "")
+// serialSubResults names the p-values like the STS stats.txt, with the differences
+// of psi^2 as their statistics.
+func serialSubResults(r SerialResult) []SubResult {
+	psi2 := r.Psi2[:]
+	return []SubResult{
+		{Name: "p_value1", PValue: r.PValue1, Passed: r.PValue1 >= Alpha, Statistic: r.Del1, Values: psi2},
+		{Name: "p_value2", PValue: r.PValue2, Passed: r.PValue2 >= Alpha, Statistic: r.Del2, Values: psi2},
+	}
+}
 
-	// 12. Random Excursions
-	p, pass = RandomExcursionsTest(bitstream)
-	warn = ""
-	if p == 0 && !pass {
-		warn = "insufficient cycles (J < 500) or fail"
+// linearComplexitySubResults reports the nu histogram of T_i and the linear complexity
+// of every block alongside the single p-value.
+func linearComplexitySubResults(r LinearComplexityResult) []SubResult {
+	values := make([]float64, len(r.Complexities))
+	for i, L := range r.Complexities {
+		values[i] = float64(L)
 	}
-	appendResult("random_excursions", p, pass, warn)
+	return []SubResult{{
+		Name:      "blocks",
+		PValue:    r.PValue,
+		Passed:    r.Passed,
+		Statistic: r.ChiSquare,
+		Counts:    r.Nu[:],
+		Values:    values,
+	}}
+}
 
-	// 13. Random Excursions Variant
-	p, pass = RandomExcursionsVariantTest(bitstream)
-	warn = ""
-	if p == 0 && !pass {
-		warn = "insufficient cycles (J < 500) or fail"
+func templateSubResults(r NonOverlappingTemplateResult) []SubResult {
+	subs := make([]SubResult, len(r.Templates))
+	for i, t := range r.Templates {
+		subs[i] = SubResult{
+			Name:      t.Template,
+			PValue:    t.PValue,
+			Passed:    t.Passed,
+			Statistic: t.ChiSquare,
+			Counts:    t.Counts,
+		}
 	}
-	appendResult("random_excursions_variant", p, pass, warn)
+	return subs
+}
 
-	// 14. Serial (m = 16)
-	p, pass = SerialTest(bitstream, 16)
-	appendResult("serial", p, pass, "")
+func excursionSubResults(r RandomExcursionsResult) []SubResult {
+	subs := make([]SubResult, len(r.States))
+	for i, st := range r.States {
+		subs[i] = SubResult{
+			Name:      stateName(st.State),
+			State:     st.State,
+			PValue:    st.PValue,
+			Passed:    st.Passed,
+			Statistic: st.ChiSquare,
+			Counts:    st.Nu[:],
+		}
+	}
+	return subs
+}
 
-	// 15. Linear Complexity (M = 500)
-	p, pass = LinearComplexityTest(bitstream, 500)
-	appendResult("linear_complexity", p, pass, "")
+func excursionVariantSubResults(r RandomExcursionsVariantResult) []SubResult {
+	subs := make([]SubResult, len(r.States))
+	for i, st := range r.States {
+		subs[i] = SubResult{
+			Name:      stateName(st.State),
+			State:     st.State,
+			PValue:    st.PValue,
+			Passed:    st.Passed,
+			Statistic: st.Statistic,
+			Counts:    []int{st.Visits},
+		}
+	}
+	return subs
+}
 
-	return results, nil
+// stateName labels a random walk state the way the NIST STS reports do, e.g. "x=-4".
+func stateName(x int) string {
+	return fmt.Sprintf("x=%d", x)
 }
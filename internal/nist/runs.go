@@ -1,33 +1,44 @@
 package nist
 
 import (
+	"context"
 	"math"
-	"math/bits"
 )
 
 // RunsTest implements the NIST Runs test.
 // It returns the p-value and whether it passes at Alpha.
 func RunsTest(bitstream []byte) (float64, bool) {
-	n := len(bitstream) * 8
+	o, _ := RunsTestSequence(context.Background(), BitSequenceFromBytes(bitstream))
+	return o.PValue, o.Passed()
+}
+
+// RunsTestSequence runs the Runs test on a BitSequence of any length.
+// The outcome's Statistic is V_n(obs); its Values hold the proportion of ones pi.
+func RunsTestSequence(ctx context.Context, bits BitSequence) (Outcome, error) {
+	n := bits.n
 	if n == 0 {
-		return 0, false
+		return notApplicable("empty bitstream"), nil
 	}
 
-	ones := 0
-	for _, b := range bitstream {
-		ones += bits.OnesCount8(b)
+	if err := ctx.Err(); err != nil {
+		return Outcome{}, err
 	}
+	ones := bits.OnesCount()
 
 	pi := float64(ones) / float64(n)
 	if math.Abs(pi-0.5) > 2.0/math.Sqrt(float64(n)) {
 		// Precondition for the runs test is not met.
-		return 0, false
+		return notApplicable("pi estimator criteria not met: pi=%.6f", pi), nil
 	}
 
 	runs := 1
-	prev := bitAt(bitstream, 0)
+	done := ctx.Done()
+	prev := bits.Bit(0)
 	for i := 1; i < n; i++ {
-		b := bitAt(bitstream, i)
+		if i%ctxCheckInterval == 0 && isDone(done) {
+			return Outcome{}, ctx.Err()
+		}
+		b := bits.Bit(i)
 		if b != prev {
 			runs++
 			prev = b
@@ -38,5 +49,7 @@ func RunsTest(bitstream []byte) (float64, bool) {
 		(2.0 * math.Sqrt(2*float64(n)) * pi * (1 - pi))
 	pValue := math.Erfc(erfcArg)
 
-	return pValue, pValue >= Alpha
+	o := statisticOutcome(pValue, float64(runs))
+	o.Values = []float64{pi}
+	return o, nil
 }
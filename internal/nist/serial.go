@@ -1,31 +1,76 @@
 package nist
 
 import (
+	"context"
+	"fmt"
 	"math"
 
 	"gonum.org/v1/gonum/mathext"
 )
 
+// SerialResult is the detailed outcome of the Serial test, which the NIST STS reports as
+// two p-values.
+type SerialResult struct {
+	BlockLength int // m
+	// Psi2 holds psi^2_m, psi^2_{m-1} and psi^2_{m-2}.
+	Psi2 [3]float64
+	// Del1 and Del2 are the first and second differences of psi^2_m.
+	Del1    float64
+	Del2    float64
+	PValue1 float64
+	PValue2 float64
+}
+
+// MinPValue returns the smaller of the two p-values.
+func (r SerialResult) MinPValue() float64 {
+	return math.Min(r.PValue1, r.PValue2)
+}
+
+// CombinedPValue returns the Sidak-corrected minimum 1-(1-p_min)^2 over both p-values, the p-value that
+// decides whether the test as a whole passes.
+func (r SerialResult) CombinedPValue() float64 {
+	return sidakPValue(r.MinPValue(), 2)
+}
+
 // SerialTest implements the NIST Serial test with a fixed block length m.
-// It returns the minimum p-value across the two computed statistics and whether it passes at Alpha.
+// It returns the Sidak-corrected minimum of the two p-values and whether it passes at Alpha.
 func SerialTest(bitstream []byte, m int) (float64, bool) {
-	bits := expandBits(bitstream)
-	n := len(bits)
-	if n == 0 || m < 2 {
+	res, err := SerialTestSequence(context.Background(), BitSequenceFromBytes(bitstream), m)
+	if err != nil {
 		return 0, false
 	}
 
-	psim0 := psi2(bits, m)
-	psim1 := psi2(bits, m-1)
-	psim2 := psi2(bits, m-2)
+	p := res.CombinedPValue()
+	return p, p >= Alpha
+}
 
-	del1 := psim0 - psim1
-	del2 := psim0 - 2.0*psim1 + psim2
+// SerialTestSequence runs the Serial test on a BitSequence of any length with block
+// length m and reports psi^2 for m, m-1 and m-2 bits, its differences and both p-values.
+// It returns an error wrapping ErrNotApplicable for an empty bitstream.
+func SerialTestSequence(ctx context.Context, bits BitSequence, m int) (SerialResult, error) {
+	n := bits.n
+	if m < 2 {
+		return SerialResult{}, fmt.Errorf("invalid block length m=%d", m)
+	}
+	if n == 0 {
+		return SerialResult{}, fmt.Errorf("%w: empty bitstream", ErrNotApplicable)
+	}
 
-	p1 := mathext.GammaIncRegComp(math.Pow(2, float64(m-1))/2.0, del1/2.0)
-	p2 := mathext.GammaIncRegComp(math.Pow(2, float64(m-2))/2.0, del2/2.0)
+	// A single pass counts the m-bit patterns; those of m-1 and m-2 bits are their prefixes.
+	counts, err := patternCounts(ctx, bits, m)
+	if err != nil {
+		return SerialResult{}, err
+	}
+	res := SerialResult{BlockLength: m}
+	res.Psi2[0] = psi2(counts, n)
+	counts = marginalCounts(counts)
+	res.Psi2[1] = psi2(counts, n)
+	res.Psi2[2] = psi2(marginalCounts(counts), n)
 
-	pValue := math.Min(p1, p2)
+	res.Del1 = res.Psi2[0] - res.Psi2[1]
+	res.Del2 = res.Psi2[0] - 2.0*res.Psi2[1] + res.Psi2[2]
 
-	return pValue, pValue >= Alpha
+	res.PValue1 = mathext.GammaIncRegComp(math.Pow(2, float64(m-1))/2.0, res.Del1/2.0)
+	res.PValue2 = mathext.GammaIncRegComp(math.Pow(2, float64(m-2))/2.0, res.Del2/2.0)
+	return res, nil
 }
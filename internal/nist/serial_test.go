@@ -1,7 +1,12 @@
 package nist
 
 import (
+	"context"
+	"errors"
+	"math"
 	"testing"
+
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/testrand"
 )
 
 func TestSerial(t *testing.T) {
@@ -14,12 +19,7 @@ func TestSerial(t *testing.T) {
 	})
 
 	t.Run("valid_input", func(t *testing.T) {
-		data := make([]byte, 100000)
-		state := uint64(777)
-		for i := range data {
-			state = state*6364136223846793005 + 1442695040888963407
-			data[i] = byte(state >> 56)
-		}
+		data := testrand.Bytes(100000, 777)
 		p, pass := SerialTest(data, 3)
 		if p < 0 || p > 1 {
 			t.Fatalf("p-value out of range: %.6f", p)
@@ -29,3 +29,30 @@ func TestSerial(t *testing.T) {
 		}
 	})
 }
+
+func TestSerialTestSequence(t *testing.T) {
+	data := testrand.Bytes(20000, 13)
+
+	res, err := SerialTestSequence(context.Background(), BitSequenceFromBytes(data), 8)
+	if err != nil {
+		t.Fatalf("SerialTestSequence failed: %v", err)
+	}
+	if res.BlockLength != 8 || res.Del1 != res.Psi2[0]-res.Psi2[1] || math.Abs(res.Del2-(res.Psi2[0]-2*res.Psi2[1]+res.Psi2[2])) > 1e-9 {
+		t.Errorf("inconsistent differences in %+v", res)
+	}
+	if p, _ := SerialTest(data, 8); p != res.CombinedPValue() || res.MinPValue() != math.Min(res.PValue1, res.PValue2) {
+		t.Errorf("p-value %.6f is not the combined minimum of %+v", p, res)
+	}
+
+	if _, err := SerialTestSequence(context.Background(), BitSequenceFromBytes(data), 1); err == nil || errors.Is(err, ErrNotApplicable) {
+		t.Errorf("expected an invalid parameter error for m=1, got %v", err)
+	}
+	if _, err := SerialTestSequence(context.Background(), BitSequenceFromBytes(nil), 8); !errors.Is(err, ErrNotApplicable) {
+		t.Errorf("expected ErrNotApplicable for an empty bitstream, got %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := SerialTestSequence(ctx, BitSequenceFromBytes(data), 8); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package nist
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Status is the outcome category of a single test.
+type Status int
+
+const (
+	// StatusPassed means the test produced a p-value >= Alpha.
+	StatusPassed Status = iota + 1
+	// StatusFailed means the test produced a p-value < Alpha.
+	StatusFailed
+	// StatusNotApplicable means the sequence does not meet the test's preconditions
+	// (e.g. too few cycles for Random Excursions); no p-value was computed.
+	StatusNotApplicable
+	// StatusError means the test could not run, e.g. because of an invalid parameter
+	// or a non-finite statistic.
+	StatusError
+)
+
+// String returns the lower-case name of the status, e.g. "not_applicable".
+func (s Status) String() string {
+	switch s {
+	case StatusPassed:
+		return "passed"
+	case StatusFailed:
+		return "failed"
+	case StatusNotApplicable:
+		return "not_applicable"
+	case StatusError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// Completed reports whether the test produced a p-value, i.e. it passed or failed.
+func (s Status) Completed() bool {
+	return s == StatusPassed || s == StatusFailed
+}
+
+// Outcome is the structured result of a single-valued test.
+type Outcome struct {
+	PValue float64
+	Status Status
+	// Reason explains a StatusNotApplicable or StatusError outcome.
+	Reason string
+	// Statistic is the test statistic the p-value is computed from, e.g. s_obs of the
+	// Frequency test or chi^2(obs) of Block Frequency; zero if the test reports none.
+	Statistic float64
+	// Counts and Values hold the intermediate statistics that SP 800-22 section 2 lists
+	// for the test, e.g. the nu_i of the Longest Run test; each test documents them.
+	Counts []int
+	Values []float64
+}
+
+// Passed reports whether the outcome has StatusPassed.
+func (o Outcome) Passed() bool {
+	return o.Status == StatusPassed
+}
+
+// pValueOutcome classifies a computed p-value at Alpha.
+func pValueOutcome(p float64) Outcome {
+	switch {
+	case math.IsNaN(p) || math.IsInf(p, 0):
+		return Outcome{Status: StatusError, Reason: fmt.Sprintf("non-finite p-value %v", p)}
+	case p >= Alpha:
+		return Outcome{PValue: p, Status: StatusPassed}
+	default:
+		return Outcome{PValue: p, Status: StatusFailed}
+	}
+}
+
+// statisticOutcome is like pValueOutcome and records the test statistic.
+func statisticOutcome(p, statistic float64) Outcome {
+	o := pValueOutcome(p)
+	o.Statistic = statistic
+	return o
+}
+
+func notApplicable(format string, args ...any) Outcome {
+	return Outcome{Status: StatusNotApplicable, Reason: fmt.Sprintf(format, args...)}
+}
+
+func errorOutcome(format string, args ...any) Outcome {
+	return Outcome{Status: StatusError, Reason: fmt.Sprintf(format, args...)}
+}
+
+// errOutcome maps an error returned by a detailed test function to an outcome.
+func errOutcome(err error) Outcome {
+	if errors.Is(err, ErrNotApplicable) {
+		return Outcome{Status: StatusNotApplicable, Reason: err.Error()}
+	}
+	return Outcome{Status: StatusError, Reason: err.Error()}
+}
@@ -0,0 +1,90 @@
+package nist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestStatus(t *testing.T) {
+	t.Run("string_and_completed", func(t *testing.T) {
+		cases := []struct {
+			status    Status
+			name      string
+			completed bool
+		}{
+			{StatusPassed, "passed", true},
+			{StatusFailed, "failed", true},
+			{StatusNotApplicable, "not_applicable", false},
+			{StatusError, "error", false},
+			{Status(0), "unknown", false},
+		}
+		for _, tc := range cases {
+			if got := tc.status.String(); got != tc.name {
+				t.Errorf("String() = %q, want %q", got, tc.name)
+			}
+			if got := tc.status.Completed(); got != tc.completed {
+				t.Errorf("%s: Completed() = %v, want %v", tc.name, got, tc.completed)
+			}
+		}
+	})
+
+	t.Run("p_value_outcome", func(t *testing.T) {
+		if o := pValueOutcome(0.5); o.Status != StatusPassed || !o.Passed() || o.PValue != 0.5 {
+			t.Errorf("unexpected outcome for p=0.5: %+v", o)
+		}
+		// A legitimate p-value of 0 is a failure, not a skipped test
+		if o := pValueOutcome(0); o.Status != StatusFailed || o.Passed() {
+			t.Errorf("unexpected outcome for p=0: %+v", o)
+		}
+		if o := pValueOutcome(math.NaN()); o.Status != StatusError || o.Reason == "" {
+			t.Errorf("unexpected outcome for NaN: %+v", o)
+		}
+	})
+
+	t.Run("err_outcome", func(t *testing.T) {
+		if o := errOutcome(fmt.Errorf("%w: too short", ErrNotApplicable)); o.Status != StatusNotApplicable || o.Reason == "" {
+			t.Errorf("expected not applicable, got %+v", o)
+		}
+		if o := errOutcome(errors.New("boom")); o.Status != StatusError || o.Reason != "boom" {
+			t.Errorf("expected error, got %+v", o)
+		}
+	})
+
+	t.Run("test_outcomes", func(t *testing.T) {
+		ctx := context.Background()
+		outcome := func(o Outcome, _ error) Outcome { return o }
+		detailedOutcome := func(_ any, err error) Outcome { return errOutcome(err) }
+		zeros := make([]byte, 1000)
+		cases := []struct {
+			name    string
+			outcome Outcome
+			want    Status
+		}{
+			{"frequency empty", outcome(FrequencyTestSequence(ctx, BitSequenceFromBytes(nil))), StatusNotApplicable},
+			{"frequency zeros", outcome(FrequencyTestSequence(ctx, BitSequenceFromBytes(zeros))), StatusFailed},
+			{"block frequency invalid M", outcome(BlockFrequencyTestSequence(ctx, BitSequenceFromBytes(zeros), 0)), StatusError},
+			{"block frequency short", outcome(BlockFrequencyTestSequence(ctx, BitSequenceFromBytes(zeros), 10000)), StatusNotApplicable},
+			{"runs pi estimator", outcome(RunsTestSequence(ctx, BitSequenceFromBytes(zeros))), StatusNotApplicable},
+			{"longest run short", outcome(LongestRunOfOnesTestSequence(ctx, BitSequenceFromBytes(make([]byte, 8)))), StatusNotApplicable},
+			{"matrix rank short", outcome(BinaryMatrixRankTestSequence(ctx, BitSequenceFromBytes(make([]byte, 64)), DefaultMatrixRows, DefaultMatrixColumns)), StatusNotApplicable},
+			{"overlapping invalid m", outcome(OverlappingTemplateTestSequence(ctx, BitSequenceFromBytes(zeros), 0)), StatusError},
+			{"overlapping short", outcome(OverlappingTemplateTestSequence(ctx, BitSequenceFromBytes(make([]byte, 100)), 9)), StatusNotApplicable},
+			{"universal short", outcome(UniversalStatisticalTestSequence(ctx, BitSequenceFromBytes(zeros))), StatusNotApplicable},
+			{"apen invalid m", outcome(ApproximateEntropyTestSequence(ctx, BitSequenceFromBytes(zeros), 0)), StatusError},
+			{"serial invalid m", detailedOutcome(SerialTestSequence(ctx, BitSequenceFromBytes(zeros), 1)), StatusError},
+			{"linear complexity invalid M", detailedOutcome(LinearComplexityTestSequence(ctx, BitSequenceFromBytes(zeros), 0)), StatusError},
+			{"linear complexity short", detailedOutcome(LinearComplexityTestSequence(ctx, BitSequenceFromBytes(make([]byte, 10)), 500)), StatusNotApplicable},
+		}
+		for _, tc := range cases {
+			if tc.outcome.Status != tc.want {
+				t.Errorf("%s: status %v, want %v (%+v)", tc.name, tc.outcome.Status, tc.want, tc.outcome)
+			}
+			if !tc.outcome.Status.Completed() && tc.outcome.Reason == "" {
+				t.Errorf("%s: missing reason", tc.name)
+			}
+		}
+	})
+}
@@ -0,0 +1,196 @@
+package nist
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// ErrInvalidParameter is returned (wrapped) when a SuiteConfig value lies outside
+// the range recommended by NIST SP 800-22 for the given sequence length.
+var ErrInvalidParameter = errors.New("invalid test parameter")
+
+// Default parameters, matching the defaults of the NIST STS reference implementation.
+const (
+	DefaultBlockFrequencyBlockLength     = 128
+	DefaultNonOverlappingTemplateLength  = 9
+	DefaultNonOverlappingTemplateBlocks  = 8
+	DefaultOverlappingTemplateLength     = 9
+	DefaultApproximateEntropyBlockLength = 10
+	DefaultSerialBlockLength             = 16
+	DefaultLinearComplexityBlockLength   = 500
+)
+
+// SuiteConfig holds the tunable parameters of the test battery.
+// A zero value for any field selects the corresponding NIST STS default.
+type SuiteConfig struct {
+	// BlockFrequencyBlockLength is M for the Block Frequency test.
+	BlockFrequencyBlockLength int
+	// NonOverlappingTemplateLength is m for the Non-overlapping Template test.
+	NonOverlappingTemplateLength int
+	// NonOverlappingTemplateBlocks is N, the number of blocks for the Non-overlapping Template test.
+	NonOverlappingTemplateBlocks int
+	// OverlappingTemplateLength is m for the Overlapping Template test.
+	OverlappingTemplateLength int
+	// ApproximateEntropyBlockLength is m for the Approximate Entropy test.
+	ApproximateEntropyBlockLength int
+	// SerialBlockLength is m for the Serial test.
+	SerialBlockLength int
+	// LinearComplexityBlockLength is M for the Linear Complexity test.
+	LinearComplexityBlockLength int
+	// BinaryMatrixRows is M, the number of rows of each Binary Matrix Rank matrix.
+	BinaryMatrixRows int
+	// BinaryMatrixColumns is Q, the number of columns of each Binary Matrix Rank matrix.
+	BinaryMatrixColumns int
+
+	// BitOrder is the order in which RunAllTestsContext and RunMultiSequenceContext read
+	// the bits of each byte of the bitstream; the zero value is MSBFirst. A BitSequence
+	// carries its order already, so RunAllTestsSequence ignores this field.
+	BitOrder BitOrder
+
+	// Workers is the maximum number of tests run concurrently; zero selects GOMAXPROCS.
+	// It does not affect the results.
+	Workers int
+	// MaxBits caps the length of the sequence accepted by RunAllTestsContext; zero selects MaxBits.
+	MaxBits int
+}
+
+// DefaultSuiteConfig returns the NIST STS default parameters.
+func DefaultSuiteConfig() SuiteConfig {
+	return SuiteConfig{
+		BlockFrequencyBlockLength:     DefaultBlockFrequencyBlockLength,
+		NonOverlappingTemplateLength:  DefaultNonOverlappingTemplateLength,
+		NonOverlappingTemplateBlocks:  DefaultNonOverlappingTemplateBlocks,
+		OverlappingTemplateLength:     DefaultOverlappingTemplateLength,
+		ApproximateEntropyBlockLength: DefaultApproximateEntropyBlockLength,
+		SerialBlockLength:             DefaultSerialBlockLength,
+		LinearComplexityBlockLength:   DefaultLinearComplexityBlockLength,
+		BinaryMatrixRows:              DefaultMatrixRows,
+		BinaryMatrixColumns:           DefaultMatrixColumns,
+	}
+}
+
+// WithDefaults returns a copy of c with every zero field replaced by its default.
+func (c SuiteConfig) WithDefaults() SuiteConfig {
+	d := DefaultSuiteConfig()
+	if c.BlockFrequencyBlockLength == 0 {
+		c.BlockFrequencyBlockLength = d.BlockFrequencyBlockLength
+	}
+	if c.NonOverlappingTemplateLength == 0 {
+		c.NonOverlappingTemplateLength = d.NonOverlappingTemplateLength
+	}
+	if c.NonOverlappingTemplateBlocks == 0 {
+		c.NonOverlappingTemplateBlocks = d.NonOverlappingTemplateBlocks
+	}
+	if c.OverlappingTemplateLength == 0 {
+		c.OverlappingTemplateLength = d.OverlappingTemplateLength
+	}
+	if c.ApproximateEntropyBlockLength == 0 {
+		c.ApproximateEntropyBlockLength = d.ApproximateEntropyBlockLength
+	}
+	if c.SerialBlockLength == 0 {
+		c.SerialBlockLength = d.SerialBlockLength
+	}
+	if c.LinearComplexityBlockLength == 0 {
+		c.LinearComplexityBlockLength = d.LinearComplexityBlockLength
+	}
+	if c.BinaryMatrixRows == 0 {
+		c.BinaryMatrixRows = d.BinaryMatrixRows
+	}
+	if c.BinaryMatrixColumns == 0 {
+		c.BinaryMatrixColumns = d.BinaryMatrixColumns
+	}
+	return c
+}
+
+// Validate checks every explicitly set (non-zero) parameter against the ranges
+// recommended in NIST SP 800-22 for a sequence of n bits. Zero fields select the
+// STS defaults, which are used as-is even where the STS itself departs from the
+// recommendation (e.g. M=128 for Block Frequency at n >= 12,800).
+func (c SuiteConfig) Validate(n int) error {
+	log2n := 0
+	if n > 0 {
+		log2n = bits.Len(uint(n)) - 1
+	}
+
+	if M := c.BlockFrequencyBlockLength; M != 0 {
+		if M < 20 || M > n || 100*M <= n {
+			return invalidParam("block frequency block length M=%d: need M >= 20 and M > 0.01n (n=%d)", M, n)
+		}
+	}
+
+	if m := c.NonOverlappingTemplateLength; m != 0 {
+		if m < MinTemplateLength || m > MaxTemplateLength {
+			return invalidParam("non-overlapping template length m=%d: need %d <= m <= %d", m, MinTemplateLength, MaxTemplateLength)
+		}
+	}
+
+	if N := c.NonOverlappingTemplateBlocks; N != 0 {
+		if N < 1 || N > 100 {
+			return invalidParam("non-overlapping template blocks N=%d: need 1 <= N <= 100", N)
+		}
+	}
+
+	if m := c.OverlappingTemplateLength; m != 0 && m != 9 && m != 10 {
+		return invalidParam("overlapping template length m=%d: must be 9 or 10", m)
+	}
+
+	if m := c.ApproximateEntropyBlockLength; m != 0 {
+		if m < 1 || m >= log2n-5 {
+			return invalidParam("approximate entropy block length m=%d: need 1 <= m < floor(log2 n)-5 = %d", m, log2n-5)
+		}
+	}
+
+	if m := c.SerialBlockLength; m != 0 {
+		if m < 2 || m >= log2n-2 {
+			return invalidParam("serial block length m=%d: need 2 <= m < floor(log2 n)-2 = %d", m, log2n-2)
+		}
+	}
+
+	if M := c.LinearComplexityBlockLength; M != 0 {
+		if M < MinLinearComplexityBlockLength || M > MaxLinearComplexityBlockLength {
+			return invalidParam("linear complexity block length M=%d: need %d <= M <= %d",
+				M, MinLinearComplexityBlockLength, MaxLinearComplexityBlockLength)
+		}
+		if n/M < MinLinearComplexityBlocks {
+			return invalidParam("linear complexity block length M=%d: need at least %d blocks (n=%d)", M, MinLinearComplexityBlocks, n)
+		}
+	}
+
+	if c.BinaryMatrixRows != 0 || c.BinaryMatrixColumns != 0 {
+		d := c.WithDefaults()
+		M, Q := d.BinaryMatrixRows, d.BinaryMatrixColumns
+		if M < 2 || Q < 2 {
+			return invalidParam("binary matrix size %dx%d: need M >= 2 and Q >= 2", M, Q)
+		}
+		if n/M/Q < MinBinaryMatrices {
+			return invalidParam("binary matrix size %dx%d: need at least %d matrices (n=%d)", M, Q, MinBinaryMatrices, n)
+		}
+	}
+
+	if c.BitOrder != MSBFirst && c.BitOrder != LSBFirst {
+		return invalidParam("bit order %v: must be MSB-first or LSB-first", c.BitOrder)
+	}
+
+	if c.Workers < 0 {
+		return invalidParam("workers=%d: must not be negative", c.Workers)
+	}
+
+	if c.MaxBits < 0 {
+		return invalidParam("max bits=%d: must not be negative", c.MaxBits)
+	}
+
+	return nil
+}
+
+// maxBits returns the effective sequence length cap.
+func (c SuiteConfig) maxBits() int {
+	if c.MaxBits > 0 {
+		return c.MaxBits
+	}
+	return MaxBits
+}
+
+func invalidParam(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidParameter, fmt.Sprintf(format, args...))
+}
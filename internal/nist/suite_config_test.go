@@ -0,0 +1,116 @@
+package nist
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+func TestSuiteConfigWithDefaults(t *testing.T) {
+	if got := (SuiteConfig{}).WithDefaults(); got != DefaultSuiteConfig() {
+		t.Fatalf("zero config should resolve to defaults, got %+v", got)
+	}
+
+	cfg := SuiteConfig{SerialBlockLength: 12}.WithDefaults()
+	if cfg.SerialBlockLength != 12 {
+		t.Fatalf("explicit value overwritten: %+v", cfg)
+	}
+	if cfg.BlockFrequencyBlockLength != DefaultBlockFrequencyBlockLength {
+		t.Fatalf("default not applied: %+v", cfg)
+	}
+}
+
+func TestSuiteConfigValidate(t *testing.T) {
+	const n = 1000000
+
+	valid := []SuiteConfig{
+		{},
+		{BlockFrequencyBlockLength: 10001},
+		{NonOverlappingTemplateLength: 2, NonOverlappingTemplateBlocks: 100},
+		{NonOverlappingTemplateLength: 21},
+		{OverlappingTemplateLength: 10},
+		{ApproximateEntropyBlockLength: 13},
+		{SerialBlockLength: 16},
+		{LinearComplexityBlockLength: 5000},
+		{BinaryMatrixRows: 2, BinaryMatrixColumns: 2},
+		{BinaryMatrixRows: 64},
+		{BinaryMatrixRows: 128, BinaryMatrixColumns: 200},
+		{BitOrder: LSBFirst},
+	}
+	for _, cfg := range valid {
+		if err := cfg.Validate(n); err != nil {
+			t.Errorf("unexpected error for %+v: %v", cfg, err)
+		}
+	}
+
+	invalid := []SuiteConfig{
+		{BlockFrequencyBlockLength: 19},
+		{BlockFrequencyBlockLength: 10000},
+		{BlockFrequencyBlockLength: -1},
+		{NonOverlappingTemplateLength: 22},
+		{NonOverlappingTemplateBlocks: 101},
+		{OverlappingTemplateLength: 8},
+		{ApproximateEntropyBlockLength: 14},
+		{SerialBlockLength: 1},
+		{SerialBlockLength: 17},
+		{LinearComplexityBlockLength: 499},
+		{LinearComplexityBlockLength: 5001},
+		{BinaryMatrixRows: 1},
+		{BinaryMatrixColumns: -32},
+		{BinaryMatrixRows: 128, BinaryMatrixColumns: 206},
+		{Workers: -1},
+		{MaxBits: -1},
+		{BitOrder: 2},
+	}
+	for _, cfg := range invalid {
+		err := cfg.Validate(n)
+		if !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("expected ErrInvalidParameter for %+v, got %v", cfg, err)
+		}
+	}
+
+	// 199 blocks of M=5000 bits
+	if err := (SuiteConfig{LinearComplexityBlockLength: 5000}).Validate(n - 5000); !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("expected ErrInvalidParameter for 199 linear complexity blocks, got %v", err)
+	}
+
+	// 37 matrices of 32x32 bits
+	if err := (SuiteConfig{BinaryMatrixRows: 32}).Validate(38*32*32 - 1); !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("expected ErrInvalidParameter for 37 binary matrices, got %v", err)
+	}
+}
+
+func TestRunAllTestsWithConfig(t *testing.T) {
+	data := make([]byte, MinBits/8)
+	for i := range data {
+		data[i] = byte(i * 131)
+	}
+
+	if _, err := RunAllTestsWithConfig(data, SuiteConfig{SerialBlockLength: 20}); !errors.Is(err, ErrInvalidParameter) {
+		t.Fatalf("expected ErrInvalidParameter, got %v", err)
+	}
+
+	results, err := RunAllTestsWithConfig(data, SuiteConfig{BlockFrequencyBlockLength: 4000, SerialBlockLength: 10})
+	if err != nil {
+		t.Fatalf("RunAllTestsWithConfig failed: %v", err)
+	}
+	if len(results) != 15 {
+		t.Fatalf("expected 15 results, got %d", len(results))
+	}
+
+	random := make([]byte, MinBits/8)
+	rand.Read(random)
+	results, err = RunAllTestsWithConfig(random, SuiteConfig{BinaryMatrixRows: 16, BinaryMatrixColumns: 24})
+	if err != nil {
+		t.Fatalf("RunAllTestsWithConfig failed: %v", err)
+	}
+	want, _ := BinaryMatrixRankTestSequence(context.Background(), BitSequenceFromBytes(random), 16, 24)
+	if r := results[5]; r.Name != "binary_matrix_rank" || r.PValue != want.PValue {
+		t.Errorf("matrix size not applied: got %+v, want p-value %v", r, want.PValue)
+	}
+
+	if _, err := RunAllTestsWithConfig(data, SuiteConfig{MaxBits: MinBits - 8}); err == nil {
+		t.Fatal("expected an error for a sequence longer than MaxBits")
+	}
+}
@@ -1,7 +1,16 @@
 package nist
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
 	"testing"
+	"time"
+
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/testrand"
 )
 
 // TestRunAllTests tests the RunAllTests function
@@ -35,6 +44,12 @@ func TestRunAllTests(t *testing.T) {
 			if r.Name == "" {
 				t.Errorf("result missing name: %+v", r)
 			}
+			if r.Status == 0 || r.Passed != (r.Status == StatusPassed) {
+				t.Errorf("%s: inconsistent status %v (passed=%v)", r.Name, r.Status, r.Passed)
+			}
+			if r.Duration <= 0 {
+				t.Errorf("%s: missing duration", r.Name)
+			}
 		}
 	})
 
@@ -57,4 +72,180 @@ func TestRunAllTests(t *testing.T) {
 			names[r.Name] = true
 		}
 	})
+	t.Run("insufficient cycles are not applicable", func(t *testing.T) {
+		// All zeros: the random walk never returns to zero, so J = 1
+		data := make([]byte, MinBits/8)
+
+		results, err := RunAllTests(data)
+		if err != nil {
+			t.Fatalf("RunAllTests failed: %v", err)
+		}
+
+		for _, r := range results {
+			if r.Name != "random_excursions" && r.Name != "random_excursions_variant" {
+				continue
+			}
+			if r.Status != StatusNotApplicable || r.Passed || r.Warning == "" || len(r.SubResults) != 0 {
+				t.Errorf("%s: expected not applicable with warning, got %+v", r.Name, r)
+			}
+		}
+	})
+
+	t.Run("random excursions report per-state sub-results", func(t *testing.T) {
+		data := testrand.Bytes(MinBits/8, 7) // J = 1378 cycles
+
+		results, err := RunAllTests(data)
+		if err != nil {
+			t.Fatalf("RunAllTests failed: %v", err)
+		}
+
+		want := map[string]int{"random_excursions": 8, "random_excursions_variant": 18}
+		for _, r := range results {
+			n, ok := want[r.Name]
+			if !ok {
+				continue
+			}
+			if !r.Status.Completed() {
+				t.Fatalf("%s: unexpected status %v: %s", r.Name, r.Status, r.Warning)
+			}
+			if len(r.SubResults) != n {
+				t.Errorf("%s: expected %d sub-results, got %d", r.Name, n, len(r.SubResults))
+			}
+			if r.SubResults[0].Name != fmt.Sprintf("x=%d", r.SubResults[0].State) {
+				t.Errorf("%s: unexpected sub-result name %q", r.Name, r.SubResults[0].Name)
+			}
+		}
+	})
+	t.Run("linear complexity reports its blocks", func(t *testing.T) {
+		data := testrand.Bytes(MinBits/8, 7)
+
+		results, err := RunAllTests(data)
+		if err != nil {
+			t.Fatalf("RunAllTests failed: %v", err)
+		}
+		want, err := LinearComplexityTestSequence(context.Background(), BitSequenceFromBytes(data), DefaultLinearComplexityBlockLength)
+		if err != nil {
+			t.Fatalf("LinearComplexityTestSequence failed: %v", err)
+		}
+
+		r := results[14]
+		if r.Name != "linear_complexity" || r.MultiValued() || len(r.SubResults) != 1 {
+			t.Fatalf("unexpected result %+v", r)
+		}
+		sub := r.SubResults[0]
+		if sub.Name != "blocks" || sub.PValue != r.PValue || sub.Statistic != want.ChiSquare || !slices.Equal(sub.Counts, want.Nu[:]) {
+			t.Errorf("unexpected sub-result %s: p=%v chi2=%v counts=%v", sub.Name, sub.PValue, sub.Statistic, sub.Counts)
+		}
+		if len(sub.Values) != want.NumBlocks || sub.Values[0] != float64(want.Complexities[0]) || sub.Values[want.NumBlocks-1] != float64(want.Complexities[want.NumBlocks-1]) {
+			t.Errorf("expected the linear complexity of %d blocks, got %d values", want.NumBlocks, len(sub.Values))
+		}
+	})
+	t.Run("results are independent of the worker count", func(t *testing.T) {
+		data := testrand.Bytes(MinBits/8, 7)
+
+		sequential, err := RunAllTestsWithConfig(data, SuiteConfig{Workers: 1})
+		if err != nil {
+			t.Fatalf("RunAllTestsWithConfig failed: %v", err)
+		}
+		parallel, err := RunAllTestsWithConfig(data, SuiteConfig{Workers: 15})
+		if err != nil {
+			t.Fatalf("RunAllTestsWithConfig failed: %v", err)
+		}
+
+		// Timings naturally differ between runs
+		for i := range sequential {
+			sequential[i].Duration, parallel[i].Duration = 0, 0
+		}
+		if !reflect.DeepEqual(sequential, parallel) {
+			t.Fatal("parallel results differ from sequential results")
+		}
+		for i, r := range parallel {
+			if r.Name != suiteTests[i].name {
+				t.Errorf("result %d: got %s, want %s", i, r.Name, suiteTests[i].name)
+			}
+		}
+	})
+}
+
+func TestCombinedPValue(t *testing.T) {
+	results := []TestResult{
+		{Name: "a", Status: StatusPassed, PValue: 0.5},
+		{Name: "b", Status: StatusFailed, PValue: 0.005},
+		{Name: "c", Status: StatusNotApplicable},
+		{Name: "d", Status: StatusError},
+	}
+	p, k := CombinedPValue(results)
+	if k != 2 || math.Abs(p-(1-0.995*0.995)) > 1e-12 {
+		t.Errorf("expected the corrected minimum of 2 results, got %v over %d", p, k)
+	}
+	if p, k := CombinedPValue(results[2:]); p != 1 || k != 0 {
+		t.Errorf("expected 1 without completed results, got %v over %d", p, k)
+	}
+}
+
+func TestContextCancellation(t *testing.T) {
+	data := testrand.Bytes(MinBits/8, 7)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	t.Run("suite returns the context error", func(t *testing.T) {
+		if _, err := RunAllTestsContext(canceled, data, SuiteConfig{}); !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+
+		expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancel()
+		if _, err := RunAllTestsContext(expired, data, SuiteConfig{}); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+		}
+
+		if _, err := RunMultiSequenceContext(canceled, data, len(data)*8, 1, SuiteConfig{}); !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled from multi-sequence run, got %v", err)
+		}
+	})
+
+	t.Run("every test aborts", func(t *testing.T) {
+		seq := BitSequenceFromBytes(data)
+		tests := map[string]func(ctx context.Context) error{
+			"frequency": func(ctx context.Context) error { _, err := FrequencyTestSequence(ctx, seq); return err },
+			"block_frequency": func(ctx context.Context) error {
+				_, err := BlockFrequencyTestSequence(ctx, seq, DefaultBlockFrequencyBlockLength)
+				return err
+			},
+			"cumulative_sums": func(ctx context.Context) error { _, err := CumulativeSumsTestSequence(ctx, seq); return err },
+			"runs":            func(ctx context.Context) error { _, err := RunsTestSequence(ctx, seq); return err },
+			"longest_run":     func(ctx context.Context) error { _, err := LongestRunOfOnesTestSequence(ctx, seq); return err },
+			"rank": func(ctx context.Context) error {
+				_, err := BinaryMatrixRankTestSequence(ctx, seq, DefaultMatrixRows, DefaultMatrixColumns)
+				return err
+			},
+			"dft": func(ctx context.Context) error { _, err := DiscreteFourierTransformTestSequence(ctx, seq); return err },
+			"non_overlapping_template": func(ctx context.Context) error {
+				_, err := NonOverlappingTemplateTestSequence(ctx, seq, 9, 8)
+				return err
+			},
+			"overlapping_template": func(ctx context.Context) error { _, err := OverlappingTemplateTestSequence(ctx, seq, 9); return err },
+			"universal":            func(ctx context.Context) error { _, err := UniversalStatisticalTestSequence(ctx, seq); return err },
+			"approximate_entropy":  func(ctx context.Context) error { _, err := ApproximateEntropyTestSequence(ctx, seq, 10); return err },
+			"random_excursions": func(ctx context.Context) error {
+				_, err := RandomExcursionsTestSequence(ctx, seq)
+				return err
+			},
+			"random_excursions_variant": func(ctx context.Context) error {
+				_, err := RandomExcursionsVariantTestSequence(ctx, seq)
+				return err
+			},
+			"serial":            func(ctx context.Context) error { _, err := SerialTestSequence(ctx, seq, 16); return err },
+			"linear_complexity": func(ctx context.Context) error { _, err := LinearComplexityTestSequence(ctx, seq, 500); return err },
+		}
+		for name, run := range tests {
+			if err := run(canceled); !errors.Is(err, context.Canceled) {
+				t.Errorf("%s: expected context.Canceled, got %v", name, err)
+			}
+			if err := run(context.Background()); err != nil {
+				t.Errorf("%s: unexpected error without cancellation: %v", name, err)
+			}
+		}
+	})
 }
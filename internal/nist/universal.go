@@ -1,12 +1,21 @@
 package nist
 
-import "math"
+import (
+	"context"
+	"math"
+)
 
 // UniversalStatisticalTest implements Maurer's Universal Statistical test.
 // It returns the p-value and whether it passes at Alpha.
 func UniversalStatisticalTest(bitstream []byte) (float64, bool) {
-	bits := expandBits(bitstream)
-	n := len(bits)
+	o, _ := UniversalStatisticalTestSequence(context.Background(), BitSequenceFromBytes(bitstream))
+	return o.PValue, o.Passed()
+}
+
+// UniversalStatisticalTestSequence runs the Universal Statistical test on a BitSequence of any length.
+// The outcome's Statistic is f_n; its Values hold c(L, K) and sigma.
+func UniversalStatisticalTestSequence(ctx context.Context, bits BitSequence) (Outcome, error) {
+	n := bits.n
 
 	L := 5
 	switch {
@@ -39,7 +48,7 @@ func UniversalStatisticalTest(bitstream []byte) (float64, bool) {
 	Q := 10 * (1 << L)
 	K := n/L - Q
 	if L < 6 || L > 16 || Q < 10*(1<<L) || K <= 0 {
-		return 0, false
+		return notApplicable("insufficient bits: got %d, need at least %d", n, MinBits), nil
 	}
 
 	expected := [...]float64{0, 0, 0, 0, 0, 0, 5.2177052, 6.1962507, 7.1836656, 8.1764248, 9.1723243, 10.170032, 11.168765, 12.16807, 13.167693, 14.167488, 15.167379}
@@ -53,25 +62,32 @@ func UniversalStatisticalTest(bitstream []byte) (float64, bool) {
 	for i := 1; i <= Q; i++ {
 		decRep := 0
 		for j := 0; j < L; j++ {
-			decRep = decRep*2 + int(bits[(i-1)*L+j])
+			decRep = decRep*2 + int(bits.Bit((i-1)*L+j))
 		}
 		T[decRep] = i
 	}
 
 	sum := 0.0
+	done := ctx.Done()
 	for i := Q + 1; i <= Q+K; i++ {
+		if (i-Q-1)%ctxCheckInterval == 0 && isDone(done) {
+			return Outcome{}, ctx.Err()
+		}
 		decRep := 0
 		for j := 0; j < L; j++ {
-			decRep = decRep*2 + int(bits[(i-1)*L+j])
+			decRep = decRep*2 + int(bits.Bit((i-1)*L+j))
 		}
 		sum += math.Log(float64(i-T[decRep])) / math.Log(2)
 		T[decRep] = i
 	}
 
 	phi := sum / float64(K)
-	sigma := (0.7 - 0.8/float64(L) + (4+32/float64(L))*math.Pow(float64(K), -3/float64(L))/15) * math.Sqrt(variance[L]/float64(K))
+	c := 0.7 - 0.8/float64(L) + (4+32/float64(L))*math.Pow(float64(K), -3/float64(L))/15
+	sigma := c * math.Sqrt(variance[L]/float64(K))
 	arg := math.Abs(phi-expected[L]) / (math.Sqrt2 * sigma)
 	pValue := math.Erfc(arg)
 
-	return pValue, pValue >= Alpha
+	o := statisticOutcome(pValue, phi)
+	o.Values = []float64{c, sigma}
+	return o, nil
 }
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/AmmannChristian/nist-sp800-22-rev1a/pkg/pb"
+
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/generators"
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/metrics"
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/nist"
+)
+
+// MaxGeneratedSequences limits num_sequences of GenerateAndTest.
+const MaxGeneratedSequences = 1000
+
+// generate is a variable to allow mocking in tests
+var generate = generators.Generate
+
+// GenerateAndTest implements the GenerateAndTest RPC
+func (s *Server) GenerateAndTest(ctx context.Context, req *pb.Sp80022GenerateAndTestRequest) (*pb.Sp80022GenerateAndTestResponse, error) {
+	startTime := time.Now()
+
+	// Generate unique request ID for log correlation
+	requestID := uuid.New().String()
+
+	log.Info().
+		Str("request_id", requestID).
+		Stringer("generator", req.Generator).
+		Int32("sequence_length_bits", req.SequenceLengthBits).
+		Int32("num_sequences", req.NumSequences).
+		Msg("GenerateAndTest request received")
+
+	// Validate request
+	name, numSequences, err := s.validateGenerateRequest(req)
+	if err != nil {
+		log.Error().
+			Str("request_id", requestID).
+			Err(err).
+			Msg("Request validation failed")
+		metrics.RequestsTotal.WithLabelValues("GenerateAndTest", "error").Inc()
+		return nil, err
+	}
+
+	metrics.RequestsTotal.WithLabelValues("GenerateAndTest", "success").Inc()
+
+	// The deadline covers generation and the test run
+	runCtx, cancel := s.executionContext(ctx)
+	defer cancel()
+
+	seqLen := int(req.SequenceLengthBits)
+	data, err := generate(runCtx, name, seqLen, numSequences)
+	if err != nil {
+		log.Error().
+			Str("request_id", requestID).
+			Err(err).
+			Msg("Generator failed")
+		return nil, executionError(err)
+	}
+
+	cfg := s.suiteConfig(req.Config)
+	if numSequences == 1 {
+		seq, err := nist.NewBitSequence(data, seqLen)
+		if err != nil {
+			return nil, executionError(err)
+		}
+		response, err := s.runTestSuite(runCtx, requestID, seq, cfg, startTime)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.Sp80022GenerateAndTestResponse{Result: &pb.Sp80022GenerateAndTestResponse_TestSuite{TestSuite: response}}, nil
+	}
+
+	report, err := runMultiSequence(runCtx, data, seqLen, numSequences, cfg)
+	if err != nil {
+		log.Error().
+			Str("request_id", requestID).
+			Err(err).
+			Msg("NIST multi-sequence analysis failed")
+		return nil, executionError(err)
+	}
+
+	response := multiSequenceResponse(report, startTime)
+
+	log.Info().
+		Str("request_id", requestID).
+		Int("num_sequences", report.NumSequences).
+		Int64("execution_time_ms", response.ExecutionTimeMs).
+		Msg("Generator analysis completed successfully")
+
+	return &pb.Sp80022GenerateAndTestResponse{Result: &pb.Sp80022GenerateAndTestResponse_MultiSequence{MultiSequence: response}}, nil
+}
+
+// validateGenerateRequest validates the generator request and returns the generator name
+// and the number of sequences to generate.
+func (s *Server) validateGenerateRequest(req *pb.Sp80022GenerateAndTestRequest) (string, int, error) {
+	name, err := generatorFromProto(req.Generator)
+	if err != nil {
+		return "", 0, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	numSequences := int(req.NumSequences)
+	if numSequences < 0 || numSequences > MaxGeneratedSequences {
+		return "", 0, status.Errorf(codes.InvalidArgument, "number of sequences must be 0-%d, got %d",
+			MaxGeneratedSequences, numSequences)
+	}
+	numSequences = max(numSequences, 1)
+
+	seqLen := int(req.SequenceLengthBits)
+	if numSequences > 1 && seqLen%8 != 0 {
+		return "", 0, status.Errorf(codes.InvalidArgument, "sequence length must be a multiple of 8 bits, got %d", seqLen)
+	}
+	if seqLen < nist.MinBits {
+		return "", 0, status.Errorf(codes.InvalidArgument, "sequence length too short: got %d, need at least %d bits",
+			seqLen, nist.MinBits)
+	}
+	maxBits := s.maxBits()
+	if seqLen > maxBits {
+		return "", 0, status.Errorf(codes.InvalidArgument, "sequence length too long: got %d, maximum %d bits",
+			seqLen, maxBits)
+	}
+
+	// All sequences are generated up front and tested one at a time; together they must
+	// fit into the memory of a single run of MAX_BITS bits, which the server checked at startup.
+	seqBytes := uint64((seqLen + 7) / 8) //nolint:gosec // seqLen >= MinBits
+	budget, work := nist.EstimateMemory(maxBits), nist.EstimateMemory(seqLen)-seqBytes
+	if fit := (budget - work) / seqBytes; uint64(numSequences) > fit {
+		return "", 0, status.Errorf(codes.InvalidArgument,
+			"too many sequences: %d sequences of %d bits need about %d MiB, at most %d fit into the %d MiB of a MAX_BITS run",
+			numSequences, seqLen, (uint64(numSequences)*seqBytes+work)>>20, fit, budget>>20)
+	}
+
+	// Generated bits are packed MSB-first
+	if req.Config.GetBitOrder() == pb.Sp80022BitOrder_SP80022_BIT_ORDER_LSB_FIRST {
+		return "", 0, status.Error(codes.InvalidArgument, "bit_order LSB_FIRST does not apply to generated sequences")
+	}
+
+	// Check test parameters against the NIST SP 800-22 recommendations
+	if err := suiteConfigFromProto(req.Config).Validate(seqLen); err != nil {
+		return "", 0, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return name, numSequences, nil
+}
+
+// generatorFromProto converts the requested generator into its generators package name
+func generatorFromProto(g pb.Sp80022Generator) (string, error) {
+	switch g {
+	case pb.Sp80022Generator_SP80022_GENERATOR_LCG:
+		return generators.LCG, nil
+	case pb.Sp80022Generator_SP80022_GENERATOR_QUADRATIC_CONGRUENTIAL_1:
+		return generators.QuadraticCongruential1, nil
+	case pb.Sp80022Generator_SP80022_GENERATOR_QUADRATIC_CONGRUENTIAL_2:
+		return generators.QuadraticCongruential2, nil
+	case pb.Sp80022Generator_SP80022_GENERATOR_CUBIC_CONGRUENTIAL:
+		return generators.CubicCongruential, nil
+	case pb.Sp80022Generator_SP80022_GENERATOR_XOR:
+		return generators.XOR, nil
+	case pb.Sp80022Generator_SP80022_GENERATOR_MODULAR_EXPONENTIATION:
+		return generators.ModExp, nil
+	case pb.Sp80022Generator_SP80022_GENERATOR_BLUM_BLUM_SHUB:
+		return generators.BlumBlumShub, nil
+	case pb.Sp80022Generator_SP80022_GENERATOR_MICALI_SCHNORR:
+		return generators.MicaliSchnorr, nil
+	case pb.Sp80022Generator_SP80022_GENERATOR_G_SHA1:
+		return generators.GSHA1, nil
+	case pb.Sp80022Generator_SP80022_GENERATOR_UNSPECIFIED:
+		return "", fmt.Errorf("generator must be specified")
+	default:
+		return "", fmt.Errorf("unsupported generator %v", g)
+	}
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/generators"
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/nist"
+	pb "github.com/AmmannChristian/nist-sp800-22-rev1a/pkg/pb"
+)
+
+func TestValidateGenerateRequest(t *testing.T) {
+	s := NewServer()
+	seqLen := int32(nist.MinBits)
+	lcg := pb.Sp80022Generator_SP80022_GENERATOR_LCG
+
+	tests := []struct {
+		name string
+		req  *pb.Sp80022GenerateAndTestRequest
+	}{
+		{"no generator", &pb.Sp80022GenerateAndTestRequest{SequenceLengthBits: seqLen}},
+		{"unknown generator", &pb.Sp80022GenerateAndTestRequest{Generator: 99, SequenceLengthBits: seqLen}},
+		{"negative count", &pb.Sp80022GenerateAndTestRequest{Generator: lcg, SequenceLengthBits: seqLen, NumSequences: -1}},
+		{"too many sequences", &pb.Sp80022GenerateAndTestRequest{Generator: lcg, SequenceLengthBits: seqLen, NumSequences: MaxGeneratedSequences + 1}},
+		{"not byte aligned", &pb.Sp80022GenerateAndTestRequest{Generator: lcg, SequenceLengthBits: seqLen + 1, NumSequences: 2}},
+		{"sequence too short", &pb.Sp80022GenerateAndTestRequest{Generator: lcg, SequenceLengthBits: 800}},
+		{"sequence too long", &pb.Sp80022GenerateAndTestRequest{Generator: lcg, SequenceLengthBits: nist.MaxBits + 8}},
+		// 1.25 GB of generated bits
+		{"too many bits", &pb.Sp80022GenerateAndTestRequest{Generator: lcg, SequenceLengthBits: nist.MaxBits, NumSequences: MaxGeneratedSequences}},
+		{"two sequences of MAX_BITS", &pb.Sp80022GenerateAndTestRequest{Generator: lcg, SequenceLengthBits: nist.MaxBits, NumSequences: 2}},
+		{"lsb first", &pb.Sp80022GenerateAndTestRequest{
+			Generator: lcg, SequenceLengthBits: seqLen,
+			Config: &pb.Sp80022TestConfig{BitOrder: pb.Sp80022BitOrder_SP80022_BIT_ORDER_LSB_FIRST},
+		}},
+		{"bad config", &pb.Sp80022GenerateAndTestRequest{
+			Generator: lcg, SequenceLengthBits: seqLen,
+			Config: &pb.Sp80022TestConfig{SerialBlockLength: 40},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := s.validateGenerateRequest(tt.req)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Fatalf("expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+
+	// A single sequence need not be byte aligned
+	name, n, err := s.validateGenerateRequest(&pb.Sp80022GenerateAndTestRequest{Generator: lcg, SequenceLengthBits: seqLen + 1})
+	if err != nil || name != generators.LCG || n != 1 {
+		t.Fatalf("expected one lcg sequence, got %q n=%d err=%v", name, n, err)
+	}
+
+	// 1000 sequences of 1,000,000 bits take 125 MB besides the 40 MB of a run
+	_, n, err = s.validateGenerateRequest(&pb.Sp80022GenerateAndTestRequest{Generator: lcg, SequenceLengthBits: 1_000_000, NumSequences: 1000})
+	if err != nil || n != 1000 {
+		t.Fatalf("expected 1000 sequences, got n=%d err=%v", n, err)
+	}
+}
+
+func TestGeneratorFromProto(t *testing.T) {
+	seen := make(map[string]bool)
+	for value := range pb.Sp80022Generator_name {
+		g := pb.Sp80022Generator(value)
+		if g == pb.Sp80022Generator_SP80022_GENERATOR_UNSPECIFIED {
+			continue
+		}
+		name, err := generatorFromProto(g)
+		if err != nil {
+			t.Fatalf("%v: %v", g, err)
+		}
+		if _, err := generators.New(name); err != nil {
+			t.Errorf("%v maps to %q: %v", g, name, err)
+		}
+		seen[name] = true
+	}
+	if len(seen) != len(generators.Names()) {
+		t.Errorf("expected all %d generators to be selectable, got %v", len(generators.Names()), seen)
+	}
+}
+
+func TestGenerateAndTest(t *testing.T) {
+	s := NewServer()
+
+	resp, err := s.GenerateAndTest(context.Background(), &pb.Sp80022GenerateAndTestRequest{
+		Generator:          pb.Sp80022Generator_SP80022_GENERATOR_G_SHA1,
+		SequenceLengthBits: int32(nist.MinBits),
+	})
+	if err != nil {
+		t.Fatalf("GenerateAndTest failed: %v", err)
+	}
+	suite := resp.GetTestSuite()
+	if suite == nil || suite.SampleSizeBits != int32(nist.MinBits) || suite.TestsTotal != 15 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	if _, err := s.GenerateAndTest(context.Background(), &pb.Sp80022GenerateAndTestRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for empty request, got %v", err)
+	}
+}
+
+func TestGenerateAndTestMultiSequence(t *testing.T) {
+	origGenerate, origRun := generate, runMultiSequence
+	defer func() { generate, runMultiSequence = origGenerate, origRun }()
+
+	s := NewServer()
+	req := &pb.Sp80022GenerateAndTestRequest{
+		Generator:          pb.Sp80022Generator_SP80022_GENERATOR_BLUM_BLUM_SHUB,
+		SequenceLengthBits: int32(nist.MinBits),
+		NumSequences:       3,
+	}
+
+	generate = func(ctx context.Context, name string, n, num int) ([]byte, error) {
+		if name != generators.BlumBlumShub || n != nist.MinBits || num != 3 {
+			t.Errorf("unexpected generator call: %s %d %d", name, n, num)
+		}
+		return make([]byte, num*n/8), nil
+	}
+	runMultiSequence = func(ctx context.Context, bitstream []byte, sequenceLength, numSequences int, cfg nist.SuiteConfig) (*nist.MultiSequenceReport, error) {
+		if len(bitstream) != 3*nist.MinBits/8 {
+			t.Errorf("unexpected bitstream of %d bytes", len(bitstream))
+		}
+		return &nist.MultiSequenceReport{
+			SequenceLength: sequenceLength,
+			NumSequences:   numSequences,
+			Config:         cfg.WithDefaults(),
+			Results:        []nist.SequenceAnalysis{{Name: "frequency_monobit", SampleSize: 3, PassedSequences: 3}},
+		}, nil
+	}
+	resp, err := s.GenerateAndTest(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GenerateAndTest failed: %v", err)
+	}
+	multi := resp.GetMultiSequence()
+	if multi == nil || multi.NumSequences != 3 || multi.SequenceLengthBits != int32(nist.MinBits) || len(multi.Results) != 1 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	runMultiSequence = func(ctx context.Context, bitstream []byte, sequenceLength, numSequences int, cfg nist.SuiteConfig) (*nist.MultiSequenceReport, error) {
+		return nil, fmt.Errorf("mock error")
+	}
+	if _, err := s.GenerateAndTest(context.Background(), req); err == nil || status.Code(err) == codes.InvalidArgument {
+		t.Fatalf("expected internal error, got %v", err)
+	}
+
+	generate = func(ctx context.Context, name string, n, num int) ([]byte, error) {
+		return nil, context.Canceled
+	}
+	if _, err := s.GenerateAndTest(context.Background(), req); status.Code(err) != codes.Canceled {
+		t.Fatalf("expected Canceled, got %v", err)
+	}
+}
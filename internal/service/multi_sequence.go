@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/AmmannChristian/nist-sp800-22-rev1a/pkg/pb"
+
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/metrics"
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/nist"
+)
+
+// runMultiSequence is a variable to allow mocking in tests
+var runMultiSequence = nist.RunMultiSequenceContext
+
+// RunMultiSequenceAnalysis implements the RunMultiSequenceAnalysis RPC
+func (s *Server) RunMultiSequenceAnalysis(ctx context.Context, req *pb.Sp80022MultiSequenceRequest) (*pb.Sp80022MultiSequenceResponse, error) {
+	startTime := time.Now()
+
+	// Generate unique request ID for log correlation
+	requestID := uuid.New().String()
+
+	log.Info().
+		Str("request_id", requestID).
+		Int("bitstream_bytes", len(req.Bitstream)).
+		Int32("sequence_length_bits", req.SequenceLengthBits).
+		Int32("num_sequences", req.NumSequences).
+		Msg("RunMultiSequenceAnalysis request received")
+
+	// Validate request
+	numSequences, err := s.validateMultiSequenceRequest(req)
+	if err != nil {
+		log.Error().
+			Str("request_id", requestID).
+			Err(err).
+			Msg("Request validation failed")
+		metrics.RequestsTotal.WithLabelValues("RunMultiSequenceAnalysis", "error").Inc()
+		return nil, err
+	}
+
+	metrics.RequestsTotal.WithLabelValues("RunMultiSequenceAnalysis", "success").Inc()
+
+	cfg := s.suiteConfig(req.Config)
+	runCtx, cancel := s.executionContext(ctx)
+	defer cancel()
+
+	report, err := runMultiSequence(runCtx, req.Bitstream, int(req.SequenceLengthBits), numSequences, cfg)
+	if err != nil {
+		log.Error().
+			Str("request_id", requestID).
+			Err(err).
+			Msg("NIST multi-sequence analysis failed")
+		return nil, executionError(err)
+	}
+
+	response := multiSequenceResponse(report, startTime)
+
+	log.Info().
+		Str("request_id", requestID).
+		Int("num_sequences", report.NumSequences).
+		Int("statistics", len(report.Results)).
+		Int64("execution_time_ms", response.ExecutionTimeMs).
+		Msg("Multi-sequence analysis completed successfully")
+
+	return response, nil
+}
+
+// multiSequenceResponse converts a multi-sequence report into its protobuf form.
+func multiSequenceResponse(report *nist.MultiSequenceReport, startTime time.Time) *pb.Sp80022MultiSequenceResponse {
+	response := &pb.Sp80022MultiSequenceResponse{
+		Timestamp:          time.Now().Format(time.RFC3339),
+		SequenceLengthBits: int32(report.SequenceLength), //nolint:gosec // bounded by MaxBits
+		NumSequences:       int32(report.NumSequences),   //nolint:gosec // bounded by the bitstream size
+		Results:            make([]*pb.Sp80022SequenceAnalysis, len(report.Results)),
+		EffectiveConfig:    suiteConfigToProto(report.Config),
+	}
+
+	for i, a := range report.Results {
+		histogram := make([]int32, len(a.Histogram))
+		for j, c := range a.Histogram {
+			histogram[j] = int32(c) //nolint:gosec // bounded by num_sequences
+		}
+		response.Results[i] = &pb.Sp80022SequenceAnalysis{
+			Name:                 a.Name,
+			Histogram:            histogram,
+			PValueUniformity:     a.PValueT,
+			UniformityPassed:     a.UniformityPassed,
+			PassedSequences:      int32(a.PassedSequences), //nolint:gosec // bounded by num_sequences
+			SampleSize:           int32(a.SampleSize),      //nolint:gosec // bounded by num_sequences
+			Proportion:           a.Proportion,
+			ProportionLowerBound: a.ProportionLower,
+			ProportionUpperBound: a.ProportionUpper,
+			ProportionPassed:     a.ProportionPassed,
+		}
+	}
+
+	response.ExecutionTimeMs = time.Since(startTime).Milliseconds()
+	return response
+}
+
+// validateMultiSequenceRequest validates the multi-sequence request and returns the
+// number of sequences to test.
+func (s *Server) validateMultiSequenceRequest(req *pb.Sp80022MultiSequenceRequest) (int, error) {
+	if len(req.Bitstream) == 0 {
+		return 0, status.Error(codes.InvalidArgument, "bitstream cannot be empty")
+	}
+
+	seqLen := int(req.SequenceLengthBits)
+	if seqLen%8 != 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "sequence length must be a multiple of 8 bits, got %d", seqLen)
+	}
+	if seqLen < nist.MinBits {
+		return 0, status.Errorf(codes.InvalidArgument, "sequence length too short: got %d, need at least %d bits",
+			seqLen, nist.MinBits)
+	}
+	if maxBits := s.maxBits(); seqLen > maxBits {
+		return 0, status.Errorf(codes.InvalidArgument, "sequence length too long: got %d, maximum %d bits",
+			seqLen, maxBits)
+	}
+
+	available := len(req.Bitstream) * 8
+	numSequences := int(req.NumSequences)
+	if numSequences < 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "number of sequences cannot be negative: %d", numSequences)
+	}
+	if numSequences == 0 {
+		numSequences = available / seqLen
+	}
+	if numSequences == 0 || numSequences*seqLen > available {
+		return 0, status.Errorf(codes.InvalidArgument, "insufficient bits: need %d sequences of %d bits, got %d bits",
+			max(numSequences, 1), seqLen, available)
+	}
+
+	// Check test parameters against the NIST SP 800-22 recommendations
+	if err := suiteConfigFromProto(req.Config).Validate(seqLen); err != nil {
+		return 0, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return numSequences, nil
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/nist"
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/testrand"
+	pb "github.com/AmmannChristian/nist-sp800-22-rev1a/pkg/pb"
+)
+
+func TestValidateMultiSequenceRequest(t *testing.T) {
+	s := NewServer()
+	seqLen := int32(nist.MinBits)
+
+	tests := []struct {
+		name string
+		req  *pb.Sp80022MultiSequenceRequest
+	}{
+		{"empty bitstream", &pb.Sp80022MultiSequenceRequest{SequenceLengthBits: seqLen}},
+		{"not byte aligned", &pb.Sp80022MultiSequenceRequest{Bitstream: make([]byte, 10), SequenceLengthBits: seqLen + 1}},
+		{"sequence too short", &pb.Sp80022MultiSequenceRequest{Bitstream: make([]byte, 10), SequenceLengthBits: 800}},
+		{"sequence too long", &pb.Sp80022MultiSequenceRequest{Bitstream: make([]byte, 10), SequenceLengthBits: nist.MaxBits + 8}},
+		{"negative count", &pb.Sp80022MultiSequenceRequest{Bitstream: make([]byte, nist.MinBits/8), SequenceLengthBits: seqLen, NumSequences: -1}},
+		{"not enough bits", &pb.Sp80022MultiSequenceRequest{Bitstream: make([]byte, nist.MinBits/8), SequenceLengthBits: seqLen, NumSequences: 2}},
+		{"less than one sequence", &pb.Sp80022MultiSequenceRequest{Bitstream: make([]byte, nist.MinBits/8-1), SequenceLengthBits: seqLen}},
+		{"bad config", &pb.Sp80022MultiSequenceRequest{
+			Bitstream: make([]byte, nist.MinBits/8), SequenceLengthBits: seqLen,
+			Config: &pb.Sp80022TestConfig{SerialBlockLength: 40},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.validateMultiSequenceRequest(tt.req)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Fatalf("expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+
+	n, err := s.validateMultiSequenceRequest(&pb.Sp80022MultiSequenceRequest{
+		Bitstream:          make([]byte, 2*nist.MinBits/8+5),
+		SequenceLengthBits: seqLen,
+	})
+	if err != nil || n != 2 {
+		t.Fatalf("expected 2 sequences to be derived, got n=%d err=%v", n, err)
+	}
+}
+
+func TestRunMultiSequenceAnalysis(t *testing.T) {
+	s := NewServer()
+
+	n := 2 * nist.MinBits / 8
+	bits := testrand.Bytes(n, 777)
+
+	resp, err := s.RunMultiSequenceAnalysis(context.Background(), &pb.Sp80022MultiSequenceRequest{
+		Bitstream:          bits,
+		SequenceLengthBits: int32(nist.MinBits),
+	})
+	if err != nil {
+		t.Fatalf("RunMultiSequenceAnalysis failed: %v", err)
+	}
+	if resp.NumSequences != 2 || len(resp.Results) == 0 {
+		t.Fatalf("unexpected response: sequences=%d results=%d", resp.NumSequences, len(resp.Results))
+	}
+	if resp.EffectiveConfig.GetSerialBlockLength() != 16 {
+		t.Fatalf("unexpected effective config: %+v", resp.EffectiveConfig)
+	}
+	for _, r := range resp.Results {
+		if len(r.Histogram) != 10 || r.SampleSize > 2 {
+			t.Fatalf("unexpected analysis for %s: %+v", r.Name, r)
+		}
+	}
+
+	if _, err := s.RunMultiSequenceAnalysis(context.Background(), &pb.Sp80022MultiSequenceRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for empty request, got %v", err)
+	}
+}
+
+func TestRunMultiSequenceAnalysisMocked(t *testing.T) {
+	orig := runMultiSequence
+	defer func() { runMultiSequence = orig }()
+
+	s := NewServer()
+	req := &pb.Sp80022MultiSequenceRequest{Bitstream: make([]byte, nist.MinBits/8), SequenceLengthBits: int32(nist.MinBits)}
+
+	runMultiSequence = func(ctx context.Context, bitstream []byte, sequenceLength, numSequences int, cfg nist.SuiteConfig) (*nist.MultiSequenceReport, error) {
+		return nil, fmt.Errorf("mock error")
+	}
+	if _, err := s.RunMultiSequenceAnalysis(context.Background(), req); err == nil || status.Code(err) == codes.InvalidArgument {
+		t.Fatalf("expected internal error, got %v", err)
+	}
+
+	runMultiSequence = func(ctx context.Context, bitstream []byte, sequenceLength, numSequences int, cfg nist.SuiteConfig) (*nist.MultiSequenceReport, error) {
+		return nil, fmt.Errorf("mock: %w", nist.ErrInvalidParameter)
+	}
+	if _, err := s.RunMultiSequenceAnalysis(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+}
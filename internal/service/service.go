@@ -3,14 +3,18 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	pb "github.com/AmmannChristian/nist-sp800-22-rev1a/pkg/pb"
 
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/bitstream"
 	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/metrics"
 	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/nist"
 
@@ -18,7 +22,7 @@ import (
 )
 
 // runAllTests is a variable to allow mocking in tests
-var runAllTests = nist.RunAllTests
+var runAllTests = nist.RunAllTestsSequence
 
 const (
 	// Version of the service (2.0.0 for breaking API change)
@@ -28,14 +32,73 @@ const (
 	Alpha = 0.01
 )
 
+// Options holds the server-side execution settings.
+type Options struct {
+	// TestConcurrency is the maximum number of tests run in parallel per sequence
+	// (zero selects GOMAXPROCS).
+	TestConcurrency int
+	// MaxExecutionTime bounds the test run of a single request (zero disables the limit).
+	MaxExecutionTime time.Duration
+	// MaxBits caps the bitstream size of RunTestSuite and the sequence length of
+	// RunMultiSequenceAnalysis (zero selects nist.MaxBits).
+	MaxBits int
+	// StreamMaxBits caps the bitstream size accepted by RunTestSuiteStream (zero selects nist.MaxBits).
+	StreamMaxBits int
+}
+
 // Server implements the Sp80022TestService
 type Server struct {
 	pb.UnimplementedSp80022TestServiceServer
+
+	opts Options
 }
 
-// NewServer creates a new Sp80022TestService server
+// NewServer creates a new Sp80022TestService server with default options
 func NewServer() *Server {
-	return &Server{}
+	return NewServerWithOptions(Options{})
+}
+
+// NewServerWithOptions creates a new Sp80022TestService server
+func NewServerWithOptions(opts Options) *Server {
+	return &Server{opts: opts}
+}
+
+// executionContext derives the context for a test run, applying MaxExecutionTime.
+func (s *Server) executionContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.opts.MaxExecutionTime > 0 {
+		return context.WithTimeout(ctx, s.opts.MaxExecutionTime)
+	}
+	return context.WithCancel(ctx)
+}
+
+// executionError maps an error from the test engine to a gRPC status error.
+func executionError(err error) error {
+	switch {
+	case errors.Is(err, nist.ErrInvalidParameter):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, "test execution canceled")
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, "test execution deadline exceeded")
+	default:
+		return fmt.Errorf("test execution failed: %w", err)
+	}
+}
+
+// suiteConfig builds the test parameters for a request, applying the server settings.
+func (s *Server) suiteConfig(c *pb.Sp80022TestConfig) nist.SuiteConfig {
+	cfg := suiteConfigFromProto(c)
+	cfg.Workers = s.opts.TestConcurrency
+	cfg.MaxBits = s.maxBits()
+	return cfg
+}
+
+// maxBits returns the effective bitstream size limit of RunTestSuite.
+func (s *Server) maxBits() int {
+	if s.opts.MaxBits > 0 {
+		return s.opts.MaxBits
+	}
+	return nist.MaxBits
 }
 
 // RunTestSuite implements the RunTestSuite RPC
@@ -48,10 +111,12 @@ func (s *Server) RunTestSuite(ctx context.Context, req *pb.Sp80022TestRequest) (
 	log.Info().
 		Str("request_id", requestID).
 		Int("bitstream_bytes", len(req.Bitstream)).
+		Stringer("encoding", req.Encoding).
 		Msg("RunTestSuite request received")
 
 	// Validate request
-	if err := s.validateRequest(req); err != nil {
+	seq, err := s.validateRequest(req)
+	if err != nil {
 		log.Error().
 			Str("request_id", requestID).
 			Err(err).
@@ -62,22 +127,30 @@ func (s *Server) RunTestSuite(ctx context.Context, req *pb.Sp80022TestRequest) (
 
 	metrics.RequestsTotal.WithLabelValues("RunTestSuite", "success").Inc()
 
-	// Run NIST tests in pure Go
+	return s.runTestSuite(ctx, requestID, seq, s.suiteConfig(req.Config), startTime)
+}
+
+// runTestSuite runs the battery on a validated bitstream and builds the response.
+func (s *Server) runTestSuite(ctx context.Context, requestID string, seq nist.BitSequence, cfg nist.SuiteConfig, startTime time.Time) (*pb.Sp80022TestResponse, error) {
+	// Run NIST tests in pure Go, aborting when the client goes away or the deadline passes
+	runCtx, cancel := s.executionContext(ctx)
+	defer cancel()
+
 	testStart := time.Now()
-	results, err := runAllTests(req.Bitstream)
+	results, err := runAllTests(runCtx, seq, cfg)
 	if err != nil {
 		log.Error().
 			Str("request_id", requestID).
 			Err(err).
 			Msg("NIST test execution failed")
-		return nil, fmt.Errorf("test execution failed: %w", err)
+		return nil, executionError(err)
 	}
 
 	// Record overall duration
 	duration := time.Since(testStart)
 	metrics.OverallDuration.Observe(duration.Seconds())
 
-	sampleBits := int32(len(req.Bitstream) * 8) //nolint:gosec // safe: MaxBits < 2^31
+	sampleBits := int32(seq.Len()) //nolint:gosec // safe: MaxBits and StreamMaxBits < 2^31
 
 	// Build response
 	response := &pb.Sp80022TestResponse{
@@ -85,6 +158,7 @@ func (s *Server) RunTestSuite(ctx context.Context, req *pb.Sp80022TestRequest) (
 		SampleSizeBits:  sampleBits,
 		Results:         make([]*pb.Sp80022TestResult, len(results)),
 		ExecutionTimeMs: time.Since(startTime).Milliseconds(),
+		EffectiveConfig: suiteConfigToProto(cfg.WithDefaults()),
 	}
 
 	// Convert results and compute overall metrics
@@ -93,44 +167,16 @@ func (s *Server) RunTestSuite(ctx context.Context, req *pb.Sp80022TestRequest) (
 	pValues := make([]float64, 0, len(results))
 
 	for i, result := range results {
-		// Skip tests that weren't implemented (p_value < 0)
-		if result.PValue < 0.0 {
-			// Mark as skipped
-			pbResult := &pb.Sp80022TestResult{
-				Name:   result.Name,
-				PValue: result.PValue,
-				Passed: false,
-			}
-
-			if result.Warning != "" {
-				pbResult.Warning = &result.Warning
-			}
-
-			response.Results[i] = pbResult
-			continue
-		}
-
-		// This is a real test result
-		testsRun++
-
-		// Record metrics for this test
-		status := "fail"
-		if result.Passed {
-			status = "pass"
-			passedCount++
-		}
-		metrics.TestsTotal.WithLabelValues(result.Name, status).Inc()
-		metrics.PValue.WithLabelValues(result.Name).Set(result.PValue)
-
-		// Convert to protobuf message
 		pbResult := &pb.Sp80022TestResult{
-			Name:   result.Name,
-			PValue: result.PValue,
-			Passed: result.Passed,
+			Name:            result.Name,
+			PValue:          result.PValue,
+			Passed:          result.Passed,
+			Status:          statusToProto(result.Status),
+			ExecutionTimeMs: float64(result.Duration) / float64(time.Millisecond),
 		}
 
-		if result.Proportion > 0 {
-			pbResult.Proportion = &result.Proportion
+		if len(result.SubResults) > 0 {
+			pbResult.SubResults = subResultsToProto(result.SubResults)
 		}
 
 		if result.Warning != "" {
@@ -139,11 +185,26 @@ func (s *Server) RunTestSuite(ctx context.Context, req *pb.Sp80022TestRequest) (
 
 		response.Results[i] = pbResult
 
-		// Only add real p-values to uniformity check
+		// Record metrics for this test
+		metrics.RecordTestDuration(result.Name, result.Duration.Seconds())
+		switch result.Status {
+		case nist.StatusPassed:
+			metrics.TestsTotal.WithLabelValues(result.Name, "pass").Inc()
+			passedCount++
+		case nist.StatusFailed:
+			metrics.TestsTotal.WithLabelValues(result.Name, "fail").Inc()
+		default:
+			// Not applicable or error: no p-value, excluded from the pass rate
+			metrics.TestsTotal.WithLabelValues(result.Name, result.Status.String()).Inc()
+			continue
+		}
+
+		testsRun++
+		metrics.PValue.WithLabelValues(result.Name).Set(result.PValue)
 		pValues = append(pValues, result.PValue)
 	}
 
-	// Calculate overall pass rate ONLY for implemented tests
+	// Calculate overall pass rate ONLY for tests that produced a p-value
 	if testsRun > 0 {
 		response.OverallPassRate = float64(passedCount) / float64(testsRun)
 		metrics.LastOverallPassRate.Set(response.OverallPassRate)
@@ -174,27 +235,180 @@ func (s *Server) RunTestSuite(ctx context.Context, req *pb.Sp80022TestRequest) (
 	return response, nil
 }
 
-// validateRequest validates the test request
-func (s *Server) validateRequest(req *pb.Sp80022TestRequest) error {
+// validateRequest validates the test request and returns its decoded bit sequence
+func (s *Server) validateRequest(req *pb.Sp80022TestRequest) (nist.BitSequence, error) {
 	if len(req.Bitstream) == 0 {
-		return fmt.Errorf("bitstream cannot be empty")
+		return nist.BitSequence{}, status.Error(codes.InvalidArgument, "bitstream cannot be empty")
+	}
+
+	enc, err := encodingFromProto(req.Encoding)
+	if err != nil {
+		return nist.BitSequence{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+	// ASCII bits are listed in order; there are no bytes to read in either order
+	if enc == bitstream.ASCIIBits && req.Config.GetBitOrder() == pb.Sp80022BitOrder_SP80022_BIT_ORDER_LSB_FIRST {
+		return nist.BitSequence{}, status.Error(codes.InvalidArgument, "bit_order LSB_FIRST does not apply to ASCII_BITS input")
+	}
+	data, numBits, err := bitstream.Decode(req.Bitstream, enc)
+	if err != nil {
+		return nist.BitSequence{}, status.Errorf(codes.InvalidArgument, "cannot decode %s bitstream: %v", enc, err)
+	}
+
+	return validateBitstream(data, numBits, req.BitLength, req.Config, s.maxBits())
+}
+
+// validateBitstream checks the size of a bitstream of numBits bits and the test parameters
+// requested for it, and returns its first bitLength bits (all bits if bitLength is nil)
+func validateBitstream(data []byte, numBits int, bitLength *int64, config *pb.Sp80022TestConfig, maxBits int) (nist.BitSequence, error) {
+	if numBits == 0 {
+		return nist.BitSequence{}, status.Error(codes.InvalidArgument, "bitstream cannot be empty")
 	}
 
-	numBits := len(req.Bitstream) * 8
+	// Only the pad bits of the last byte may be excluded
+	if bitLength != nil {
+		lowest := int64(len(data))*8 - 7
+		if *bitLength < lowest || *bitLength > int64(numBits) {
+			return nist.BitSequence{}, status.Errorf(codes.InvalidArgument, "invalid bit_length %d for %d bytes: must be %d-%d",
+				*bitLength, len(data), lowest, numBits)
+		}
+		numBits = int(*bitLength)
+	}
 
 	// Check minimum bits (Universal Test requires 387,840)
 	if numBits < nist.MinBits {
-		return fmt.Errorf("insufficient bits: got %d, need at least %d (%d bytes)",
+		return nist.BitSequence{}, status.Errorf(codes.InvalidArgument, "insufficient bits: got %d, need at least %d (%d bytes)",
 			numBits, nist.MinBits, nist.MinBits/8)
 	}
 
 	// Check maximum bits (prevent excessive memory use)
-	if numBits > nist.MaxBits {
-		return fmt.Errorf("too many bits: got %d, maximum %d (%d bytes)",
-			numBits, nist.MaxBits, nist.MaxBits/8)
+	if numBits > maxBits {
+		return nist.BitSequence{}, status.Errorf(codes.InvalidArgument, "too many bits: got %d, maximum %d (%d bytes)",
+			numBits, maxBits, maxBits/8)
 	}
 
-	return nil
+	// Check test parameters against the NIST SP 800-22 recommendations
+	cfg := suiteConfigFromProto(config)
+	if err := cfg.Validate(numBits); err != nil {
+		return nist.BitSequence{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	seq, err := nist.NewBitSequenceOrder(data, numBits, cfg.BitOrder)
+	if err != nil {
+		return nist.BitSequence{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return seq, nil
+}
+
+// suiteConfigFromProto converts the optional request config into a nist.SuiteConfig.
+// Unset fields stay zero and therefore select the NIST defaults.
+func suiteConfigFromProto(c *pb.Sp80022TestConfig) nist.SuiteConfig {
+	if c == nil {
+		return nist.SuiteConfig{}
+	}
+	return nist.SuiteConfig{
+		BlockFrequencyBlockLength:     int(c.BlockFrequencyBlockLength),
+		NonOverlappingTemplateLength:  int(c.NonOverlappingTemplateBlockLength),
+		NonOverlappingTemplateBlocks:  int(c.NonOverlappingTemplateNumBlocks),
+		OverlappingTemplateLength:     int(c.OverlappingTemplateBlockLength),
+		ApproximateEntropyBlockLength: int(c.ApproximateEntropyBlockLength),
+		SerialBlockLength:             int(c.SerialBlockLength),
+		LinearComplexityBlockLength:   int(c.LinearComplexitySequenceLength),
+		BinaryMatrixRows:              int(c.BinaryMatrixRows),
+		BinaryMatrixColumns:           int(c.BinaryMatrixColumns),
+		BitOrder:                      bitOrderFromProto(c.BitOrder),
+	}
+}
+
+// suiteConfigToProto converts an effective nist.SuiteConfig back into its protobuf form.
+func suiteConfigToProto(c nist.SuiteConfig) *pb.Sp80022TestConfig {
+	return &pb.Sp80022TestConfig{
+		BlockFrequencyBlockLength:         int32(c.BlockFrequencyBlockLength),     //nolint:gosec // validated block length
+		NonOverlappingTemplateBlockLength: int32(c.NonOverlappingTemplateLength),  //nolint:gosec // validated template length
+		OverlappingTemplateBlockLength:    int32(c.OverlappingTemplateLength),     //nolint:gosec // validated template length
+		ApproximateEntropyBlockLength:     int32(c.ApproximateEntropyBlockLength), //nolint:gosec // validated block length
+		SerialBlockLength:                 int32(c.SerialBlockLength),             //nolint:gosec // validated block length
+		LinearComplexitySequenceLength:    int32(c.LinearComplexityBlockLength),   //nolint:gosec // validated block length
+		NonOverlappingTemplateNumBlocks:   int32(c.NonOverlappingTemplateBlocks),  //nolint:gosec // validated block count
+		BinaryMatrixRows:                  int32(c.BinaryMatrixRows),              //nolint:gosec // validated matrix size
+		BinaryMatrixColumns:               int32(c.BinaryMatrixColumns),           //nolint:gosec // validated matrix size
+		BitOrder:                          bitOrderToProto(c.BitOrder),
+	}
+}
+
+// bitOrderFromProto converts the requested bit order; unknown values are passed on
+// for SuiteConfig.Validate to reject.
+func bitOrderFromProto(o pb.Sp80022BitOrder) nist.BitOrder {
+	switch o {
+	case pb.Sp80022BitOrder_SP80022_BIT_ORDER_UNSPECIFIED, pb.Sp80022BitOrder_SP80022_BIT_ORDER_MSB_FIRST:
+		return nist.MSBFirst
+	case pb.Sp80022BitOrder_SP80022_BIT_ORDER_LSB_FIRST:
+		return nist.LSBFirst
+	default:
+		return nist.BitOrder(o)
+	}
+}
+
+func bitOrderToProto(o nist.BitOrder) pb.Sp80022BitOrder {
+	if o == nist.LSBFirst {
+		return pb.Sp80022BitOrder_SP80022_BIT_ORDER_LSB_FIRST
+	}
+	return pb.Sp80022BitOrder_SP80022_BIT_ORDER_MSB_FIRST
+}
+
+// encodingFromProto converts the requested bitstream encoding
+func encodingFromProto(e pb.Sp80022BitstreamEncoding) (bitstream.Encoding, error) {
+	switch e {
+	case pb.Sp80022BitstreamEncoding_SP80022_BITSTREAM_ENCODING_UNSPECIFIED, pb.Sp80022BitstreamEncoding_SP80022_BITSTREAM_ENCODING_RAW:
+		return bitstream.Raw, nil
+	case pb.Sp80022BitstreamEncoding_SP80022_BITSTREAM_ENCODING_ASCII_BITS:
+		return bitstream.ASCIIBits, nil
+	case pb.Sp80022BitstreamEncoding_SP80022_BITSTREAM_ENCODING_HEX:
+		return bitstream.Hex, nil
+	case pb.Sp80022BitstreamEncoding_SP80022_BITSTREAM_ENCODING_BASE64_TEXT:
+		return bitstream.Base64, nil
+	default:
+		return 0, fmt.Errorf("unsupported encoding %v", e)
+	}
+}
+
+// statusToProto converts a nist.Status into its protobuf enum.
+func statusToProto(s nist.Status) pb.Sp80022TestStatus {
+	switch s {
+	case nist.StatusPassed:
+		return pb.Sp80022TestStatus_SP80022_TEST_STATUS_PASSED
+	case nist.StatusFailed:
+		return pb.Sp80022TestStatus_SP80022_TEST_STATUS_FAILED
+	case nist.StatusNotApplicable:
+		return pb.Sp80022TestStatus_SP80022_TEST_STATUS_NOT_APPLICABLE
+	case nist.StatusError:
+		return pb.Sp80022TestStatus_SP80022_TEST_STATUS_ERROR
+	default:
+		return pb.Sp80022TestStatus_SP80022_TEST_STATUS_UNSPECIFIED
+	}
+}
+
+// subResultsToProto converts the sub-statistics of a multi-valued test into protobuf messages.
+func subResultsToProto(subs []nist.SubResult) []*pb.Sp80022SubResult {
+	out := make([]*pb.Sp80022SubResult, len(subs))
+	for i, sub := range subs {
+		counts := make([]int64, len(sub.Counts))
+		for j, c := range sub.Counts {
+			counts[j] = int64(c)
+		}
+		out[i] = &pb.Sp80022SubResult{
+			Name:      sub.Name,
+			PValue:    sub.PValue,
+			Passed:    sub.Passed,
+			Statistic: sub.Statistic,
+			Counts:    counts,
+			Values:    sub.Values,
+		}
+		if sub.State != 0 {
+			state := int32(sub.State) //nolint:gosec // random walk states are within -9..9
+			out[i].State = &state
+		}
+	}
+	return out
 }
 
 // calculatePValueUniformity performs a chi-squared test on p-value distribution
@@ -1,12 +1,23 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/metrics"
 	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/nist"
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/testrand"
 	pb "github.com/AmmannChristian/nist-sp800-22-rev1a/pkg/pb"
 )
 
@@ -14,16 +25,123 @@ func TestValidateRequest(t *testing.T) {
 	s := NewServer()
 
 	tooSmall := &pb.Sp80022TestRequest{Bitstream: make([]byte, 10)}
-	if err := s.validateRequest(tooSmall); err == nil {
+	if _, err := s.validateRequest(tooSmall); err == nil {
 		t.Fatalf("expected error for insufficient bits")
 	}
 
 	justRight := &pb.Sp80022TestRequest{Bitstream: make([]byte, nist.MinBits/8)}
-	if err := s.validateRequest(justRight); err != nil {
+	if _, err := s.validateRequest(justRight); err != nil {
 		t.Fatalf("unexpected error for valid size: %v", err)
 	}
 }
 
+func TestValidateRequestBitLength(t *testing.T) {
+	s := NewServer()
+	data := make([]byte, nist.MinBits/8+1)
+	data[len(data)-1] = 0xFF
+
+	seq, err := s.validateRequest(&pb.Sp80022TestRequest{Bitstream: data, BitLength: proto.Int64(nist.MinBits + 3)})
+	if err != nil {
+		t.Fatalf("unexpected error for a valid bit_length: %v", err)
+	}
+	if seq.Len() != nist.MinBits+3 || seq.OnesCount() != 3 {
+		t.Fatalf("expected %d bits with 3 ones, got %d bits with %d ones", nist.MinBits+3, seq.Len(), seq.OnesCount())
+	}
+
+	seq, err = s.validateRequest(&pb.Sp80022TestRequest{Bitstream: data})
+	if err != nil || seq.Len() != len(data)*8 {
+		t.Fatalf("expected all %d bits by default, got %d (%v)", len(data)*8, seq.Len(), err)
+	}
+
+	// Only the pad bits of the last byte may be dropped
+	for _, n := range []int64{nist.MinBits, int64(len(data))*8 + 1, -1} {
+		_, err := s.validateRequest(&pb.Sp80022TestRequest{Bitstream: data, BitLength: proto.Int64(n)})
+		if status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), "bit_length") {
+			t.Errorf("bit_length %d: expected InvalidArgument, got %v", n, err)
+		}
+	}
+
+	// The minimum applies to bit_length, not to the byte count
+	_, err = s.validateRequest(&pb.Sp80022TestRequest{Bitstream: make([]byte, nist.MinBits/8), BitLength: proto.Int64(nist.MinBits - 1)})
+	if status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), "insufficient bits") {
+		t.Errorf("expected insufficient bits, got %v", err)
+	}
+}
+
+func TestValidateRequestEncoding(t *testing.T) {
+	s := NewServer()
+
+	raw := make([]byte, nist.MinBits/8+1)
+	for i := range raw {
+		raw[i] = byte(i * 31)
+	}
+	var ascii strings.Builder
+	for i, b := range raw {
+		fmt.Fprintf(&ascii, "%08b", b)
+		if i%16 == 15 {
+			ascii.WriteString("\n")
+		}
+	}
+	// One extra bit: ASCII input need not be byte aligned
+	ascii.WriteString("1")
+
+	tests := []struct {
+		name     string
+		encoding pb.Sp80022BitstreamEncoding
+		input    []byte
+		bits     int
+	}{
+		{"unspecified", pb.Sp80022BitstreamEncoding_SP80022_BITSTREAM_ENCODING_UNSPECIFIED, raw, len(raw) * 8},
+		{"raw", pb.Sp80022BitstreamEncoding_SP80022_BITSTREAM_ENCODING_RAW, raw, len(raw) * 8},
+		{"ascii", pb.Sp80022BitstreamEncoding_SP80022_BITSTREAM_ENCODING_ASCII_BITS, []byte(ascii.String()), len(raw)*8 + 1},
+		{"hex", pb.Sp80022BitstreamEncoding_SP80022_BITSTREAM_ENCODING_HEX, []byte(hex.EncodeToString(raw)), len(raw) * 8},
+		{"base64", pb.Sp80022BitstreamEncoding_SP80022_BITSTREAM_ENCODING_BASE64_TEXT, []byte(base64.StdEncoding.EncodeToString(raw)), len(raw) * 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seq, err := s.validateRequest(&pb.Sp80022TestRequest{Bitstream: tt.input, Encoding: tt.encoding})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if seq.Len() != tt.bits || !bytes.Equal(seq.Bytes()[:len(raw)], raw) {
+				t.Fatalf("decoded %d bits, expected %d bits of the raw input", seq.Len(), tt.bits)
+			}
+		})
+	}
+
+	// bit_length applies to the decoded bits
+	seq, err := s.validateRequest(&pb.Sp80022TestRequest{
+		Bitstream: []byte(hex.EncodeToString(raw)),
+		Encoding:  pb.Sp80022BitstreamEncoding_SP80022_BITSTREAM_ENCODING_HEX,
+		BitLength: proto.Int64(int64(len(raw)*8 - 3)),
+	})
+	if err != nil || seq.Len() != len(raw)*8-3 {
+		t.Fatalf("expected %d bits, got %d (%v)", len(raw)*8-3, seq.Len(), err)
+	}
+
+	invalid := []struct {
+		name     string
+		encoding pb.Sp80022BitstreamEncoding
+		input    string
+		detail   string
+	}{
+		{"ascii character", pb.Sp80022BitstreamEncoding_SP80022_BITSTREAM_ENCODING_ASCII_BITS, "0110x", "'x' at offset 4"},
+		{"hex character", pb.Sp80022BitstreamEncoding_SP80022_BITSTREAM_ENCODING_HEX, "zz", "'z' at offset 0"},
+		{"base64 character", pb.Sp80022BitstreamEncoding_SP80022_BITSTREAM_ENCODING_BASE64_TEXT, "pQ8!", "'!' at offset 3"},
+		{"only whitespace", pb.Sp80022BitstreamEncoding_SP80022_BITSTREAM_ENCODING_ASCII_BITS, " \n", "cannot be empty"},
+		{"too short when decoded", pb.Sp80022BitstreamEncoding_SP80022_BITSTREAM_ENCODING_HEX, hex.EncodeToString(raw[:nist.MinBits/16]), "insufficient bits"},
+		{"unknown encoding", pb.Sp80022BitstreamEncoding(99), "00", "unsupported encoding"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.validateRequest(&pb.Sp80022TestRequest{Bitstream: []byte(tt.input), Encoding: tt.encoding})
+			if status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), tt.detail) {
+				t.Fatalf("expected InvalidArgument with %q, got %v", tt.detail, err)
+			}
+		})
+	}
+}
+
 func TestCalculatePValueUniformity(t *testing.T) {
 	values := []float64{0.1, 0.3, 0.5, 0.7, 0.9}
 	chi2 := calculatePValueUniformity(values)
@@ -60,6 +178,20 @@ func TestRunTestSuiteSuccessAndFailure(t *testing.T) {
 	if resp.ExecutionTimeMs <= 0 || time.Since(start) < 0 {
 		t.Fatalf("invalid execution time ms: %d", resp.ExecutionTimeMs)
 	}
+	for _, r := range resp.Results {
+		if r.Name == "non_overlapping_template" && len(r.SubResults) != 148 {
+			t.Fatalf("expected 148 template sub-results, got %d", len(r.SubResults))
+		}
+		if r.Name == "serial" && (len(r.SubResults) != 2 || r.SubResults[1].Name != "p_value2" || len(r.SubResults[1].Values) != 3) {
+			t.Fatalf("expected both serial p-values with psi^2 values, got %+v", r.SubResults)
+		}
+		if r.Name == "cumulative_sums" && (len(r.SubResults) != 2 || r.SubResults[0].Name != "forward" || r.SubResults[0].Statistic <= 0) {
+			t.Fatalf("expected forward and reverse cusum sub-results, got %+v", r.SubResults)
+		}
+		if r.Name == "linear_complexity" && (len(r.SubResults) != 1 || len(r.SubResults[0].Counts) != 7 || len(r.SubResults[0].Values) != nist.MinBits/500) {
+			t.Fatalf("expected the nu histogram and L_i of every block, got %d sub-results", len(r.SubResults))
+		}
+	}
 }
 
 func TestValidateRequestEdgeCases(t *testing.T) {
@@ -67,7 +199,7 @@ func TestValidateRequestEdgeCases(t *testing.T) {
 
 	// Empty bitstream
 	empty := &pb.Sp80022TestRequest{Bitstream: []byte{}}
-	if err := s.validateRequest(empty); err == nil {
+	if _, err := s.validateRequest(empty); err == nil {
 		t.Error("expected error for empty bitstream")
 	}
 
@@ -76,7 +208,7 @@ func TestValidateRequestEdgeCases(t *testing.T) {
 	// but we can mock or just trust the logic.
 	// Actually, nist.MaxBits is 10,000,000 bits = 1.25MB. That's fine to allocate.
 	huge := &pb.Sp80022TestRequest{Bitstream: make([]byte, nist.MaxBits/8+1)}
-	if err := s.validateRequest(huge); err == nil {
+	if _, err := s.validateRequest(huge); err == nil {
 		t.Error("expected error for exceeding max bits")
 	}
 }
@@ -105,16 +237,9 @@ func TestCalculatePValueUniformityEdgeCases(t *testing.T) {
 func TestRunTestSuiteCoverage(t *testing.T) {
 	s := NewServer()
 
-	// 1. Random data (should pass most tests) -> Covers Proportion > 0
+	// 1. Random data (should pass most tests)
 	// We need MinBits
-	n := nist.MinBits / 8
-	randomBits := make([]byte, n)
-	// Simple pseudo-random generation
-	state := uint64(12345)
-	for i := range randomBits {
-		state = state*6364136223846793005 + 1442695040888963407
-		randomBits[i] = byte(state >> 56)
-	}
+	randomBits := testrand.Bytes(nist.MinBits/8, 12345)
 
 	_, err := s.RunTestSuite(context.Background(), &pb.Sp80022TestRequest{Bitstream: randomBits})
 	if err != nil {
@@ -122,7 +247,7 @@ func TestRunTestSuiteCoverage(t *testing.T) {
 	}
 
 	// 2. All zeros (should fail and warn) -> Covers Warning != ""
-	zeros := make([]byte, n)
+	zeros := make([]byte, len(randomBits))
 	_, err = s.RunTestSuite(context.Background(), &pb.Sp80022TestRequest{Bitstream: zeros})
 	if err != nil {
 		t.Fatalf("RunTestSuite with zeros failed: %v", err)
@@ -135,7 +260,7 @@ func TestRunTestSuiteMocked(t *testing.T) {
 
 	s := NewServer()
 
-	runAllTests = func(bitstream []byte) ([]nist.TestResult, error) {
+	runAllTests = func(ctx context.Context, seq nist.BitSequence, cfg nist.SuiteConfig) ([]nist.TestResult, error) {
 		return nil, fmt.Errorf("mock error")
 	}
 	validBits := make([]byte, nist.MinBits/8)
@@ -144,10 +269,10 @@ func TestRunTestSuiteMocked(t *testing.T) {
 		t.Error("expected error from mocked RunAllTests")
 	}
 
-	runAllTests = func(bitstream []byte) ([]nist.TestResult, error) {
+	runAllTests = func(ctx context.Context, seq nist.BitSequence, cfg nist.SuiteConfig) ([]nist.TestResult, error) {
 		return []nist.TestResult{
-			{Name: "SkippedTest", PValue: -1.0, Passed: false},
-			{Name: "ValidTest", PValue: 0.5, Passed: true, Proportion: 1.0},
+			{Name: "SkippedTest", Status: nist.StatusNotApplicable, Warning: "insufficient bits"},
+			{Name: "ValidTest", PValue: 0.5, Passed: true, Status: nist.StatusPassed},
 		}, nil
 	}
 	resp, err := s.RunTestSuite(context.Background(), &pb.Sp80022TestRequest{Bitstream: validBits})
@@ -157,11 +282,316 @@ func TestRunTestSuiteMocked(t *testing.T) {
 	if len(resp.Results) != 2 {
 		t.Fatalf("expected 2 results, got %d", len(resp.Results))
 	}
-	if resp.Results[0].PValue >= 0 {
-		t.Error("expected negative p-value for skipped test")
+	if resp.Results[0].Status != pb.Sp80022TestStatus_SP80022_TEST_STATUS_NOT_APPLICABLE || resp.Results[0].Passed {
+		t.Errorf("expected not applicable skipped test, got %+v", resp.Results[0])
+	}
+	if resp.Results[1].Proportion != nil { //nolint:staticcheck // checks that the deprecated field stays unset
+		t.Errorf("expected no proportion for a single sequence, got %v", *resp.Results[1].Proportion) //nolint:staticcheck // see above
+	}
+	if resp.TestsRun != 1 || resp.TestsSkipped != 1 || resp.NistCompliant {
+		t.Errorf("unexpected counts: run=%d skipped=%d compliant=%v", resp.TestsRun, resp.TestsSkipped, resp.NistCompliant)
 	}
 
 	if resp.PValueUniformityChi2 != -1.0 {
 		t.Errorf("expected -1.0 for uniformity chi2, got %f", resp.PValueUniformityChi2)
 	}
 }
+
+func TestRunTestSuiteStatus(t *testing.T) {
+	orig := runAllTests
+	defer func() { runAllTests = orig }()
+
+	runAllTests = func(ctx context.Context, seq nist.BitSequence, cfg nist.SuiteConfig) ([]nist.TestResult, error) {
+		return []nist.TestResult{
+			{Name: "frequency_monobit", PValue: 0.5, Passed: true, Status: nist.StatusPassed},
+			{Name: "runs", PValue: 0, Passed: false, Status: nist.StatusFailed},
+			{Name: "random_excursions", Status: nist.StatusNotApplicable, Warning: "insufficient cycles"},
+			{Name: "linear_complexity", Status: nist.StatusError, Warning: "invalid block length"},
+			{Name: "random_excursions_variant", PValue: 0.4, Passed: true, Status: nist.StatusPassed, SubResults: []nist.SubResult{
+				{Name: "x=-9", State: -9, PValue: 0.4, Passed: true, Statistic: 0.8, Counts: []int{480}},
+			}},
+			{Name: "serial", PValue: 0.3, Passed: true, Status: nist.StatusPassed, SubResults: []nist.SubResult{
+				{Name: "p_value1", PValue: 0.6, Passed: true, Statistic: 1.6, Values: []float64{2.8, 1.2, 0.4}},
+				{Name: "p_value2", PValue: 0.3, Passed: true, Statistic: 0.8, Values: []float64{2.8, 1.2, 0.4}},
+			}},
+		}, nil
+	}
+
+	s := NewServer()
+	resp, err := s.RunTestSuite(context.Background(), &pb.Sp80022TestRequest{Bitstream: make([]byte, nist.MinBits/8)})
+	if err != nil {
+		t.Fatalf("RunTestSuite failed: %v", err)
+	}
+
+	if resp.OverallPassRate != 3.0/4.0 {
+		t.Errorf("expected pass rate 3/4 ignoring the skipped tests, got %f", resp.OverallPassRate)
+	}
+	if resp.TestsRun != 4 || resp.TestsSkipped != 2 || resp.TestsTotal != 6 || resp.NistCompliant {
+		t.Errorf("unexpected counts: run=%d skipped=%d total=%d compliant=%v",
+			resp.TestsRun, resp.TestsSkipped, resp.TestsTotal, resp.NistCompliant)
+	}
+	// A p-value of exactly 0 is a genuine failure, not a skipped test
+	if failed := resp.Results[1]; failed.Status != pb.Sp80022TestStatus_SP80022_TEST_STATUS_FAILED {
+		t.Errorf("unexpected failed result: %+v", failed)
+	}
+	if na := resp.Results[2]; na.Status != pb.Sp80022TestStatus_SP80022_TEST_STATUS_NOT_APPLICABLE || na.Passed || na.GetWarning() != "insufficient cycles" {
+		t.Errorf("unexpected not applicable result: %+v", na)
+	}
+	if e := resp.Results[3]; e.Status != pb.Sp80022TestStatus_SP80022_TEST_STATUS_ERROR || e.GetWarning() != "invalid block length" {
+		t.Errorf("unexpected error result: %+v", e)
+	}
+	sub := resp.Results[4].SubResults[0]
+	if sub.GetState() != -9 || sub.Name != "x=-9" || len(sub.Counts) != 1 || sub.Counts[0] != 480 {
+		t.Errorf("unexpected state sub-result: %+v", sub)
+	}
+	if sub := resp.Results[5].SubResults[1]; sub.Name != "p_value2" || sub.Statistic != 0.8 || len(sub.Values) != 3 || sub.Values[2] != 0.4 {
+		t.Errorf("unexpected serial sub-result: %+v", sub)
+	}
+}
+
+func TestRunTestSuiteConfig(t *testing.T) {
+	s := NewServer()
+	bits := make([]byte, nist.MinBits/8)
+
+	t.Run("defaults are echoed", func(t *testing.T) {
+		resp, err := s.RunTestSuite(context.Background(), &pb.Sp80022TestRequest{Bitstream: bits})
+		if err != nil {
+			t.Fatalf("RunTestSuite failed: %v", err)
+		}
+		cfg := resp.EffectiveConfig
+		if cfg.GetBlockFrequencyBlockLength() != 128 || cfg.GetSerialBlockLength() != 16 ||
+			cfg.GetLinearComplexitySequenceLength() != 500 || cfg.GetNonOverlappingTemplateBlockLength() != 9 ||
+			cfg.GetBinaryMatrixRows() != 32 || cfg.GetBinaryMatrixColumns() != 32 ||
+			cfg.GetBitOrder() != pb.Sp80022BitOrder_SP80022_BIT_ORDER_MSB_FIRST {
+			t.Fatalf("unexpected effective config: %+v", cfg)
+		}
+	})
+
+	t.Run("explicit parameters are honored", func(t *testing.T) {
+		var gotCfg nist.SuiteConfig
+		orig := runAllTests
+		defer func() { runAllTests = orig }()
+		runAllTests = func(ctx context.Context, seq nist.BitSequence, cfg nist.SuiteConfig) ([]nist.TestResult, error) {
+			gotCfg = cfg
+			return orig(ctx, seq, cfg)
+		}
+
+		resp, err := s.RunTestSuite(context.Background(), &pb.Sp80022TestRequest{
+			Bitstream: bits,
+			Config: &pb.Sp80022TestConfig{
+				BlockFrequencyBlockLength:      4000,
+				SerialBlockLength:              12,
+				LinearComplexitySequenceLength: 1000,
+				BinaryMatrixRows:               16,
+			},
+		})
+		if err != nil {
+			t.Fatalf("RunTestSuite failed: %v", err)
+		}
+		if gotCfg.BlockFrequencyBlockLength != 4000 || gotCfg.SerialBlockLength != 12 || gotCfg.LinearComplexityBlockLength != 1000 ||
+			gotCfg.BinaryMatrixRows != 16 {
+			t.Fatalf("config not passed through: %+v", gotCfg)
+		}
+		if resp.EffectiveConfig.GetBlockFrequencyBlockLength() != 4000 || resp.EffectiveConfig.GetApproximateEntropyBlockLength() != 10 ||
+			resp.EffectiveConfig.GetBinaryMatrixRows() != 16 || resp.EffectiveConfig.GetBinaryMatrixColumns() != 32 {
+			t.Fatalf("unexpected effective config: %+v", resp.EffectiveConfig)
+		}
+	})
+
+	t.Run("bit order is applied", func(t *testing.T) {
+		var first uint8
+		orig := runAllTests
+		defer func() { runAllTests = orig }()
+		runAllTests = func(ctx context.Context, seq nist.BitSequence, cfg nist.SuiteConfig) ([]nist.TestResult, error) {
+			first = seq.Bit(0)
+			return orig(ctx, seq, cfg)
+		}
+
+		lsb := append([]byte{0x01}, bits[1:]...)
+		resp, err := s.RunTestSuite(context.Background(), &pb.Sp80022TestRequest{
+			Bitstream: lsb,
+			Config:    &pb.Sp80022TestConfig{BitOrder: pb.Sp80022BitOrder_SP80022_BIT_ORDER_LSB_FIRST},
+		})
+		if err != nil {
+			t.Fatalf("RunTestSuite failed: %v", err)
+		}
+		if first != 1 {
+			t.Fatalf("expected the low-order bit of the first byte first")
+		}
+		if resp.EffectiveConfig.GetBitOrder() != pb.Sp80022BitOrder_SP80022_BIT_ORDER_LSB_FIRST {
+			t.Fatalf("unexpected effective config: %+v", resp.EffectiveConfig)
+		}
+
+		_, err = s.RunTestSuite(context.Background(), &pb.Sp80022TestRequest{
+			Bitstream: []byte(strings.Repeat("01", nist.MinBits/2)),
+			Encoding:  pb.Sp80022BitstreamEncoding_SP80022_BITSTREAM_ENCODING_ASCII_BITS,
+			Config:    &pb.Sp80022TestConfig{BitOrder: pb.Sp80022BitOrder_SP80022_BIT_ORDER_LSB_FIRST},
+		})
+		if status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), "ASCII_BITS") {
+			t.Fatalf("expected InvalidArgument for LSB-first ASCII bits, got %v", err)
+		}
+	})
+
+	t.Run("out of range parameters are rejected", func(t *testing.T) {
+		cases := map[string]*pb.Sp80022TestConfig{
+			"block frequency too small":   {BlockFrequencyBlockLength: 10},
+			"block frequency below 0.01n": {BlockFrequencyBlockLength: 128},
+			"serial too large":            {SerialBlockLength: 16},
+			"approximate entropy large":   {ApproximateEntropyBlockLength: 13},
+			"linear complexity too small": {LinearComplexitySequenceLength: 100},
+			"unsupported template length": {NonOverlappingTemplateBlockLength: 22},
+			"too many template blocks":    {NonOverlappingTemplateNumBlocks: 200},
+			"matrix too small":            {BinaryMatrixRows: 1},
+			"too few matrices":            {BinaryMatrixRows: 64, BinaryMatrixColumns: 256},
+			"unknown bit order":           {BitOrder: pb.Sp80022BitOrder(7)},
+		}
+		for name, cfg := range cases {
+			_, err := s.RunTestSuite(context.Background(), &pb.Sp80022TestRequest{Bitstream: bits, Config: cfg})
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("%s: expected InvalidArgument, got %v", name, err)
+			}
+		}
+	})
+
+	t.Run("engine parameter errors map to InvalidArgument", func(t *testing.T) {
+		orig := runAllTests
+		defer func() { runAllTests = orig }()
+		runAllTests = func(ctx context.Context, seq nist.BitSequence, cfg nist.SuiteConfig) ([]nist.TestResult, error) {
+			return nil, fmt.Errorf("wrapped: %w", nist.ErrInvalidParameter)
+		}
+		_, err := s.RunTestSuite(context.Background(), &pb.Sp80022TestRequest{Bitstream: bits})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("expected InvalidArgument, got %v", err)
+		}
+	})
+}
+
+func TestServerOptions(t *testing.T) {
+	orig := runAllTests
+	defer func() { runAllTests = orig }()
+
+	var gotWorkers int
+	runAllTests = func(ctx context.Context, seq nist.BitSequence, cfg nist.SuiteConfig) ([]nist.TestResult, error) {
+		gotWorkers = cfg.Workers
+		return []nist.TestResult{{Name: "frequency_monobit", PValue: 0.5, Passed: true, Status: nist.StatusPassed}}, nil
+	}
+
+	s := NewServerWithOptions(Options{TestConcurrency: 3})
+	if _, err := s.RunTestSuite(context.Background(), &pb.Sp80022TestRequest{Bitstream: make([]byte, nist.MinBits/8)}); err != nil {
+		t.Fatalf("RunTestSuite failed: %v", err)
+	}
+	if gotWorkers != 3 {
+		t.Fatalf("expected 3 workers, got %d", gotWorkers)
+	}
+}
+
+func TestServerMaxBits(t *testing.T) {
+	orig := runAllTests
+	defer func() { runAllTests = orig }()
+
+	var gotMaxBits int
+	runAllTests = func(ctx context.Context, seq nist.BitSequence, cfg nist.SuiteConfig) ([]nist.TestResult, error) {
+		gotMaxBits = cfg.MaxBits
+		return []nist.TestResult{{Name: "frequency_monobit", PValue: 0.5, Passed: true, Status: nist.StatusPassed}}, nil
+	}
+
+	// Raised limit: a bitstream beyond nist.MaxBits is accepted and the limit reaches the engine
+	raised := NewServerWithOptions(Options{MaxBits: 2 * nist.MaxBits})
+	if _, err := raised.RunTestSuite(context.Background(), &pb.Sp80022TestRequest{Bitstream: make([]byte, nist.MaxBits/8+1)}); err != nil {
+		t.Fatalf("RunTestSuite failed: %v", err)
+	}
+	if gotMaxBits != 2*nist.MaxBits {
+		t.Fatalf("expected engine limit %d, got %d", 2*nist.MaxBits, gotMaxBits)
+	}
+
+	// Lowered limit: the error reports the configured maximum
+	limit := 2 * nist.MinBits
+	lowered := NewServerWithOptions(Options{MaxBits: limit})
+	_, err := lowered.validateRequest(&pb.Sp80022TestRequest{Bitstream: make([]byte, limit/8+1)})
+	if status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), fmt.Sprintf("maximum %d", limit)) {
+		t.Fatalf("expected InvalidArgument naming the configured limit, got %v", err)
+	}
+
+	_, err = lowered.validateMultiSequenceRequest(&pb.Sp80022MultiSequenceRequest{
+		Bitstream:          make([]byte, limit/4+1),
+		SequenceLengthBits: int32(limit + 8),
+	})
+	if status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), fmt.Sprintf("maximum %d", limit)) {
+		t.Fatalf("expected InvalidArgument naming the configured limit, got %v", err)
+	}
+}
+
+func TestRunTestSuiteCancellation(t *testing.T) {
+	bits := make([]byte, nist.MinBits/8)
+
+	t.Run("canceled client context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := NewServer().RunTestSuite(ctx, &pb.Sp80022TestRequest{Bitstream: bits})
+		if status.Code(err) != codes.Canceled {
+			t.Fatalf("expected Canceled, got %v", err)
+		}
+	})
+
+	t.Run("server max execution time", func(t *testing.T) {
+		s := NewServerWithOptions(Options{MaxExecutionTime: time.Nanosecond})
+		_, err := s.RunTestSuite(context.Background(), &pb.Sp80022TestRequest{Bitstream: bits})
+		if status.Code(err) != codes.DeadlineExceeded {
+			t.Fatalf("expected DeadlineExceeded, got %v", err)
+		}
+		_, err = s.RunMultiSequenceAnalysis(context.Background(), &pb.Sp80022MultiSequenceRequest{
+			Bitstream:          bits,
+			SequenceLengthBits: nist.MinBits,
+		})
+		if status.Code(err) != codes.DeadlineExceeded {
+			t.Fatalf("expected DeadlineExceeded from multi-sequence analysis, got %v", err)
+		}
+	})
+
+	t.Run("engine receives the deadline", func(t *testing.T) {
+		orig := runAllTests
+		defer func() { runAllTests = orig }()
+
+		var hasDeadline bool
+		runAllTests = func(ctx context.Context, seq nist.BitSequence, cfg nist.SuiteConfig) ([]nist.TestResult, error) {
+			_, hasDeadline = ctx.Deadline()
+			return nil, nil
+		}
+		s := NewServerWithOptions(Options{MaxExecutionTime: time.Minute})
+		if _, err := s.RunTestSuite(context.Background(), &pb.Sp80022TestRequest{Bitstream: bits}); err != nil {
+			t.Fatalf("RunTestSuite failed: %v", err)
+		}
+		if !hasDeadline {
+			t.Fatal("expected the engine context to carry the max execution time")
+		}
+	})
+}
+
+func TestRunTestSuiteTiming(t *testing.T) {
+	orig := runAllTests
+	defer func() { runAllTests = orig }()
+
+	runAllTests = func(ctx context.Context, seq nist.BitSequence, cfg nist.SuiteConfig) ([]nist.TestResult, error) {
+		return []nist.TestResult{
+			{Name: "timing_probe_passed", PValue: 0.5, Passed: true, Status: nist.StatusPassed, Duration: 1500 * time.Microsecond},
+			{Name: "timing_probe_not_applicable", Status: nist.StatusNotApplicable, Warning: "pi estimator", Duration: 250 * time.Microsecond},
+		}, nil
+	}
+
+	before := testutil.CollectAndCount(metrics.TestDuration)
+	resp, err := NewServer().RunTestSuite(context.Background(), &pb.Sp80022TestRequest{Bitstream: make([]byte, nist.MinBits/8)})
+	if err != nil {
+		t.Fatalf("RunTestSuite failed: %v", err)
+	}
+
+	if got := resp.Results[0].ExecutionTimeMs; got != 1.5 {
+		t.Errorf("expected 1.5 ms, got %f", got)
+	}
+	if got := resp.Results[1].ExecutionTimeMs; got != 0.25 {
+		t.Errorf("expected 0.25 ms for a not applicable test, got %f", got)
+	}
+	// Every test, including skipped ones, gets its own nist_test_duration_seconds series
+	if after := testutil.CollectAndCount(metrics.TestDuration); after != before+2 {
+		t.Errorf("expected 2 new duration histogram series, got %d (was %d)", after, before)
+	}
+}
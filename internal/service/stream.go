@@ -0,0 +1,124 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/AmmannChristian/nist-sp800-22-rev1a/pkg/pb"
+
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/metrics"
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/nist"
+)
+
+// RunTestSuiteStream implements the RunTestSuiteStream RPC
+func (s *Server) RunTestSuiteStream(stream pb.Sp80022TestService_RunTestSuiteStreamServer) error {
+	startTime := time.Now()
+
+	// Generate unique request ID for log correlation
+	requestID := uuid.New().String()
+
+	maxBits := s.streamMaxBits()
+
+	// Assemble and validate the bitstream
+	var seq nist.BitSequence
+	bitstream, header, err := receiveBitstream(stream, maxBits)
+	if err == nil {
+		seq, err = validateBitstream(bitstream, len(bitstream)*8, header.BitLength, header.Config, maxBits)
+	}
+	if err != nil {
+		log.Error().
+			Str("request_id", requestID).
+			Err(err).
+			Msg("Request validation failed")
+		metrics.RequestsTotal.WithLabelValues("RunTestSuiteStream", "error").Inc()
+		return err
+	}
+
+	log.Info().
+		Str("request_id", requestID).
+		Int("bitstream_bytes", len(bitstream)).
+		Msg("RunTestSuiteStream request received")
+
+	metrics.RequestsTotal.WithLabelValues("RunTestSuiteStream", "success").Inc()
+
+	cfg := s.suiteConfig(header.Config)
+	cfg.MaxBits = maxBits
+
+	response, err := s.runTestSuite(stream.Context(), requestID, seq, cfg, startTime)
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(response)
+}
+
+// streamMaxBits returns the effective bitstream size limit of RunTestSuiteStream.
+func (s *Server) streamMaxBits() int {
+	if s.opts.StreamMaxBits > 0 {
+		return s.opts.StreamMaxBits
+	}
+	return nist.MaxBits
+}
+
+// receiveBitstream reads the header of a RunTestSuiteStream call and concatenates the
+// chunks that follow, rejecting the stream as soon as it exceeds maxBits.
+func receiveBitstream(stream pb.Sp80022TestService_RunTestSuiteStreamServer, maxBits int) ([]byte, *pb.Sp80022TestStreamHeader, error) {
+	first, err := stream.Recv()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, status.Error(codes.InvalidArgument, "empty stream: expected a header")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := first.GetHeader()
+	if header == nil {
+		return nil, nil, status.Error(codes.InvalidArgument, "first message must be a header")
+	}
+
+	maxBytes := maxBits / 8
+	total := header.TotalBytes
+	if total < 0 {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "invalid total_bytes: %d", total)
+	}
+	if total > int64(maxBytes) {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "too many bits: got %d, maximum %d (%d bytes)",
+			total*8, maxBits, maxBytes)
+	}
+
+	bitstream := make([]byte, 0, total)
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch p := msg.Payload.(type) {
+		case *pb.Sp80022TestStreamRequest_Chunk:
+			if len(bitstream)+len(p.Chunk) > maxBytes {
+				return nil, nil, status.Errorf(codes.InvalidArgument, "too many bits: stream exceeds maximum %d (%d bytes)",
+					maxBits, maxBytes)
+			}
+			bitstream = append(bitstream, p.Chunk...)
+		case *pb.Sp80022TestStreamRequest_Header:
+			return nil, nil, status.Error(codes.InvalidArgument, "header must only be sent once")
+		default:
+			return nil, nil, status.Error(codes.InvalidArgument, "message has no payload")
+		}
+	}
+
+	if total > 0 && int64(len(bitstream)) != total {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "incomplete stream: got %d bytes, header announced %d",
+			len(bitstream), total)
+	}
+
+	return bitstream, header, nil
+}
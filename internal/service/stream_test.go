@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/nist"
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/testrand"
+	pb "github.com/AmmannChristian/nist-sp800-22-rev1a/pkg/pb"
+)
+
+// mockTestStream is a client stream that replays msgs and records the response
+type mockTestStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	msgs     []*pb.Sp80022TestStreamRequest
+	recvErr  error
+	response *pb.Sp80022TestResponse
+}
+
+func (m *mockTestStream) Context() context.Context {
+	if m.ctx != nil {
+		return m.ctx
+	}
+	return context.Background()
+}
+
+func (m *mockTestStream) Recv() (*pb.Sp80022TestStreamRequest, error) {
+	if len(m.msgs) == 0 {
+		if m.recvErr != nil {
+			return nil, m.recvErr
+		}
+		return nil, io.EOF
+	}
+	msg := m.msgs[0]
+	m.msgs = m.msgs[1:]
+	return msg, nil
+}
+
+func (m *mockTestStream) SendAndClose(resp *pb.Sp80022TestResponse) error {
+	m.response = resp
+	return nil
+}
+
+func streamHeader(config *pb.Sp80022TestConfig, totalBytes int64) *pb.Sp80022TestStreamRequest {
+	return &pb.Sp80022TestStreamRequest{Payload: &pb.Sp80022TestStreamRequest_Header{
+		Header: &pb.Sp80022TestStreamHeader{Config: config, TotalBytes: totalBytes},
+	}}
+}
+
+func streamChunk(chunk []byte) *pb.Sp80022TestStreamRequest {
+	return &pb.Sp80022TestStreamRequest{Payload: &pb.Sp80022TestStreamRequest_Chunk{Chunk: chunk}}
+}
+
+// chunked splits data into a stream of chunk messages of the given size
+func chunked(data []byte, size int) []*pb.Sp80022TestStreamRequest {
+	var msgs []*pb.Sp80022TestStreamRequest
+	for len(data) > 0 {
+		n := min(size, len(data))
+		msgs = append(msgs, streamChunk(data[:n]))
+		data = data[n:]
+	}
+	return msgs
+}
+
+func TestRunTestSuiteStream(t *testing.T) {
+	orig := runAllTests
+	defer func() { runAllTests = orig }()
+
+	t.Run("assembles chunks beyond the unary limit", func(t *testing.T) {
+		data := make([]byte, nist.MaxBits/8+1000)
+		for i := range data {
+			data[i] = byte(i * 7)
+		}
+
+		var got []byte
+		var gotCfg nist.SuiteConfig
+		runAllTests = func(ctx context.Context, seq nist.BitSequence, cfg nist.SuiteConfig) ([]nist.TestResult, error) {
+			got, gotCfg = seq.Bytes(), cfg
+			return []nist.TestResult{{Name: "t", PValue: 0.5, Passed: true, Status: nist.StatusPassed}}, nil
+		}
+
+		s := NewServerWithOptions(Options{StreamMaxBits: 2 * nist.MaxBits})
+		msgs := append([]*pb.Sp80022TestStreamRequest{streamHeader(&pb.Sp80022TestConfig{SerialBlockLength: 10}, int64(len(data)))},
+			chunked(data, 1<<20)...)
+		stream := &mockTestStream{msgs: msgs}
+		if err := s.RunTestSuiteStream(stream); err != nil {
+			t.Fatalf("RunTestSuiteStream failed: %v", err)
+		}
+
+		if string(got) != string(data) {
+			t.Fatalf("assembled bitstream differs from the sent data")
+		}
+		if gotCfg.MaxBits != 2*nist.MaxBits || gotCfg.SerialBlockLength != 10 {
+			t.Errorf("unexpected suite config: %+v", gotCfg)
+		}
+		if stream.response == nil || int(stream.response.SampleSizeBits) != len(data)*8 {
+			t.Fatalf("unexpected response: %+v", stream.response)
+		}
+		if stream.response.OverallPassRate != 1.0 || stream.response.EffectiveConfig.GetSerialBlockLength() != 10 {
+			t.Errorf("unexpected response summary: %+v", stream.response)
+		}
+	})
+
+	t.Run("runs the real engine", func(t *testing.T) {
+		runAllTests = orig
+		data := testrand.Bytes(nist.MinBits/8, 7)
+
+		stream := &mockTestStream{msgs: append([]*pb.Sp80022TestStreamRequest{streamHeader(nil, 0)}, chunked(data, 4096)...)}
+		if err := NewServer().RunTestSuiteStream(stream); err != nil {
+			t.Fatalf("RunTestSuiteStream failed: %v", err)
+		}
+		if len(stream.response.Results) != 15 {
+			t.Fatalf("expected 15 results, got %d", len(stream.response.Results))
+		}
+	})
+
+	t.Run("engine errors are mapped", func(t *testing.T) {
+		runAllTests = func(ctx context.Context, seq nist.BitSequence, cfg nist.SuiteConfig) ([]nist.TestResult, error) {
+			return nil, context.Canceled
+		}
+		stream := &mockTestStream{msgs: []*pb.Sp80022TestStreamRequest{streamHeader(nil, 0), streamChunk(make([]byte, nist.MinBits/8))}}
+		err := NewServer().RunTestSuiteStream(stream)
+		if status.Code(err) != codes.Canceled {
+			t.Fatalf("expected Canceled, got %v", err)
+		}
+		if stream.response != nil {
+			t.Error("no response expected on error")
+		}
+	})
+}
+
+func TestRunTestSuiteStreamValidation(t *testing.T) {
+	orig := runAllTests
+	defer func() { runAllTests = orig }()
+	runAllTests = func(ctx context.Context, seq nist.BitSequence, cfg nist.SuiteConfig) ([]nist.TestResult, error) {
+		t.Fatal("engine must not run for an invalid stream")
+		return nil, nil
+	}
+
+	const maxBits = nist.MinBits * 2
+	valid := make([]byte, nist.MinBits/8)
+
+	tests := []struct {
+		name string
+		msgs []*pb.Sp80022TestStreamRequest
+	}{
+		{"empty stream", nil},
+		{"missing header", []*pb.Sp80022TestStreamRequest{streamChunk(valid)}},
+		{"duplicate header", []*pb.Sp80022TestStreamRequest{streamHeader(nil, 0), streamHeader(nil, 0)}},
+		{"no payload", []*pb.Sp80022TestStreamRequest{streamHeader(nil, 0), {}}},
+		{"negative total", []*pb.Sp80022TestStreamRequest{streamHeader(nil, -1)}},
+		{"announced total too large", []*pb.Sp80022TestStreamRequest{streamHeader(nil, maxBits/8+1)}},
+		{"stream too large", append([]*pb.Sp80022TestStreamRequest{streamHeader(nil, 0)}, chunked(make([]byte, maxBits/8+1), 1000)...)},
+		{"total mismatch", []*pb.Sp80022TestStreamRequest{streamHeader(nil, int64(len(valid))+1), streamChunk(valid)}},
+		{"insufficient bits", []*pb.Sp80022TestStreamRequest{streamHeader(nil, 0), streamChunk(make([]byte, 10))}},
+		{"header only", []*pb.Sp80022TestStreamRequest{streamHeader(nil, 0)}},
+		{"invalid config", []*pb.Sp80022TestStreamRequest{streamHeader(&pb.Sp80022TestConfig{SerialBlockLength: 1}, 0), streamChunk(valid)}},
+		{"invalid bit length", []*pb.Sp80022TestStreamRequest{{Payload: &pb.Sp80022TestStreamRequest_Header{
+			Header: &pb.Sp80022TestStreamHeader{BitLength: proto.Int64(int64(len(valid))*8 + 1)},
+		}}, streamChunk(valid)}},
+	}
+
+	s := NewServerWithOptions(Options{StreamMaxBits: maxBits})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.RunTestSuiteStream(&mockTestStream{msgs: tt.msgs})
+			if status.Code(err) != codes.InvalidArgument {
+				t.Fatalf("expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+
+	t.Run("receive error", func(t *testing.T) {
+		recvErr := status.Error(codes.Unavailable, "connection lost")
+		err := s.RunTestSuiteStream(&mockTestStream{msgs: []*pb.Sp80022TestStreamRequest{streamHeader(nil, 0)}, recvErr: recvErr})
+		if !errors.Is(err, recvErr) {
+			t.Fatalf("expected the receive error, got %v", err)
+		}
+	})
+
+	t.Run("default limit", func(t *testing.T) {
+		if got := NewServer().streamMaxBits(); got != nist.MaxBits {
+			t.Errorf("expected default stream limit %d, got %d", nist.MaxBits, got)
+		}
+	})
+}
@@ -0,0 +1,167 @@
+package sts
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/nist"
+)
+
+// FinalAnalysisReportFile is the name of the summary file in the experiment directory.
+const FinalAnalysisReportFile = "finalAnalysisReport.txt"
+
+// testFiles holds the open results.txt and stats.txt of one test.
+type testFiles struct {
+	results, stats       *os.File
+	resultsBuf, statsBuf *bufio.Writer
+}
+
+// ExperimentWriter writes results in the directory layout of the STS
+// experiments/AlgorithmTesting directory: per test a <Test>/results.txt with one p-value
+// per line and sequence, a <Test>/stats.txt with the outcome of every sequence, and a
+// finalAnalysisReport.txt. As in the STS, a test that is not applicable to a sequence
+// contributes p-values of 0.
+//
+// results.txt and finalAnalysisReport.txt follow the STS formats. stats.txt only has
+// the STS p-value lines of every sequence, without the computational information the
+// STS writes before them, so tools that read more than the p-values from it cannot
+// consume it.
+type ExperimentWriter struct {
+	dir   string
+	files map[string]*testFiles
+}
+
+// NewExperimentWriter creates dir (the equivalent of experiments/AlgorithmTesting) and a
+// subdirectory for every test, truncating existing results.
+func NewExperimentWriter(dir string) (*ExperimentWriter, error) {
+	w := &ExperimentWriter{dir: dir, files: make(map[string]*testFiles, len(stsTests))}
+	for test, t := range stsTests {
+		testDir := filepath.Join(dir, t.name)
+		if err := os.MkdirAll(testDir, 0o750); err != nil {
+			w.Close()
+			return nil, err
+		}
+		results, err := os.Create(filepath.Join(testDir, "results.txt")) //nolint:gosec // path below the user-selected directory
+		if err != nil {
+			w.Close()
+			return nil, err
+		}
+		stats, err := os.Create(filepath.Join(testDir, "stats.txt")) //nolint:gosec // path below the user-selected directory
+		if err != nil {
+			results.Close()
+			w.Close()
+			return nil, err
+		}
+		w.files[test] = &testFiles{results: results, stats: stats, resultsBuf: bufio.NewWriter(results), statsBuf: bufio.NewWriter(stats)}
+	}
+	return w, nil
+}
+
+// AddSequence appends the results of the next sequence. Its signature matches
+// nist.SequenceFunc, so it can be passed to nist.RunMultiSequenceFunc.
+func (w *ExperimentWriter) AddSequence(index int, results []nist.TestResult) error {
+	for _, r := range results {
+		f, ok := w.files[r.Name]
+		if !ok {
+			return fmt.Errorf("unknown test %q", r.Name)
+		}
+		t := stsTests[r.Name]
+
+		fmt.Fprintf(f.statsBuf, "\t\t\t%s (sequence %d)\n", t.title, index+1)
+		f.statsBuf.WriteString("\t\t---------------------------------------------\n")
+
+		switch {
+		case !r.Status.Completed():
+			// The STS writes zero p-values when a test does not apply
+			for range max(t.states, len(r.SubResults)) {
+				f.resultsBuf.WriteString("0.000000\n")
+			}
+			fmt.Fprintf(f.statsBuf, "\t\tWARNING: TEST NOT APPLICABLE: %s\n\n", r.Warning)
+		case r.MultiValued():
+			for _, sub := range r.SubResults {
+				fmt.Fprintf(f.resultsBuf, "%f\n", sub.PValue)
+			}
+			writeSubResultStats(f.statsBuf, r)
+			f.statsBuf.WriteString("\n")
+		default:
+			fmt.Fprintf(f.resultsBuf, "%f\n", r.PValue)
+			fmt.Fprintf(f.statsBuf, "%s\t\tp_value = %f\n\n", verdict(r.Passed), r.PValue)
+		}
+	}
+	return nil
+}
+
+// writeSubResultStats writes the p-value lines of a multi-valued test in the form of
+// the STS stats.txt, which names the statistic of each line.
+func writeSubResultStats(w *bufio.Writer, r nist.TestResult) {
+	if r.Name == "non_overlapping_template" && len(r.SubResults) > 0 {
+		w.WriteString("\t\tTemplate  ")
+		for j := range r.SubResults[0].Counts {
+			fmt.Fprintf(w, " W_%-2d", j+1)
+		}
+		w.WriteString("    Chi^2   P_value Assignment Index\n")
+	}
+	for i, sub := range r.SubResults {
+		switch r.Name {
+		case "cumulative_sums":
+			fmt.Fprintf(w, "\t\t      CUMULATIVE SUMS (%s) TEST\n", strings.ToUpper(sub.Name))
+			fmt.Fprintf(w, "%s\t\tp_value = %f\n", verdict(sub.Passed), sub.PValue)
+		case "non_overlapping_template":
+			fmt.Fprintf(w, "%s ", sub.Name)
+			for _, c := range sub.Counts {
+				fmt.Fprintf(w, "%4d ", c)
+			}
+			fmt.Fprintf(w, "%9.6f %f %s %4d\n", sub.Statistic, sub.PValue, verdict(sub.Passed), i)
+		case "random_excursions":
+			fmt.Fprintf(w, "%s\t\tx = %2d chi^2 = %9.6f p_value = %f\n", verdict(sub.Passed), sub.State, sub.Statistic, sub.PValue)
+		case "random_excursions_variant":
+			visits := 0
+			if len(sub.Counts) > 0 {
+				visits = sub.Counts[0]
+			}
+			fmt.Fprintf(w, "%s\t\t(x = %2d) Total visits = %4d; p-value = %f\n", verdict(sub.Passed), sub.State, visits, sub.PValue)
+		default:
+			// The Serial test names its p-values p_value1 and p_value2
+			fmt.Fprintf(w, "%s\t\t%s = %f\n", verdict(sub.Passed), sub.Name, sub.PValue)
+		}
+	}
+}
+
+func verdict(passed bool) string {
+	if passed {
+		return "SUCCESS"
+	}
+	return "FAILURE"
+}
+
+// Finish writes finalAnalysisReport.txt for report and closes the per-test files.
+func (w *ExperimentWriter) Finish(generator string, report *nist.MultiSequenceReport) error {
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	f, err := os.Create(filepath.Join(w.dir, FinalAnalysisReportFile)) //nolint:gosec // path below the user-selected directory
+	if err != nil {
+		return err
+	}
+	if err := WriteFinalAnalysisReport(f, generator, report); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Close flushes and closes the per-test files without writing the final report.
+// It is safe to call more than once.
+func (w *ExperimentWriter) Close() error {
+	var errs []error
+	for test, f := range w.files {
+		errs = append(errs, f.resultsBuf.Flush(), f.statsBuf.Flush(), f.results.Close(), f.stats.Close())
+		delete(w.files, test)
+	}
+	return errors.Join(errs...)
+}
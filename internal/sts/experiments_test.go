@@ -0,0 +1,111 @@
+package sts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/nist"
+)
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path) //nolint:gosec // test file below t.TempDir
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestExperimentWriter(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "experiments", "AlgorithmTesting")
+	w, err := NewExperimentWriter(dir)
+	if err != nil {
+		t.Fatalf("NewExperimentWriter failed: %v", err)
+	}
+
+	sequences := [][]nist.TestResult{
+		{
+			{Name: "frequency_monobit", PValue: 0.5, Passed: true, Status: nist.StatusPassed},
+			{Name: "random_excursions", Status: nist.StatusNotApplicable, Warning: "insufficient cycles"},
+			{Name: "non_overlapping_template", PValue: 0.004, Status: nist.StatusFailed, SubResults: []nist.SubResult{
+				{Name: "000000001", PValue: 0.25, Passed: true, Statistic: 2.5, Counts: []int{239, 235}},
+				{Name: "000000011", PValue: 0.004, Statistic: 11, Counts: []int{1002, 235}},
+			}},
+			{Name: "cumulative_sums", PValue: 0.8, Passed: true, Status: nist.StatusPassed, SubResults: []nist.SubResult{
+				{Name: "forward", PValue: 0.669887, Passed: true},
+				{Name: "reverse", PValue: 0.724266, Passed: true},
+			}},
+		},
+		{
+			{Name: "frequency_monobit", PValue: 0.001, Status: nist.StatusFailed},
+			{Name: "serial", Status: nist.StatusNotApplicable, Warning: "empty bitstream"},
+		},
+	}
+	for i, results := range sequences {
+		if err := w.AddSequence(i, results); err != nil {
+			t.Fatalf("AddSequence failed: %v", err)
+		}
+	}
+	report := &nist.MultiSequenceReport{NumSequences: 2, Results: []nist.SequenceAnalysis{{Name: "frequency_monobit", SampleSize: 2, PassedSequences: 1}}}
+	if err := w.Finish("data.bin", report); err != nil {
+		t.Fatalf("Finish failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("second Close failed: %v", err)
+	}
+
+	tests := map[string]string{
+		"Frequency/results.txt":              "0.500000\n0.001000\n",
+		"RandomExcursions/results.txt":       strings.Repeat("0.000000\n", 8),
+		"NonOverlappingTemplate/results.txt": "0.250000\n0.004000\n",
+		"Serial/results.txt":                 "0.000000\n0.000000\n",
+		"CumulativeSums/results.txt":         "0.669887\n0.724266\n",
+	}
+	for file, want := range tests {
+		if got := readFile(t, filepath.Join(dir, file)); got != want {
+			t.Errorf("%s: expected %q, got %q", file, want, got)
+		}
+	}
+
+	stats := readFile(t, filepath.Join(dir, "Frequency", "stats.txt"))
+	for _, want := range []string{"FREQUENCY TEST (sequence 1)", "SUCCESS\t\tp_value = 0.500000\n", "FAILURE\t\tp_value = 0.001000\n"} {
+		if !strings.Contains(stats, want) {
+			t.Errorf("Frequency/stats.txt lacks %q:\n%s", want, stats)
+		}
+	}
+	if stats := readFile(t, filepath.Join(dir, "RandomExcursions", "stats.txt")); !strings.Contains(stats, "NOT APPLICABLE: insufficient cycles") {
+		t.Errorf("unexpected RandomExcursions/stats.txt:\n%s", stats)
+	}
+	// Multi-valued tests name their p-values like the STS
+	if stats := readFile(t, filepath.Join(dir, "NonOverlappingTemplate", "stats.txt")); !strings.Contains(stats, "000000011 1002  235 11.000000 0.004000 FAILURE    1\n") {
+		t.Errorf("unexpected NonOverlappingTemplate/stats.txt:\n%s", stats)
+	}
+	if stats := readFile(t, filepath.Join(dir, "CumulativeSums", "stats.txt")); !strings.Contains(stats, "CUMULATIVE SUMS (REVERSE) TEST\nSUCCESS\t\tp_value = 0.724266\n") {
+		t.Errorf("unexpected CumulativeSums/stats.txt:\n%s", stats)
+	}
+	if final := readFile(t, filepath.Join(dir, FinalAnalysisReportFile)); !strings.Contains(final, "generator is <data.bin>") || !strings.Contains(final, "1/2    *  Frequency") {
+		t.Errorf("unexpected final report:\n%s", final)
+	}
+}
+
+func TestExperimentWriterErrors(t *testing.T) {
+	// A file in place of the directory
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewExperimentWriter(file); err == nil {
+		t.Error("expected error when the directory cannot be created")
+	}
+
+	w, err := NewExperimentWriter(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewExperimentWriter failed: %v", err)
+	}
+	defer w.Close()
+	if err := w.AddSequence(0, []nist.TestResult{{Name: "unknown"}}); err == nil || !strings.Contains(err.Error(), "unknown") {
+		t.Errorf("expected error for an unknown test, got %v", err)
+	}
+}
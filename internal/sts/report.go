@@ -0,0 +1,133 @@
+// Package sts writes test results in the file formats of the NIST STS reference
+// implementation (sts-2.1.2), so that tooling which parses its output can consume them.
+package sts
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/nist"
+)
+
+// MinUniformitySampleSize is the number of sequences SP 800-22 section 4.2.2 requires
+// for a meaningful uniformity P-value_T; the STS prints "----" for smaller samples.
+const MinUniformitySampleSize = 55
+
+// stsTest describes how the STS names a test and titles its stats.txt.
+type stsTest struct {
+	name  string
+	title string
+	// states is the number of p-values the STS reports per sequence for a multi-valued
+	// test that is not applicable, e.g. 8 for the random excursion states.
+	states int
+}
+
+// stsTests maps the suite's test names to their STS counterparts.
+var stsTests = map[string]stsTest{
+	"frequency_monobit":          {"Frequency", "FREQUENCY TEST", 1},
+	"block_frequency":            {"BlockFrequency", "BLOCK FREQUENCY TEST", 1},
+	"cumulative_sums":            {"CumulativeSums", "CUMULATIVE SUMS (FORWARD/REVERSE) TEST", 2},
+	"runs":                       {"Runs", "RUNS TEST", 1},
+	"longest_run":                {"LongestRun", "LONGEST RUNS OF ONES TEST", 1},
+	"binary_matrix_rank":         {"Rank", "RANK TEST", 1},
+	"discrete_fourier_transform": {"FFT", "FFT TEST", 1},
+	"non_overlapping_template":   {"NonOverlappingTemplate", "NONPERIODIC TEMPLATES TEST", 1},
+	"overlapping_template":       {"OverlappingTemplate", "OVERLAPPING TEMPLATE OF ALL ONES TEST", 1},
+	"universal_statistical":      {"Universal", "UNIVERSAL STATISTICAL TEST", 1},
+	"approximate_entropy":        {"ApproximateEntropy", "APPROXIMATE ENTROPY TEST", 1},
+	"random_excursions":          {"RandomExcursions", "RANDOM EXCURSIONS TEST", 8},
+	"random_excursions_variant":  {"RandomExcursionsVariant", "RANDOM EXCURSIONS VARIANT TEST", 18},
+	"serial":                     {"Serial", "SERIAL TEST", 2},
+	"linear_complexity":          {"LinearComplexity", "LINEAR COMPLEXITY TEST", 1},
+}
+
+// TestName returns the STS name of a suite test, e.g. "Rank" for "binary_matrix_rank".
+// A "/<sub-result>" suffix as used by nist.SequenceAnalysis is dropped, as the STS
+// repeats the test name for every statistic of a multi-valued test.
+func TestName(name string) string {
+	test, _, _ := strings.Cut(name, "/")
+	if t, ok := stsTests[test]; ok {
+		return t.name
+	}
+	return test
+}
+
+const (
+	reportRule  = "------------------------------------------------------------------------------\n"
+	summaryRule = "- - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -\n"
+)
+
+// WriteFinalAnalysisReport writes report in the format of the STS finalAnalysisReport.txt:
+// one line per statistic with the C1..C10 histogram, the uniformity P-value_T and the
+// proportion of passing sequences, each marked with "*" if it fails. generator names the
+// input in the header, like the STS stream file name.
+func WriteFinalAnalysisReport(w io.Writer, generator string, report *nist.MultiSequenceReport) error {
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString(reportRule)
+	bw.WriteString("RESULTS FOR THE UNIFORMITY OF P-VALUES AND THE PROPORTION OF PASSING SEQUENCES\n")
+	bw.WriteString(reportRule)
+	fmt.Fprintf(bw, "   generator is <%s>\n", generator)
+	bw.WriteString(reportRule)
+	bw.WriteString(" C1  C2  C3  C4  C5  C6  C7  C8  C9 C10  P-VALUE  PROPORTION  STATISTICAL TEST\n")
+	bw.WriteString(reportRule)
+
+	generalSize, excursionSize := 0, 0
+	for _, a := range report.Results {
+		for _, c := range a.Histogram {
+			fmt.Fprintf(bw, "%3d ", c)
+		}
+
+		switch {
+		case a.SampleSize < MinUniformitySampleSize:
+			bw.WriteString("    ----    ")
+		case !a.UniformityPassed:
+			fmt.Fprintf(bw, " %8.6f * ", a.PValueT)
+		default:
+			fmt.Fprintf(bw, " %8.6f   ", a.PValueT)
+		}
+
+		name := TestName(a.Name)
+		switch {
+		case a.SampleSize == 0:
+			fmt.Fprintf(bw, "    ------     %s\n", name)
+		case !a.ProportionPassed:
+			fmt.Fprintf(bw, "%4d/%-4d *  %s\n", a.PassedSequences, a.SampleSize, name)
+		default:
+			fmt.Fprintf(bw, "%4d/%-4d    %s\n", a.PassedSequences, a.SampleSize, name)
+		}
+
+		if strings.HasPrefix(name, "RandomExcursions") {
+			excursionSize = max(excursionSize, a.SampleSize)
+		} else {
+			generalSize = max(generalSize, a.SampleSize)
+		}
+	}
+
+	bw.WriteString("\n\n" + summaryRule)
+	bw.WriteString("The minimum pass rate for each statistical test with the exception of the\n")
+	fmt.Fprintf(bw, "random excursion (variant) test is approximately = %d for a\n", MinimumPassCount(generalSize))
+	fmt.Fprintf(bw, "sample size = %d binary sequences.\n\n", generalSize)
+	bw.WriteString("The minimum pass rate for the random excursion (variant) test\n")
+	fmt.Fprintf(bw, "is approximately = %d for a sample size = %d binary sequences.\n\n", MinimumPassCount(excursionSize), excursionSize)
+	bw.WriteString("For further guidelines construct a probability table using the MAPLE program\n")
+	bw.WriteString("provided in the addendum section of the documentation.\n")
+	bw.WriteString(summaryRule)
+
+	return bw.Flush()
+}
+
+// MinimumPassCount returns the smallest number of passing sequences out of sampleSize
+// within the confidence interval (1-Alpha) - 3*sqrt(Alpha*(1-Alpha)/sampleSize), truncated
+// as in the STS summary.
+func MinimumPassCount(sampleSize int) int {
+	if sampleSize == 0 {
+		return 0
+	}
+	s := float64(sampleSize)
+	pHat := 1 - nist.Alpha
+	return int((pHat - 3*math.Sqrt(pHat*nist.Alpha/s)) * s)
+}
@@ -0,0 +1,90 @@
+package sts
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/nist"
+)
+
+func TestTestName(t *testing.T) {
+	tests := map[string]string{
+		"frequency_monobit":               "Frequency",
+		"binary_matrix_rank":              "Rank",
+		"non_overlapping_template/000001": "NonOverlappingTemplate",
+		"random_excursions_variant/x=-9":  "RandomExcursionsVariant",
+		"unknown":                         "unknown",
+	}
+	for name, want := range tests {
+		if got := TestName(name); got != want {
+			t.Errorf("TestName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestMinimumPassCount(t *testing.T) {
+	tests := map[int]int{0: 0, 1: 0, 100: 96, 1000: 980}
+	for s, want := range tests {
+		if got := MinimumPassCount(s); got != want {
+			t.Errorf("MinimumPassCount(%d) = %d, want %d", s, got, want)
+		}
+	}
+}
+
+func TestWriteFinalAnalysisReport(t *testing.T) {
+	report := &nist.MultiSequenceReport{
+		SequenceLength: 1000000,
+		NumSequences:   100,
+		Results: []nist.SequenceAnalysis{
+			{
+				Name:             "frequency_monobit",
+				Histogram:        [10]int{10, 8, 12, 9, 11, 10, 10, 9, 11, 10},
+				PValueT:          0.991468,
+				UniformityPassed: true,
+				PassedSequences:  99,
+				SampleSize:       100,
+				ProportionPassed: true,
+			},
+			{
+				Name:             "runs",
+				Histogram:        [10]int{100},
+				PValueT:          0,
+				PassedSequences:  90,
+				SampleSize:       100,
+				ProportionPassed: false,
+			},
+			{
+				Name:             "random_excursions/x=-4",
+				Histogram:        [10]int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+				PValueT:          0.5,
+				UniformityPassed: true,
+				PassedSequences:  55,
+				SampleSize:       55,
+				ProportionPassed: true,
+			},
+			{Name: "random_excursions_variant/x=-9"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteFinalAnalysisReport(&buf, "data/data.pi", report); err != nil {
+		t.Fatalf("WriteFinalAnalysisReport failed: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"   generator is <data/data.pi>\n",
+		" C1  C2  C3  C4  C5  C6  C7  C8  C9 C10  P-VALUE  PROPORTION  STATISTICAL TEST\n",
+		" 10   8  12   9  11  10  10   9  11  10  0.991468     99/100     Frequency\n",
+		"100   0   0   0   0   0   0   0   0   0  0.000000 *   90/100  *  Runs\n",
+		"  1   2   3   4   5   6   7   8   9  10  0.500000     55/55      RandomExcursions\n",
+		"  0   0   0   0   0   0   0   0   0   0     ----        ------     RandomExcursionsVariant\n",
+		"random excursion (variant) test is approximately = 96 for a\nsample size = 100 binary sequences.\n",
+		"is approximately = 52 for a sample size = 55 binary sequences.\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report lacks %q:\n%s", want, out)
+		}
+	}
+}
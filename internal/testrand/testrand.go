@@ -0,0 +1,16 @@
+// Package testrand provides the deterministic pseudo-random input shared by the tests
+// of several packages.
+package testrand
+
+// Bytes returns n bytes of deterministic pseudo-random data: the high byte of each step
+// of a 64-bit linear congruential generator started at seed. Tests pick the seed, so
+// data with a known property (e.g. passing all 15 tests) stays reproducible.
+func Bytes(n int, seed uint64) []byte {
+	data := make([]byte, n)
+	state := seed
+	for i := range data {
+		state = state*6364136223846793005 + 1442695040888963407
+		data[i] = byte(state >> 56)
+	}
+	return data
+}
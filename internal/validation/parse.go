@@ -0,0 +1,198 @@
+package validation
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/sts"
+)
+
+// ParseResults parses a results.txt file: one p-value per line, blank lines ignored.
+func ParseResults(r io.Reader) ([]float64, error) {
+	var values []float64
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid p-value %q", line, text)
+		}
+		values = append(values, v)
+	}
+	return values, scanner.Err()
+}
+
+// StatsEntry is a p-value line of a stats.txt file.
+type StatsEntry struct {
+	// Name identifies the statistic like Statistic.Name; it is empty if the line does
+	// not tell, e.g. for single-valued tests.
+	Name   string
+	PValue float64
+	Passed bool
+}
+
+var (
+	// SUCCESS		x = -4 chi^2 =  3.835698 p_value = 0.573306
+	excursionLine = regexp.MustCompile(`^(SUCCESS|FAILURE)\s+x\s*=\s*(-?\d+)\s+chi\^2\s*=\s*\S+\s+p_value\s*=\s*(\S+)`)
+	// SUCCESS		(x = -9) Total visits = 1450; p-value = 0.858946
+	variantLine = regexp.MustCompile(`^(SUCCESS|FAILURE)\s+\(x\s*=\s*(-?\d+)\)\s+Total visits\s*=\s*\d+;\s*p-value\s*=\s*(\S+)`)
+	// 000000001  239  235  254  278  207  229  225  242 14.116057 0.078790 SUCCESS    0
+	templateLine = regexp.MustCompile(`^\s*([01]+)\s+(?:\d+\s+)+\S+\s+(\S+)\s+(SUCCESS|FAILURE)`)
+	// SUCCESS		p_value2 = 0.462921
+	pValueLine = regexp.MustCompile(`^(SUCCESS|FAILURE)\s+p_value(\d?)\s*=\s*(\S+)`)
+)
+
+// ParseStats extracts the p-value lines of a stats.txt file as written by the STS, and
+// so by sts.ExperimentWriter. The Cumulative Sums p-values are named after the
+// "(FORWARD)" and "(REVERSE)" headings that precede them.
+func ParseStats(r io.Reader) ([]StatsEntry, error) {
+	var entries []StatsEntry
+	direction := ""
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		switch {
+		case strings.Contains(text, "(FORWARD)"):
+			direction = "forward"
+			continue
+		case strings.Contains(text, "(REVERSE)"):
+			direction = "reverse"
+			continue
+		}
+
+		var verdict, name, p string
+		if m := excursionLine.FindStringSubmatch(text); m != nil {
+			verdict, name, p = m[1], "x="+m[2], m[3]
+		} else if m := variantLine.FindStringSubmatch(text); m != nil {
+			verdict, name, p = m[1], "x="+m[2], m[3]
+		} else if m := templateLine.FindStringSubmatch(text); m != nil {
+			verdict, name, p = m[3], m[1], m[2]
+		} else if m := pValueLine.FindStringSubmatch(text); m != nil {
+			verdict, p = m[1], m[3]
+			if m[2] != "" {
+				name = "p_value" + m[2]
+			} else {
+				name = direction
+			}
+		} else {
+			continue
+		}
+
+		v, err := strconv.ParseFloat(p, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid p-value %q", line, p)
+		}
+		entries = append(entries, StatsEntry{Name: name, PValue: v, Passed: verdict == "SUCCESS"})
+	}
+	return entries, scanner.Err()
+}
+
+// ReadExperiment reads the results.txt files below an STS experiments/AlgorithmTesting
+// directory, or one written by sts.ExperimentWriter. Tests without p-values in
+// results.txt are left out. The p-values are named from stats.txt; where it does not
+// name them, e.g. for a test that was not applicable, the layout of the STS applies and
+// the templates are taken to belong to a single sequence and are aligned by position.
+func ReadExperiment(dir string) (*Experiment, error) {
+	e := &Experiment{Tests: make(map[string][][]Statistic)}
+	for _, t := range layouts {
+		testDir := filepath.Join(dir, sts.TestName(t.name))
+		values, err := readFile(filepath.Join(testDir, "results.txt"), ParseResults)
+		if errors.Is(err, fs.ErrNotExist) || (err == nil && len(values) == 0) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		entries, err := readFile(filepath.Join(testDir, "stats.txt"), ParseStats)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		names := statsNames(entries, len(values))
+		if names == nil {
+			names = t.stats
+		}
+		seqs, err := splitSequences(values, names)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", sts.TestName(t.name), err)
+		}
+		e.Tests[t.name] = seqs
+	}
+	return e, nil
+}
+
+func readFile[T any](path string, parse func(io.Reader) ([]T, error)) ([]T, error) {
+	f, err := os.Open(path) //nolint:gosec // path below the user-selected directory
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	v, err := parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return v, nil
+}
+
+// statsNames returns the names of the p-values of one sequence, which repeat for every
+// further sequence, as given by the stats.txt entries of n results: single if the
+// entries are unnamed and nil if they do not match the results.
+func statsNames(entries []StatsEntry, n int) []string {
+	if len(entries) != n || n == 0 {
+		return nil
+	}
+	if entries[0].Name == "" {
+		if slices.ContainsFunc(entries, func(e StatsEntry) bool { return e.Name != "" }) {
+			return nil
+		}
+		return single
+	}
+	perSeq := n
+	for i := 1; i < n; i++ {
+		if entries[i].Name == entries[0].Name {
+			perSeq = i
+			break
+		}
+	}
+	names := make([]string, perSeq)
+	for i := range names {
+		names[i] = entries[i].Name
+	}
+	return names
+}
+
+// splitSequences divides values into sequences of len(names) named p-values. Without
+// names, all values belong to a single sequence.
+func splitSequences(values []float64, names []string) ([][]Statistic, error) {
+	if names == nil {
+		seq := make([]Statistic, len(values))
+		for i, v := range values {
+			seq[i] = Statistic{PValue: v}
+		}
+		return [][]Statistic{seq}, nil
+	}
+	if len(values)%len(names) != 0 {
+		return nil, fmt.Errorf("%d p-values are not a multiple of the %d per sequence", len(values), len(names))
+	}
+	seqs := make([][]Statistic, 0, len(values)/len(names))
+	for i := 0; i < len(values); i += len(names) {
+		seq := make([]Statistic, len(names))
+		for j, name := range names {
+			seq[j] = Statistic{Name: name, PValue: values[i+j]}
+		}
+		seqs = append(seqs, seq)
+	}
+	return seqs, nil
+}
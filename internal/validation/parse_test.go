@@ -0,0 +1,156 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/nist"
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/sts"
+)
+
+func TestParseResults(t *testing.T) {
+	got, err := ParseResults(strings.NewReader("0.953749\n\n  0.000000\n1.000000\n"))
+	if err != nil || !reflect.DeepEqual(got, []float64{0.953749, 0, 1}) {
+		t.Fatalf("unexpected p-values %v (%v)", got, err)
+	}
+	if _, err := ParseResults(strings.NewReader("0.5\nnan?\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected error for line 2, got %v", err)
+	}
+}
+
+func TestParseStats(t *testing.T) {
+	// Excerpts of the stats.txt files of sts-2.1.2
+	tests := []struct {
+		name  string
+		stats string
+		want  []StatsEntry
+	}{
+		{"frequency", "\t\t(b) S_n/n               = 0.000058\n\t\t---------------------------------------------\nSUCCESS\t\tp_value = 0.953749\n", []StatsEntry{
+			{"", 0.953749, true},
+		}},
+		{"cusum", "\t\t      CUMULATIVE SUMS (FORWARD) TEST\n\t\t(a) The maximum partial sum = 1024\nSUCCESS\t\tp_value = 0.669886\n\n" +
+			"\t\t      CUMULATIVE SUMS (REVERSE) TEST\nSUCCESS\t\tp_value = 0.724265\n", []StatsEntry{
+			{"forward", 0.669886, true}, {"reverse", 0.724265, true},
+		}},
+		{"serial", "\t\t(g) Del_2               = 16331.587520\nSUCCESS\t\tp_value1 = 0.766182\nSUCCESS\t\tp_value2 = 0.462921\n", []StatsEntry{
+			{"p_value1", 0.766182, true}, {"p_value2", 0.462921, true},
+		}},
+		{"templates", "\t\tTemplate   W_1  W_2  W_3  W_4  W_5  W_6  W_7  W_8    Chi^2   P_value Assignment Index\n" +
+			"000000001  239  235  254  278  207  229  225  242 14.116057 0.078790 SUCCESS    0\n" +
+			"000000011  1002  235  254  278  207  229  225  242 54.116057 0.000001 FAILURE    1\n", []StatsEntry{
+			{"000000001", 0.078790, true}, {"000000011", 0.000001, false},
+		}},
+		{"excursions", "SUCCESS\t\tx = -4 chi^2 =  3.835698 p_value = 0.573306\nFAILURE\t\tx = -1 chi^2 = 15.692617 p_value = 0.007779\nSUCCESS\t\tx =  1 chi^2 =  2.430872 p_value = 0.786868\n", []StatsEntry{
+			{"x=-4", 0.573306, true}, {"x=-1", 0.007779, false}, {"x=1", 0.786868, true},
+		}},
+		{"variant", "SUCCESS\t\t(x = -9) Total visits = 1450; p-value = 0.858946\nSUCCESS\t\t(x =  9) Total visits = 1610; p-value = 0.593930\n", []StatsEntry{
+			{"x=-9", 0.858946, true}, {"x=9", 0.593930, true},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseStats(strings.NewReader(tt.stats))
+			if err != nil {
+				t.Fatalf("ParseStats failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := ParseStats(strings.NewReader("SUCCESS\t\tp_value = 0.5.1\n")); err == nil {
+		t.Error("expected error for an invalid p-value")
+	}
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadExperiment(t *testing.T) {
+	dir := t.TempDir()
+	w, err := sts.NewExperimentWriter(dir)
+	if err != nil {
+		t.Fatalf("NewExperimentWriter failed: %v", err)
+	}
+	for i := range 2 {
+		results := []nist.TestResult{
+			{Name: "frequency_monobit", PValue: 0.5 + float64(i)/10, Status: nist.StatusPassed, Passed: true},
+			{Name: "random_excursions", Status: nist.StatusNotApplicable, Warning: "insufficient cycles"},
+			{Name: "non_overlapping_template", PValue: 0.004, Status: nist.StatusFailed, SubResults: []nist.SubResult{
+				{Name: "000000001", PValue: 0.25, Passed: true, Statistic: 2.5, Counts: []int{239, 235}},
+				{Name: "000000011", PValue: 0.004, Statistic: 11, Counts: []int{1002, 235}},
+			}},
+		}
+		if err := w.AddSequence(i, results); err != nil {
+			t.Fatalf("AddSequence failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	e, err := ReadExperiment(dir)
+	if err != nil {
+		t.Fatalf("ReadExperiment failed: %v", err)
+	}
+	// Tests with empty results.txt files are left out
+	if len(e.Tests) != 3 {
+		t.Fatalf("expected 3 tests, got %v", e.Tests)
+	}
+	if got := e.Tests["frequency_monobit"]; !reflect.DeepEqual(got, [][]Statistic{{{PValue: 0.5}}, {{PValue: 0.6}}}) {
+		t.Errorf("unexpected frequency p-values %v", got)
+	}
+	if got := e.Tests["random_excursions"]; len(got) != 2 || len(got[1]) != 8 || got[1][7] != (Statistic{Name: "x=4"}) {
+		t.Errorf("unexpected random excursions p-values %v", got)
+	}
+	wantTemplates := []Statistic{{"000000001", 0.25}, {"000000011", 0.004}}
+	if got := e.Tests["non_overlapping_template"]; !reflect.DeepEqual(got, [][]Statistic{wantTemplates, wantTemplates}) {
+		t.Errorf("unexpected template p-values %v", got)
+	}
+
+	// Without stats.txt the templates are unnamed
+	if err := os.Remove(filepath.Join(dir, "NonOverlappingTemplate", "stats.txt")); err != nil {
+		t.Fatal(err)
+	}
+	e, err = ReadExperiment(dir)
+	if err != nil {
+		t.Fatalf("ReadExperiment failed: %v", err)
+	}
+	if got := e.Tests["non_overlapping_template"]; len(got) != 1 || len(got[0]) != 4 || got[0][0].Name != "" {
+		t.Errorf("expected 4 unnamed p-values, got %v", got)
+	}
+}
+
+func TestReadExperimentErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "CumulativeSums", "results.txt"), "0.1\n0.2\n0.3\n")
+	if _, err := ReadExperiment(dir); err == nil || !strings.Contains(err.Error(), "CumulativeSums") {
+		t.Errorf("expected error for an odd number of Cusum p-values, got %v", err)
+	}
+
+	dir = t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "Frequency", "results.txt"), "p\n")
+	if _, err := ReadExperiment(dir); err == nil {
+		t.Error("expected error for an invalid results.txt")
+	}
+
+	dir = t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "NonOverlappingTemplate", "results.txt"), "0.1\n")
+	if err := os.MkdirAll(filepath.Join(dir, "NonOverlappingTemplate", "stats.txt"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadExperiment(dir); err == nil {
+		t.Error("expected error for an unreadable stats.txt")
+	}
+}
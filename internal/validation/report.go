@@ -0,0 +1,68 @@
+package validation
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteMarkdown writes the report as GitHub-flavored Markdown for CI summaries: a table
+// with one row per test, followed by every mismatching statistic. title heads the
+// report, e.g. the name of the dataset.
+func (r *Report) WriteMarkdown(w io.Writer, title string) error {
+	bw := bufio.NewWriter(w)
+
+	verdict, mismatches := "passed", 0
+	if !r.Passed {
+		verdict = "failed"
+	}
+	for _, t := range r.Tests {
+		mismatches += len(t.Mismatches())
+	}
+	fmt.Fprintf(bw, "## %s\n\n", title)
+	fmt.Fprintf(bw, "**%s**: %d tests compared at tolerance %g, %d mismatching statistics.\n\n", verdict, len(r.Tests), r.Tolerance, mismatches)
+
+	bw.WriteString("| Test | STS | Statistics | Max \\|diff\\| | Status |\n")
+	bw.WriteString("|---|---|---:|---:|---|\n")
+	for _, t := range r.Tests {
+		status := "ok"
+		switch {
+		case t.Skipped:
+			status = "skipped: " + t.Reason
+		case t.Reason != "":
+			status = "**mismatch**: " + t.Reason
+		case !t.Passed:
+			status = "**mismatch**"
+		}
+		fmt.Fprintf(bw, "| %s | %s | %d | %.2e | %s |\n", t.Name, t.STSName, len(t.Statistics), t.MaxDiff, status)
+	}
+
+	if mismatches > 0 {
+		bw.WriteString("\n### Mismatches\n\n")
+		bw.WriteString("| Test | Sequence | Statistic | Reference | Go | \\|diff\\| | Note |\n")
+		bw.WriteString("|---|---:|---|---:|---:|---:|---|\n")
+		for _, t := range r.Tests {
+			for _, s := range t.Mismatches() {
+				fmt.Fprintf(bw, "| %s | %d | %s | %s | %s | %.2e | %s |\n",
+					t.Name, s.Sequence, s.Name, formatPValue(s.Reference), formatPValue(s.Go), s.Diff, s.Note)
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// formatPValue formats p like the STS, or "-" if it is missing.
+func formatPValue(p *float64) string {
+	if p == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%f", *p)
+}
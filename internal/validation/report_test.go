@@ -0,0 +1,65 @@
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func testReport() *Report {
+	ref := &Experiment{Tests: map[string][][]Statistic{
+		"frequency_monobit":        {{{PValue: 0.953749}}},
+		"non_overlapping_template": {{{"000000001", 0.078790}, {"000000011", 0.5}}},
+		"runs":                     {{{PValue: 0.561917}}},
+	}}
+	got := &Experiment{Tests: map[string][][]Statistic{
+		"frequency_monobit":        {{{PValue: 0.953749}}},
+		"non_overlapping_template": {{{"000000001", 0.078800}}},
+	}}
+	return Compare(ref, got, 1e-6)
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testReport().WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	var got Report
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if got.Passed || len(got.Tests) != 3 || got.Tests[2].Statistics[1].Go != nil || *got.Tests[2].Statistics[1].Reference != 0.5 {
+		t.Errorf("unexpected report: %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), `"sts_name": "NonOverlappingTemplate"`) {
+		t.Errorf("report lacks the STS name:\n%s", buf.String())
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testReport().WriteMarkdown(&buf, "data.e (1000000 bits)"); err != nil {
+		t.Fatalf("WriteMarkdown failed: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"## data.e (1000000 bits)\n",
+		"**failed**: 3 tests compared at tolerance 1e-06, 2 mismatching statistics.",
+		"| frequency_monobit | Frequency | 1 | 0.00e+00 | ok |\n",
+		"| non_overlapping_template | NonOverlappingTemplate | 2 | 1.00e-05 | **mismatch** |\n",
+		"| runs | Runs | 0 | 0.00e+00 | skipped: no Go results |\n",
+		"| non_overlapping_template | 1 | 000000001 | 0.078790 | 0.078800 | 1.00e-05 |  |\n",
+		"| non_overlapping_template | 1 | 000000011 | 0.500000 | - | 0.00e+00 | missing in the Go results |\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report lacks %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	report := &Report{Tolerance: 1e-6, Passed: true, Tests: []TestDiff{{Name: "runs", STSName: "Runs", Passed: true}}}
+	if err := report.WriteMarkdown(&buf, "ok"); err != nil || strings.Contains(buf.String(), "Mismatches") || !strings.Contains(buf.String(), "**passed**") {
+		t.Errorf("unexpected report (%v):\n%s", err, buf.String())
+	}
+}
@@ -0,0 +1,271 @@
+// Package validation compares test results with those of the NIST STS reference
+// implementation (sts-2.1.2). It reads the results.txt and stats.txt files of an STS
+// experiments/AlgorithmTesting directory, aligns the p-values of multi-valued tests by
+// statistic (forward/reverse Cusum, both Serial p-values, every template and random
+// excursion state) and reports the differences as JSON or Markdown.
+package validation
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/nist"
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/sts"
+)
+
+// Statistic is a single p-value of a test for one sequence.
+type Statistic struct {
+	// Name identifies the statistic within a multi-valued test, e.g. "reverse",
+	// "p_value2", "000000001" or "x=-4"; it is empty for single-valued tests.
+	Name   string
+	PValue float64
+}
+
+// Experiment holds the p-values of one or more sequences.
+type Experiment struct {
+	// Tests maps a suite test name (e.g. "binary_matrix_rank") to the statistics of
+	// every sequence, in sequence order.
+	Tests map[string][][]Statistic
+}
+
+// testLayout names the p-values the STS writes per sequence for a test.
+type testLayout struct {
+	name string
+	// stats names the p-values of one sequence; nil if they depend on the parameters,
+	// as for the templates, and are read from stats.txt.
+	stats []string
+}
+
+var single = []string{""}
+
+// layouts lists the 15 tests in the order of the suite and the STS.
+var layouts = []testLayout{
+	{"frequency_monobit", single},
+	{"block_frequency", single},
+	{"cumulative_sums", []string{"forward", "reverse"}},
+	{"runs", single},
+	{"longest_run", single},
+	{"binary_matrix_rank", single},
+	{"discrete_fourier_transform", single},
+	{"non_overlapping_template", nil},
+	{"overlapping_template", single},
+	{"universal_statistical", single},
+	{"approximate_entropy", single},
+	{"random_excursions", states(-4, 4)},
+	{"random_excursions_variant", states(-9, 9)},
+	{"serial", []string{"p_value1", "p_value2"}},
+	{"linear_complexity", single},
+}
+
+// states names the random walk states lo..hi except 0 like nist.SubResult, e.g. "x=-4".
+func states(lo, hi int) []string {
+	var names []string
+	for x := lo; x <= hi; x++ {
+		if x != 0 {
+			names = append(names, fmt.Sprintf("x=%d", x))
+		}
+	}
+	return names
+}
+
+func layoutOf(name string) (testLayout, bool) {
+	i := slices.IndexFunc(layouts, func(t testLayout) bool { return t.name == name })
+	if i < 0 {
+		return testLayout{}, false
+	}
+	return layouts[i], true
+}
+
+// FromResults collects the results of the suite for each sequence in the form the STS
+// writes them: one p-value per sub-result, and p-values of 0 for a test that is not
+// applicable.
+func FromResults(sequences ...[]nist.TestResult) *Experiment {
+	e := &Experiment{Tests: make(map[string][][]Statistic)}
+	for _, results := range sequences {
+		for _, r := range results {
+			e.Tests[r.Name] = append(e.Tests[r.Name], resultStatistics(r))
+		}
+	}
+	return e
+}
+
+func resultStatistics(r nist.TestResult) []Statistic {
+	if r.MultiValued() {
+		stats := make([]Statistic, len(r.SubResults))
+		for i, sub := range r.SubResults {
+			stats[i] = Statistic{Name: sub.Name}
+			if r.Status.Completed() {
+				stats[i].PValue = sub.PValue
+			}
+		}
+		return stats
+	}
+	if !r.Status.Completed() {
+		if t, ok := layoutOf(r.Name); ok && t.stats != nil {
+			stats := make([]Statistic, len(t.stats))
+			for i, name := range t.stats {
+				stats[i] = Statistic{Name: name}
+			}
+			return stats
+		}
+	}
+	return []Statistic{{PValue: r.PValue}}
+}
+
+// Report is the outcome of Compare.
+type Report struct {
+	Tolerance float64 `json:"tolerance"`
+	// Passed is true iff no compared statistic differs by more than Tolerance.
+	Passed bool       `json:"passed"`
+	Tests  []TestDiff `json:"tests"`
+}
+
+// TestDiff compares the statistics of a single test.
+type TestDiff struct {
+	Name    string `json:"name"`
+	STSName string `json:"sts_name"`
+	// Skipped is set when one side has no results for the test; Reason tells which.
+	Skipped    bool            `json:"skipped"`
+	Reason     string          `json:"reason,omitempty"`
+	Passed     bool            `json:"passed"`
+	MaxDiff    float64         `json:"max_diff"`
+	Statistics []StatisticDiff `json:"statistics,omitempty"`
+}
+
+// Mismatches returns the statistics that failed the comparison.
+func (d TestDiff) Mismatches() []StatisticDiff {
+	var failed []StatisticDiff
+	for _, s := range d.Statistics {
+		if !s.Passed {
+			failed = append(failed, s)
+		}
+	}
+	return failed
+}
+
+// StatisticDiff compares one p-value of one sequence. A nil Reference or Go value is
+// missing on that side.
+type StatisticDiff struct {
+	// Sequence counts from 1 like the STS.
+	Sequence  int      `json:"sequence"`
+	Name      string   `json:"name,omitempty"`
+	Reference *float64 `json:"reference"`
+	Go        *float64 `json:"go"`
+	Diff      float64  `json:"diff"`
+	Passed    bool     `json:"passed"`
+	// Note explains an alignment other than by name, e.g. on the minimum p-value.
+	Note string `json:"note,omitempty"`
+}
+
+// Compare aligns the statistics of got with those of the reference ref and reports the
+// absolute differences of the p-values. A statistic passes if both p-values lie in
+// [0, 1] and differ by at most tolerance.
+//
+// Statistics are aligned by name. When got reports a single p-value where the reference
+// reports several, it is compared with their minimum, the summary earlier versions
+// of the suite gave for multi-valued tests without sub-results.
+func Compare(ref, got *Experiment, tolerance float64) *Report {
+	report := &Report{Tolerance: tolerance, Passed: true}
+	for _, t := range layouts {
+		d := TestDiff{Name: t.name, STSName: sts.TestName(t.name)}
+		refSeqs, inRef := ref.Tests[t.name]
+		gotSeqs, inGot := got.Tests[t.name]
+		switch {
+		case !inRef && !inGot:
+			continue
+		case !inRef:
+			d.Skipped, d.Reason = true, "no reference results"
+		case !inGot:
+			d.Skipped, d.Reason = true, "no Go results"
+		case len(refSeqs) != len(gotSeqs):
+			d.Reason = fmt.Sprintf("reference has %d sequences, Go %d", len(refSeqs), len(gotSeqs))
+		default:
+			d.Passed = true
+			for i := range refSeqs {
+				for _, s := range align(refSeqs[i], gotSeqs[i]) {
+					s.Sequence = i + 1
+					s.check(tolerance)
+					d.Passed = d.Passed && s.Passed
+					if s.Reference != nil && s.Go != nil {
+						d.MaxDiff = max(d.MaxDiff, s.Diff)
+					}
+					d.Statistics = append(d.Statistics, s)
+				}
+			}
+		}
+		report.Passed = report.Passed && (d.Passed || d.Skipped)
+		report.Tests = append(report.Tests, d)
+	}
+	return report
+}
+
+// align pairs the statistics of one sequence by name, or by position where a side
+// does not name them.
+func align(ref, got []Statistic) []StatisticDiff {
+	if len(got) == 1 && got[0].Name == "" && len(ref) > 1 {
+		minP := math.Inf(1)
+		for _, s := range ref {
+			minP = min(minP, s.PValue)
+		}
+		return []StatisticDiff{{Name: "min", Reference: &minP, Go: &got[0].PValue, Note: "aligned on the minimum p-value"}}
+	}
+
+	var diffs []StatisticDiff
+	if named(ref) && named(got) {
+		index := make(map[string]int, len(got))
+		for i, s := range got {
+			index[s.Name] = i
+		}
+		matched := make([]bool, len(got))
+		for i := range ref {
+			d := StatisticDiff{Name: ref[i].Name, Reference: &ref[i].PValue}
+			if j, ok := index[ref[i].Name]; ok {
+				d.Go = &got[j].PValue
+				matched[j] = true
+			}
+			diffs = append(diffs, d)
+		}
+		for j := range got {
+			if !matched[j] {
+				diffs = append(diffs, StatisticDiff{Name: got[j].Name, Go: &got[j].PValue})
+			}
+		}
+		return diffs
+	}
+
+	for i := range max(len(ref), len(got)) {
+		var d StatisticDiff
+		if i < len(ref) {
+			d.Name, d.Reference = ref[i].Name, &ref[i].PValue
+		}
+		if i < len(got) {
+			d.Go = &got[i].PValue
+			if d.Name == "" {
+				d.Name = got[i].Name
+			}
+		}
+		diffs = append(diffs, d)
+	}
+	return diffs
+}
+
+func named(stats []Statistic) bool {
+	return !slices.ContainsFunc(stats, func(s Statistic) bool { return s.Name == "" })
+}
+
+func (d *StatisticDiff) check(tolerance float64) {
+	switch {
+	case d.Reference == nil:
+		d.Note = "missing in the reference"
+	case d.Go == nil:
+		d.Note = "missing in the Go results"
+	default:
+		d.Diff = math.Abs(*d.Reference - *d.Go)
+		d.Passed = validPValue(*d.Reference) && validPValue(*d.Go) && d.Diff <= tolerance
+	}
+}
+
+func validPValue(p float64) bool {
+	return p >= 0 && p <= 1
+}
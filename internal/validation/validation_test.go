@@ -0,0 +1,159 @@
+package validation
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/datasets"
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/nist"
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/sts"
+)
+
+func TestFromResults(t *testing.T) {
+	e := FromResults(
+		[]nist.TestResult{
+			{Name: "frequency_monobit", PValue: 0.5, Status: nist.StatusPassed},
+			{Name: "random_excursions_variant", Status: nist.StatusNotApplicable},
+			{Name: "non_overlapping_template", Status: nist.StatusError, SubResults: []nist.SubResult{{Name: "001", PValue: 0.3}}},
+		},
+		[]nist.TestResult{
+			{Name: "frequency_monobit", PValue: 0.25, Status: nist.StatusPassed},
+		},
+	)
+	if got := e.Tests["frequency_monobit"]; len(got) != 2 || got[1][0] != (Statistic{PValue: 0.25}) {
+		t.Errorf("unexpected frequency statistics %v", got)
+	}
+	// Like the STS, a test that is not applicable reports zeros
+	if got := e.Tests["random_excursions_variant"][0]; len(got) != 18 || got[0] != (Statistic{Name: "x=-9"}) {
+		t.Errorf("unexpected variant statistics %v", got)
+	}
+	if got := e.Tests["non_overlapping_template"][0]; len(got) != 1 || got[0] != (Statistic{Name: "001"}) {
+		t.Errorf("unexpected template statistics %v", got)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	ref := &Experiment{Tests: map[string][][]Statistic{
+		"frequency_monobit":        {{{PValue: 0.953749}}},
+		"block_frequency":          {{{PValue: 0.211072}}},
+		"cumulative_sums":          {{{"forward", 0.669886}, {"reverse", 0.724265}}},
+		"non_overlapping_template": {{{"000000001", 0.078790}, {"000000011", 0.5}, {"000000101", 0.25}}},
+		"random_excursions":        {{{"x=-4", 0.573306}}, {{"x=-4", 0.5}}},
+		"serial":                   {{{"p_value1", 0.766182}, {"p_value2", 0.462921}}},
+		"runs":                     {{{PValue: 0.561917}}},
+		"longest_run":              {{{PValue: 0.7}}, {{PValue: 0.1}}},
+	}}
+	got := &Experiment{Tests: map[string][][]Statistic{
+		"frequency_monobit":          {{{PValue: 0.9537494}}},
+		"block_frequency":            {{{PValue: 0.211082}}},
+		"cumulative_sums":            {{{PValue: 0.669886}}},
+		"non_overlapping_template":   {{{"000000001", 0.078790}, {"000000101", 0.25}, {"000000111", 0.1}}},
+		"random_excursions":          {{{"x=-4", 0.573306}}, {{"x=-4", 0.5}}},
+		"serial":                     {{{"p_value2", 0.462921}, {"p_value1", 0.766182}}},
+		"discrete_fourier_transform": {{{PValue: 0.8}}},
+		"longest_run":                {{{PValue: 0.7}}},
+	}}
+	report := Compare(ref, got, 1e-6)
+	if report.Passed {
+		t.Fatal("expected the comparison to fail")
+	}
+
+	byName := make(map[string]TestDiff)
+	for _, d := range report.Tests {
+		byName[d.Name] = d
+	}
+	if len(byName) != 9 || report.Tests[0].Name != "frequency_monobit" || report.Tests[0].STSName != "Frequency" {
+		t.Fatalf("unexpected tests %+v", report.Tests)
+	}
+
+	tests := []struct {
+		name       string
+		passed     bool
+		skipped    bool
+		mismatches int
+	}{
+		{"frequency_monobit", true, false, 0},
+		{"block_frequency", false, false, 1},
+		{"cumulative_sums", true, false, 0},
+		{"non_overlapping_template", false, false, 2},
+		{"random_excursions", true, false, 0},
+		{"serial", true, false, 0},
+		{"runs", false, true, 0},
+		{"discrete_fourier_transform", false, true, 0},
+		{"longest_run", false, false, 0},
+	}
+	for _, tt := range tests {
+		d := byName[tt.name]
+		if d.Passed != tt.passed || d.Skipped != tt.skipped || len(d.Mismatches()) != tt.mismatches {
+			t.Errorf("%s: expected passed=%v skipped=%v with %d mismatches, got %+v", tt.name, tt.passed, tt.skipped, tt.mismatches, d)
+		}
+	}
+
+	if d := byName["block_frequency"]; math.Abs(d.MaxDiff-1e-5) > 1e-12 {
+		t.Errorf("expected max diff 1e-5, got %g", d.MaxDiff)
+	}
+	if s := byName["cumulative_sums"].Statistics; len(s) != 1 || s[0].Name != "min" || *s[0].Reference != 0.669886 || s[0].Note == "" {
+		t.Errorf("expected alignment on the minimum, got %+v", s)
+	}
+	mismatches := byName["non_overlapping_template"].Mismatches()
+	if mismatches[0].Name != "000000011" || mismatches[0].Go != nil || mismatches[1].Name != "000000111" || mismatches[1].Reference != nil {
+		t.Errorf("unexpected template mismatches %+v", mismatches)
+	}
+	if s := byName["random_excursions"].Statistics; len(s) != 2 || s[1].Sequence != 2 {
+		t.Errorf("unexpected excursion statistics %+v", s)
+	}
+	if d := byName["longest_run"]; !strings.Contains(d.Reason, "2 sequences") {
+		t.Errorf("unexpected reason %q", d.Reason)
+	}
+}
+
+func TestCompareInvalidPValues(t *testing.T) {
+	ref := &Experiment{Tests: map[string][][]Statistic{"runs": {{{PValue: math.NaN()}}}, "serial": {{{PValue: 1.5}, {PValue: 1.5}}}}}
+	got := &Experiment{Tests: map[string][][]Statistic{"runs": {{{PValue: math.NaN()}}}, "serial": {{{PValue: 1.5}, {PValue: 1.5}}}}}
+	report := Compare(ref, got, 1)
+	if report.Passed || report.Tests[0].Passed || report.Tests[1].Passed {
+		t.Errorf("expected invalid p-values to fail, got %+v", report)
+	}
+}
+
+// TestCompareExperimentWriter round-trips the results of the suite through the STS
+// directory layout.
+func TestCompareExperimentWriter(t *testing.T) {
+	data, err := datasets.Bits("data.e", nist.MinBits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := nist.RunAllTestsSequence(context.Background(), nist.BitSequenceFromBytes(data), nist.SuiteConfig{})
+	if err != nil {
+		t.Fatalf("RunAllTestsSequence failed: %v", err)
+	}
+
+	dir := t.TempDir()
+	w, err := sts.NewExperimentWriter(dir)
+	if err != nil {
+		t.Fatalf("NewExperimentWriter failed: %v", err)
+	}
+	if err := w.AddSequence(0, results); err != nil {
+		t.Fatalf("AddSequence failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	ref, err := ReadExperiment(dir)
+	if err != nil {
+		t.Fatalf("ReadExperiment failed: %v", err)
+	}
+
+	// results.txt holds six decimals
+	report := Compare(ref, FromResults(results), 5e-7)
+	if !report.Passed || len(report.Tests) != 15 {
+		t.Fatalf("expected all 15 tests to match, got %+v", report)
+	}
+	for _, d := range report.Tests {
+		if d.Name == "non_overlapping_template" && (len(d.Statistics) != 148 || d.Statistics[0].Name != "000000001") {
+			t.Errorf("templates not aligned by name: %+v", d.Statistics[:1])
+		}
+	}
+}
@@ -21,13 +21,280 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Sp80022BitstreamEncoding selects how Sp80022TestRequest.bitstream is encoded. The text
+// encodings ignore whitespace (space, tab, CR, LF) and reject any other invalid character.
+type Sp80022BitstreamEncoding int32
+
+const (
+	// Same as RAW
+	Sp80022BitstreamEncoding_SP80022_BITSTREAM_ENCODING_UNSPECIFIED Sp80022BitstreamEncoding = 0
+	// Eight bits per byte, most significant bit first
+	Sp80022BitstreamEncoding_SP80022_BITSTREAM_ENCODING_RAW Sp80022BitstreamEncoding = 1
+	// One ASCII '0' or '1' per bit, as in the NIST STS data files; the number of bits
+	// need not be a multiple of 8
+	Sp80022BitstreamEncoding_SP80022_BITSTREAM_ENCODING_ASCII_BITS Sp80022BitstreamEncoding = 2
+	// Two hexadecimal digits (upper or lower case) per byte
+	Sp80022BitstreamEncoding_SP80022_BITSTREAM_ENCODING_HEX Sp80022BitstreamEncoding = 3
+	// Standard base64 with padding (RFC 4648), line breaks allowed
+	Sp80022BitstreamEncoding_SP80022_BITSTREAM_ENCODING_BASE64_TEXT Sp80022BitstreamEncoding = 4
+)
+
+// Enum value maps for Sp80022BitstreamEncoding.
+var (
+	Sp80022BitstreamEncoding_name = map[int32]string{
+		0: "SP80022_BITSTREAM_ENCODING_UNSPECIFIED",
+		1: "SP80022_BITSTREAM_ENCODING_RAW",
+		2: "SP80022_BITSTREAM_ENCODING_ASCII_BITS",
+		3: "SP80022_BITSTREAM_ENCODING_HEX",
+		4: "SP80022_BITSTREAM_ENCODING_BASE64_TEXT",
+	}
+	Sp80022BitstreamEncoding_value = map[string]int32{
+		"SP80022_BITSTREAM_ENCODING_UNSPECIFIED": 0,
+		"SP80022_BITSTREAM_ENCODING_RAW":         1,
+		"SP80022_BITSTREAM_ENCODING_ASCII_BITS":  2,
+		"SP80022_BITSTREAM_ENCODING_HEX":         3,
+		"SP80022_BITSTREAM_ENCODING_BASE64_TEXT": 4,
+	}
+)
+
+func (x Sp80022BitstreamEncoding) Enum() *Sp80022BitstreamEncoding {
+	p := new(Sp80022BitstreamEncoding)
+	*p = x
+	return p
+}
+
+func (x Sp80022BitstreamEncoding) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Sp80022BitstreamEncoding) Descriptor() protoreflect.EnumDescriptor {
+	return file_nist_sp800_22_proto_enumTypes[0].Descriptor()
+}
+
+func (Sp80022BitstreamEncoding) Type() protoreflect.EnumType {
+	return &file_nist_sp800_22_proto_enumTypes[0]
+}
+
+func (x Sp80022BitstreamEncoding) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Sp80022BitstreamEncoding.Descriptor instead.
+func (Sp80022BitstreamEncoding) EnumDescriptor() ([]byte, []int) {
+	return file_nist_sp800_22_proto_rawDescGZIP(), []int{0}
+}
+
+// Sp80022BitOrder selects the order of the bits within each byte of the bitstream
+type Sp80022BitOrder int32
+
+const (
+	// Same as MSB_FIRST
+	Sp80022BitOrder_SP80022_BIT_ORDER_UNSPECIFIED Sp80022BitOrder = 0
+	// Most significant bit first, as the NIST STS reads binary files
+	Sp80022BitOrder_SP80022_BIT_ORDER_MSB_FIRST Sp80022BitOrder = 1
+	// Least significant bit first
+	Sp80022BitOrder_SP80022_BIT_ORDER_LSB_FIRST Sp80022BitOrder = 2
+)
+
+// Enum value maps for Sp80022BitOrder.
+var (
+	Sp80022BitOrder_name = map[int32]string{
+		0: "SP80022_BIT_ORDER_UNSPECIFIED",
+		1: "SP80022_BIT_ORDER_MSB_FIRST",
+		2: "SP80022_BIT_ORDER_LSB_FIRST",
+	}
+	Sp80022BitOrder_value = map[string]int32{
+		"SP80022_BIT_ORDER_UNSPECIFIED": 0,
+		"SP80022_BIT_ORDER_MSB_FIRST":   1,
+		"SP80022_BIT_ORDER_LSB_FIRST":   2,
+	}
+)
+
+func (x Sp80022BitOrder) Enum() *Sp80022BitOrder {
+	p := new(Sp80022BitOrder)
+	*p = x
+	return p
+}
+
+func (x Sp80022BitOrder) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Sp80022BitOrder) Descriptor() protoreflect.EnumDescriptor {
+	return file_nist_sp800_22_proto_enumTypes[1].Descriptor()
+}
+
+func (Sp80022BitOrder) Type() protoreflect.EnumType {
+	return &file_nist_sp800_22_proto_enumTypes[1]
+}
+
+func (x Sp80022BitOrder) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Sp80022BitOrder.Descriptor instead.
+func (Sp80022BitOrder) EnumDescriptor() ([]byte, []int) {
+	return file_nist_sp800_22_proto_rawDescGZIP(), []int{1}
+}
+
+// Sp80022TestStatus is the outcome category of a single test
+type Sp80022TestStatus int32
+
+const (
+	Sp80022TestStatus_SP80022_TEST_STATUS_UNSPECIFIED Sp80022TestStatus = 0
+	// p_value >= 0.01
+	Sp80022TestStatus_SP80022_TEST_STATUS_PASSED Sp80022TestStatus = 1
+	// p_value < 0.01
+	Sp80022TestStatus_SP80022_TEST_STATUS_FAILED Sp80022TestStatus = 2
+	// The sequence does not meet the test's preconditions (e.g. fewer than 500
+	// cycles for the random excursions tests, or the runs pi estimator criterion)
+	Sp80022TestStatus_SP80022_TEST_STATUS_NOT_APPLICABLE Sp80022TestStatus = 3
+	// The test could not be computed (invalid parameter or non-finite statistic)
+	Sp80022TestStatus_SP80022_TEST_STATUS_ERROR Sp80022TestStatus = 4
+)
+
+// Enum value maps for Sp80022TestStatus.
+var (
+	Sp80022TestStatus_name = map[int32]string{
+		0: "SP80022_TEST_STATUS_UNSPECIFIED",
+		1: "SP80022_TEST_STATUS_PASSED",
+		2: "SP80022_TEST_STATUS_FAILED",
+		3: "SP80022_TEST_STATUS_NOT_APPLICABLE",
+		4: "SP80022_TEST_STATUS_ERROR",
+	}
+	Sp80022TestStatus_value = map[string]int32{
+		"SP80022_TEST_STATUS_UNSPECIFIED":    0,
+		"SP80022_TEST_STATUS_PASSED":         1,
+		"SP80022_TEST_STATUS_FAILED":         2,
+		"SP80022_TEST_STATUS_NOT_APPLICABLE": 3,
+		"SP80022_TEST_STATUS_ERROR":          4,
+	}
+)
+
+func (x Sp80022TestStatus) Enum() *Sp80022TestStatus {
+	p := new(Sp80022TestStatus)
+	*p = x
+	return p
+}
+
+func (x Sp80022TestStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Sp80022TestStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_nist_sp800_22_proto_enumTypes[2].Descriptor()
+}
+
+func (Sp80022TestStatus) Type() protoreflect.EnumType {
+	return &file_nist_sp800_22_proto_enumTypes[2]
+}
+
+func (x Sp80022TestStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Sp80022TestStatus.Descriptor instead.
+func (Sp80022TestStatus) EnumDescriptor() ([]byte, []int) {
+	return file_nist_sp800_22_proto_rawDescGZIP(), []int{2}
+}
+
+// Sp80022Generator selects one of the sample generators of the NIST STS
+type Sp80022Generator int32
+
+const (
+	Sp80022Generator_SP80022_GENERATOR_UNSPECIFIED Sp80022Generator = 0
+	// Linear Congruential: x(i+1) = 950706376 x(i) mod 2^31-1
+	Sp80022Generator_SP80022_GENERATOR_LCG Sp80022Generator = 1
+	// Quadratic Congruential I: x(i+1) = x(i)^2 mod p (512-bit prime)
+	Sp80022Generator_SP80022_GENERATOR_QUADRATIC_CONGRUENTIAL_1 Sp80022Generator = 2
+	// Quadratic Congruential II: x(i+1) = 2 x(i)^2 + 3 x(i) + 1 mod 2^512
+	Sp80022Generator_SP80022_GENERATOR_QUADRATIC_CONGRUENTIAL_2 Sp80022Generator = 3
+	// Cubic Congruential: x(i+1) = x(i)^3 mod 2^512
+	Sp80022Generator_SP80022_GENERATOR_CUBIC_CONGRUENTIAL Sp80022Generator = 4
+	// Exclusive OR: x(i) = x(i-1) xor x(i-127)
+	Sp80022Generator_SP80022_GENERATOR_XOR Sp80022Generator = 5
+	// Modular Exponentiation: x(i+1) = g^y(i) mod p (512-bit prime)
+	Sp80022Generator_SP80022_GENERATOR_MODULAR_EXPONENTIATION Sp80022Generator = 6
+	// Blum-Blum-Shub with a 1024-bit modulus
+	Sp80022Generator_SP80022_GENERATOR_BLUM_BLUM_SHUB Sp80022Generator = 7
+	// Micali-Schnorr with a 1024-bit modulus and e = 11
+	Sp80022Generator_SP80022_GENERATOR_MICALI_SCHNORR Sp80022Generator = 8
+	// G using SHA-1 (FIPS 186)
+	Sp80022Generator_SP80022_GENERATOR_G_SHA1 Sp80022Generator = 9
+)
+
+// Enum value maps for Sp80022Generator.
+var (
+	Sp80022Generator_name = map[int32]string{
+		0: "SP80022_GENERATOR_UNSPECIFIED",
+		1: "SP80022_GENERATOR_LCG",
+		2: "SP80022_GENERATOR_QUADRATIC_CONGRUENTIAL_1",
+		3: "SP80022_GENERATOR_QUADRATIC_CONGRUENTIAL_2",
+		4: "SP80022_GENERATOR_CUBIC_CONGRUENTIAL",
+		5: "SP80022_GENERATOR_XOR",
+		6: "SP80022_GENERATOR_MODULAR_EXPONENTIATION",
+		7: "SP80022_GENERATOR_BLUM_BLUM_SHUB",
+		8: "SP80022_GENERATOR_MICALI_SCHNORR",
+		9: "SP80022_GENERATOR_G_SHA1",
+	}
+	Sp80022Generator_value = map[string]int32{
+		"SP80022_GENERATOR_UNSPECIFIED":              0,
+		"SP80022_GENERATOR_LCG":                      1,
+		"SP80022_GENERATOR_QUADRATIC_CONGRUENTIAL_1": 2,
+		"SP80022_GENERATOR_QUADRATIC_CONGRUENTIAL_2": 3,
+		"SP80022_GENERATOR_CUBIC_CONGRUENTIAL":       4,
+		"SP80022_GENERATOR_XOR":                      5,
+		"SP80022_GENERATOR_MODULAR_EXPONENTIATION":   6,
+		"SP80022_GENERATOR_BLUM_BLUM_SHUB":           7,
+		"SP80022_GENERATOR_MICALI_SCHNORR":           8,
+		"SP80022_GENERATOR_G_SHA1":                   9,
+	}
+)
+
+func (x Sp80022Generator) Enum() *Sp80022Generator {
+	p := new(Sp80022Generator)
+	*p = x
+	return p
+}
+
+func (x Sp80022Generator) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Sp80022Generator) Descriptor() protoreflect.EnumDescriptor {
+	return file_nist_sp800_22_proto_enumTypes[3].Descriptor()
+}
+
+func (Sp80022Generator) Type() protoreflect.EnumType {
+	return &file_nist_sp800_22_proto_enumTypes[3]
+}
+
+func (x Sp80022Generator) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Sp80022Generator.Descriptor instead.
+func (Sp80022Generator) EnumDescriptor() ([]byte, []int) {
+	return file_nist_sp800_22_proto_rawDescGZIP(), []int{3}
+}
+
 // Sp80022TestRequest contains the bitstream and optional configuration
 type Sp80022TestRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// Raw bitstream as bytes (minimum 387,840 bits)
+	// Bitstream in the given encoding (minimum 387,840 bits; maximum set by the server's
+	// MAX_BITS, 10,000,000 bits by default). The server accepts messages of
+	// max(4 MB, MAX_BITS + 64 KB) bytes, enough for MAX_BITS bits in every encoding:
+	// MAX_BITS/8 bytes RAW, MAX_BITS/4 HEX, about MAX_BITS/6 BASE64 and MAX_BITS
+	// ASCII_BITS, plus whitespace in the text encodings.
 	Bitstream []byte `protobuf:"bytes,1,opt,name=bitstream,proto3" json:"bitstream,omitempty"`
 	// Optional test configuration parameters
-	Config        *Sp80022TestConfig `protobuf:"bytes,2,opt,name=config,proto3,oneof" json:"config,omitempty"`
+	Config *Sp80022TestConfig `protobuf:"bytes,2,opt,name=config,proto3,oneof" json:"config,omitempty"`
+	// Optional number of valid bits in bitstream, for sequences whose length is not a
+	// multiple of 8. The remaining pad bits of the last byte are ignored; the bitstream
+	// must not contain further bytes. Default: all len(bitstream) * 8 bits.
+	BitLength *int64 `protobuf:"varint,3,opt,name=bit_length,json=bitLength,proto3,oneof" json:"bit_length,omitempty"`
+	// Encoding of bitstream. Default: raw bytes. bit_length refers to the decoded bits.
+	Encoding      Sp80022BitstreamEncoding `protobuf:"varint,4,opt,name=encoding,proto3,enum=nist.sp800_22.v1.Sp80022BitstreamEncoding" json:"encoding,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -76,12 +343,181 @@ func (x *Sp80022TestRequest) GetConfig() *Sp80022TestConfig {
 	return nil
 }
 
-// Sp80022TestConfig allows customization of test parameters
+func (x *Sp80022TestRequest) GetBitLength() int64 {
+	if x != nil && x.BitLength != nil {
+		return *x.BitLength
+	}
+	return 0
+}
+
+func (x *Sp80022TestRequest) GetEncoding() Sp80022BitstreamEncoding {
+	if x != nil {
+		return x.Encoding
+	}
+	return Sp80022BitstreamEncoding_SP80022_BITSTREAM_ENCODING_UNSPECIFIED
+}
+
+// Sp80022TestStreamRequest is one message of a RunTestSuiteStream call:
+// a single header followed by any number of bitstream chunks
+type Sp80022TestStreamRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*Sp80022TestStreamRequest_Header
+	//	*Sp80022TestStreamRequest_Chunk
+	Payload       isSp80022TestStreamRequest_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Sp80022TestStreamRequest) Reset() {
+	*x = Sp80022TestStreamRequest{}
+	mi := &file_nist_sp800_22_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Sp80022TestStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sp80022TestStreamRequest) ProtoMessage() {}
+
+func (x *Sp80022TestStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nist_sp800_22_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sp80022TestStreamRequest.ProtoReflect.Descriptor instead.
+func (*Sp80022TestStreamRequest) Descriptor() ([]byte, []int) {
+	return file_nist_sp800_22_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Sp80022TestStreamRequest) GetPayload() isSp80022TestStreamRequest_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *Sp80022TestStreamRequest) GetHeader() *Sp80022TestStreamHeader {
+	if x != nil {
+		if x, ok := x.Payload.(*Sp80022TestStreamRequest_Header); ok {
+			return x.Header
+		}
+	}
+	return nil
+}
+
+func (x *Sp80022TestStreamRequest) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Payload.(*Sp80022TestStreamRequest_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isSp80022TestStreamRequest_Payload interface {
+	isSp80022TestStreamRequest_Payload()
+}
+
+type Sp80022TestStreamRequest_Header struct {
+	// Stream header (first message only)
+	Header *Sp80022TestStreamHeader `protobuf:"bytes,1,opt,name=header,proto3,oneof"`
+}
+
+type Sp80022TestStreamRequest_Chunk struct {
+	// Next part of the raw bitstream (binary bytes, no text encoding); chunks are
+	// concatenated in order.
+	// Each message must stay below the gRPC message size limit (4 MB by default).
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*Sp80022TestStreamRequest_Header) isSp80022TestStreamRequest_Payload() {}
+
+func (*Sp80022TestStreamRequest_Chunk) isSp80022TestStreamRequest_Payload() {}
+
+// Sp80022TestStreamHeader opens a RunTestSuiteStream call
+type Sp80022TestStreamHeader struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Optional test configuration parameters
+	Config *Sp80022TestConfig `protobuf:"bytes,1,opt,name=config,proto3,oneof" json:"config,omitempty"`
+	// Optional total bitstream size in bytes. When set, the server rejects oversized
+	// streams up front and checks that exactly this many bytes were received.
+	TotalBytes int64 `protobuf:"varint,2,opt,name=total_bytes,json=totalBytes,proto3" json:"total_bytes,omitempty"`
+	// Optional number of valid bits in the assembled bitstream (see Sp80022TestRequest.bit_length)
+	BitLength     *int64 `protobuf:"varint,3,opt,name=bit_length,json=bitLength,proto3,oneof" json:"bit_length,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Sp80022TestStreamHeader) Reset() {
+	*x = Sp80022TestStreamHeader{}
+	mi := &file_nist_sp800_22_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Sp80022TestStreamHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sp80022TestStreamHeader) ProtoMessage() {}
+
+func (x *Sp80022TestStreamHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_nist_sp800_22_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sp80022TestStreamHeader.ProtoReflect.Descriptor instead.
+func (*Sp80022TestStreamHeader) Descriptor() ([]byte, []int) {
+	return file_nist_sp800_22_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Sp80022TestStreamHeader) GetConfig() *Sp80022TestConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *Sp80022TestStreamHeader) GetTotalBytes() int64 {
+	if x != nil {
+		return x.TotalBytes
+	}
+	return 0
+}
+
+func (x *Sp80022TestStreamHeader) GetBitLength() int64 {
+	if x != nil && x.BitLength != nil {
+		return *x.BitLength
+	}
+	return 0
+}
+
+// Sp80022TestConfig allows customization of test parameters.
+// Zero values select the default; explicit values must lie within the ranges
+// recommended by NIST SP 800-22, otherwise the request fails with INVALID_ARGUMENT.
 type Sp80022TestConfig struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Block Frequency Test - block length M (default: 128)
 	BlockFrequencyBlockLength int32 `protobuf:"varint,1,opt,name=block_frequency_block_length,json=blockFrequencyBlockLength,proto3" json:"block_frequency_block_length,omitempty"`
-	// Non-Overlapping Template Test - block length m (default: 9)
+	// Non-Overlapping Template Test - template length m, 2..21 (default: 9)
 	NonOverlappingTemplateBlockLength int32 `protobuf:"varint,2,opt,name=non_overlapping_template_block_length,json=nonOverlappingTemplateBlockLength,proto3" json:"non_overlapping_template_block_length,omitempty"`
 	// Overlapping Template Test - block length m (default: 9)
 	OverlappingTemplateBlockLength int32 `protobuf:"varint,3,opt,name=overlapping_template_block_length,json=overlappingTemplateBlockLength,proto3" json:"overlapping_template_block_length,omitempty"`
@@ -91,13 +527,22 @@ type Sp80022TestConfig struct {
 	SerialBlockLength int32 `protobuf:"varint,5,opt,name=serial_block_length,json=serialBlockLength,proto3" json:"serial_block_length,omitempty"`
 	// Linear Complexity Test - sequence length M (default: 500)
 	LinearComplexitySequenceLength int32 `protobuf:"varint,6,opt,name=linear_complexity_sequence_length,json=linearComplexitySequenceLength,proto3" json:"linear_complexity_sequence_length,omitempty"`
-	unknownFields                  protoimpl.UnknownFields
-	sizeCache                      protoimpl.SizeCache
+	// Non-Overlapping Template Test - number of blocks N, 1..100 (default: 8)
+	NonOverlappingTemplateNumBlocks int32 `protobuf:"varint,7,opt,name=non_overlapping_template_num_blocks,json=nonOverlappingTemplateNumBlocks,proto3" json:"non_overlapping_template_num_blocks,omitempty"`
+	// Order in which the bits of each bitstream byte are read by all tests (default: MSB first).
+	// Does not apply to ASCII_BITS input, which lists the bits in order.
+	BitOrder Sp80022BitOrder `protobuf:"varint,8,opt,name=bit_order,json=bitOrder,proto3,enum=nist.sp800_22.v1.Sp80022BitOrder" json:"bit_order,omitempty"`
+	// Binary Matrix Rank Test - matrix rows M, >= 2 with N = n/(MQ) >= 38 matrices (default: 32)
+	BinaryMatrixRows int32 `protobuf:"varint,9,opt,name=binary_matrix_rows,json=binaryMatrixRows,proto3" json:"binary_matrix_rows,omitempty"`
+	// Binary Matrix Rank Test - matrix columns Q, >= 2 with N = n/(MQ) >= 38 matrices (default: 32)
+	BinaryMatrixColumns int32 `protobuf:"varint,10,opt,name=binary_matrix_columns,json=binaryMatrixColumns,proto3" json:"binary_matrix_columns,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
 }
 
 func (x *Sp80022TestConfig) Reset() {
 	*x = Sp80022TestConfig{}
-	mi := &file_nist_sp800_22_proto_msgTypes[1]
+	mi := &file_nist_sp800_22_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -109,7 +554,7 @@ func (x *Sp80022TestConfig) String() string {
 func (*Sp80022TestConfig) ProtoMessage() {}
 
 func (x *Sp80022TestConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_nist_sp800_22_proto_msgTypes[1]
+	mi := &file_nist_sp800_22_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -122,7 +567,7 @@ func (x *Sp80022TestConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Sp80022TestConfig.ProtoReflect.Descriptor instead.
 func (*Sp80022TestConfig) Descriptor() ([]byte, []int) {
-	return file_nist_sp800_22_proto_rawDescGZIP(), []int{1}
+	return file_nist_sp800_22_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Sp80022TestConfig) GetBlockFrequencyBlockLength() int32 {
@@ -167,6 +612,34 @@ func (x *Sp80022TestConfig) GetLinearComplexitySequenceLength() int32 {
 	return 0
 }
 
+func (x *Sp80022TestConfig) GetNonOverlappingTemplateNumBlocks() int32 {
+	if x != nil {
+		return x.NonOverlappingTemplateNumBlocks
+	}
+	return 0
+}
+
+func (x *Sp80022TestConfig) GetBitOrder() Sp80022BitOrder {
+	if x != nil {
+		return x.BitOrder
+	}
+	return Sp80022BitOrder_SP80022_BIT_ORDER_UNSPECIFIED
+}
+
+func (x *Sp80022TestConfig) GetBinaryMatrixRows() int32 {
+	if x != nil {
+		return x.BinaryMatrixRows
+	}
+	return 0
+}
+
+func (x *Sp80022TestConfig) GetBinaryMatrixColumns() int32 {
+	if x != nil {
+		return x.BinaryMatrixColumns
+	}
+	return 0
+}
+
 // Sp80022TestResponse contains results from all 15 NIST SP 800-22 tests
 type Sp80022TestResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -174,7 +647,9 @@ type Sp80022TestResponse struct {
 	Timestamp string `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
 	// Number of bits in the input sample
 	SampleSizeBits int32 `protobuf:"varint,2,opt,name=sample_size_bits,json=sampleSizeBits,proto3" json:"sample_size_bits,omitempty"`
-	// Overall pass rate (0.0 - 1.0) - ONLY for implemented tests
+	// Overall pass rate (0.0 - 1.0) over the tests that ran (PASSED or FAILED). A
+	// multi-valued test counts once, by the status of its combined p_value, so each
+	// test fails random data with probability close to 0.01.
 	OverallPassRate float64 `protobuf:"fixed64,3,opt,name=overall_pass_rate,json=overallPassRate,proto3" json:"overall_pass_rate,omitempty"`
 	// P-value uniformity chi-squared test result
 	PValueUniformityChi2 float64 `protobuf:"fixed64,4,opt,name=p_value_uniformity_chi2,json=pValueUniformityChi2,proto3" json:"p_value_uniformity_chi2,omitempty"`
@@ -182,21 +657,24 @@ type Sp80022TestResponse struct {
 	Results []*Sp80022TestResult `protobuf:"bytes,5,rep,name=results,proto3" json:"results,omitempty"`
 	// Total execution time in milliseconds
 	ExecutionTimeMs int64 `protobuf:"varint,6,opt,name=execution_time_ms,json=executionTimeMs,proto3" json:"execution_time_ms,omitempty"`
-	// Number of tests actually executed (not skipped)
+	// Number of tests that produced a p-value (status PASSED or FAILED)
 	TestsRun int32 `protobuf:"varint,7,opt,name=tests_run,json=testsRun,proto3" json:"tests_run,omitempty"`
-	// Number of tests not yet implemented
+	// Number of tests that produced no p-value (status NOT_APPLICABLE or ERROR)
 	TestsSkipped int32 `protobuf:"varint,8,opt,name=tests_skipped,json=testsSkipped,proto3" json:"tests_skipped,omitempty"`
 	// Always 15 for NIST SP 800-22
 	TestsTotal int32 `protobuf:"varint,9,opt,name=tests_total,json=testsTotal,proto3" json:"tests_total,omitempty"`
-	// true only if tests_run == tests_total (full NIST SP 800-22 compliance)
+	// true only if tests_run == tests_total (full NIST SP 800-22 compliance): every
+	// test produced a p-value. It does not say whether they passed.
 	NistCompliant bool `protobuf:"varint,10,opt,name=nist_compliant,json=nistCompliant,proto3" json:"nist_compliant,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	// Effective test parameters used for this run (defaults filled in)
+	EffectiveConfig *Sp80022TestConfig `protobuf:"bytes,11,opt,name=effective_config,json=effectiveConfig,proto3" json:"effective_config,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *Sp80022TestResponse) Reset() {
 	*x = Sp80022TestResponse{}
-	mi := &file_nist_sp800_22_proto_msgTypes[2]
+	mi := &file_nist_sp800_22_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -208,7 +686,7 @@ func (x *Sp80022TestResponse) String() string {
 func (*Sp80022TestResponse) ProtoMessage() {}
 
 func (x *Sp80022TestResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_nist_sp800_22_proto_msgTypes[2]
+	mi := &file_nist_sp800_22_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -221,7 +699,7 @@ func (x *Sp80022TestResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Sp80022TestResponse.ProtoReflect.Descriptor instead.
 func (*Sp80022TestResponse) Descriptor() ([]byte, []int) {
-	return file_nist_sp800_22_proto_rawDescGZIP(), []int{2}
+	return file_nist_sp800_22_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *Sp80022TestResponse) GetTimestamp() string {
@@ -294,6 +772,13 @@ func (x *Sp80022TestResponse) GetNistCompliant() bool {
 	return false
 }
 
+func (x *Sp80022TestResponse) GetEffectiveConfig() *Sp80022TestConfig {
+	if x != nil {
+		return x.EffectiveConfig
+	}
+	return nil
+}
+
 // Sp80022TestResult represents the outcome of a single NIST SP 800-22 statistical test
 type Sp80022TestResult struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -301,19 +786,33 @@ type Sp80022TestResult struct {
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	// P-value from the test (0.0 - 1.0)
 	PValue float64 `protobuf:"fixed64,2,opt,name=p_value,json=pValue,proto3" json:"p_value,omitempty"`
-	// Whether the test passed (p_value > 0.01)
+	// Whether the test passed (status == PASSED)
 	Passed bool `protobuf:"varint,3,opt,name=passed,proto3" json:"passed,omitempty"`
-	// Proportion metric (for multi-run tests, optional)
+	// Deprecated and never set: a single sequence has no proportion. The
+	// proportion of passing sequences (NIST SP 800-22 section 4.2) is reported
+	// per statistic in Sp80022SequenceAnalysis by RunMultiSequenceAnalysis.
+	//
+	// Deprecated: Marked as deprecated in nist_sp800_22.proto.
 	Proportion *float64 `protobuf:"fixed64,4,opt,name=proportion,proto3,oneof" json:"proportion,omitempty"`
-	// Warning message if test couldn't complete normally
-	Warning       *string `protobuf:"bytes,5,opt,name=warning,proto3,oneof" json:"warning,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	// Reason the test couldn't complete normally (NOT_APPLICABLE or ERROR)
+	Warning *string `protobuf:"bytes,5,opt,name=warning,proto3,oneof" json:"warning,omitempty"`
+	// Individual statistics of multi-valued tests (e.g. one entry per template
+	// of the non-overlapping template test). p_value above is their
+	// Sidak-corrected minimum 1 - (1 - p_min)^k over the k entries. The linear
+	// complexity test has a single entry "blocks" with its per-block details.
+	SubResults []*Sp80022SubResult `protobuf:"bytes,6,rep,name=sub_results,json=subResults,proto3" json:"sub_results,omitempty"`
+	// Outcome category. NOT_APPLICABLE and ERROR results carry no p-value and
+	// are excluded from overall_pass_rate; warning holds the reason.
+	Status Sp80022TestStatus `protobuf:"varint,8,opt,name=status,proto3,enum=nist.sp800_22.v1.Sp80022TestStatus" json:"status,omitempty"`
+	// Wall time spent in this test, in (fractional) milliseconds
+	ExecutionTimeMs float64 `protobuf:"fixed64,9,opt,name=execution_time_ms,json=executionTimeMs,proto3" json:"execution_time_ms,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *Sp80022TestResult) Reset() {
 	*x = Sp80022TestResult{}
-	mi := &file_nist_sp800_22_proto_msgTypes[3]
+	mi := &file_nist_sp800_22_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -325,7 +824,7 @@ func (x *Sp80022TestResult) String() string {
 func (*Sp80022TestResult) ProtoMessage() {}
 
 func (x *Sp80022TestResult) ProtoReflect() protoreflect.Message {
-	mi := &file_nist_sp800_22_proto_msgTypes[3]
+	mi := &file_nist_sp800_22_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -338,7 +837,7 @@ func (x *Sp80022TestResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Sp80022TestResult.ProtoReflect.Descriptor instead.
 func (*Sp80022TestResult) Descriptor() ([]byte, []int) {
-	return file_nist_sp800_22_proto_rawDescGZIP(), []int{3}
+	return file_nist_sp800_22_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *Sp80022TestResult) GetName() string {
@@ -362,6 +861,7 @@ func (x *Sp80022TestResult) GetPassed() bool {
 	return false
 }
 
+// Deprecated: Marked as deprecated in nist_sp800_22.proto.
 func (x *Sp80022TestResult) GetProportion() float64 {
 	if x != nil && x.Proportion != nil {
 		return *x.Proportion
@@ -376,48 +876,730 @@ func (x *Sp80022TestResult) GetWarning() string {
 	return ""
 }
 
-var File_nist_sp800_22_proto protoreflect.FileDescriptor
+func (x *Sp80022TestResult) GetSubResults() []*Sp80022SubResult {
+	if x != nil {
+		return x.SubResults
+	}
+	return nil
+}
 
-const file_nist_sp800_22_proto_rawDesc = "" +
-	"\n" +
-	"\x13nist_sp800_22.proto\x12\x10nist.sp800_22.v1\"\x7f\n" +
-	"\x12Sp80022TestRequest\x12\x1c\n" +
-	"\tbitstream\x18\x01 \x01(\fR\tbitstream\x12@\n" +
-	"\x06config\x18\x02 \x01(\v2#.nist.sp800_22.v1.Sp80022TestConfigH\x00R\x06config\x88\x01\x01B\t\n" +
-	"\a_config\"\xb5\x03\n" +
-	"\x11Sp80022TestConfig\x12?\n" +
-	"\x1cblock_frequency_block_length\x18\x01 \x01(\x05R\x19blockFrequencyBlockLength\x12P\n" +
-	"%non_overlapping_template_block_length\x18\x02 \x01(\x05R!nonOverlappingTemplateBlockLength\x12I\n" +
-	"!overlapping_template_block_length\x18\x03 \x01(\x05R\x1eoverlappingTemplateBlockLength\x12G\n" +
-	" approximate_entropy_block_length\x18\x04 \x01(\x05R\x1dapproximateEntropyBlockLength\x12.\n" +
-	"\x13serial_block_length\x18\x05 \x01(\x05R\x11serialBlockLength\x12I\n" +
-	"!linear_complexity_sequence_length\x18\x06 \x01(\x05R\x1elinearComplexitySequenceLength\"\xb5\x03\n" +
-	"\x13Sp80022TestResponse\x12\x1c\n" +
-	"\ttimestamp\x18\x01 \x01(\tR\ttimestamp\x12(\n" +
-	"\x10sample_size_bits\x18\x02 \x01(\x05R\x0esampleSizeBits\x12*\n" +
-	"\x11overall_pass_rate\x18\x03 \x01(\x01R\x0foverallPassRate\x125\n" +
-	"\x17p_value_uniformity_chi2\x18\x04 \x01(\x01R\x14pValueUniformityChi2\x12=\n" +
-	"\aresults\x18\x05 \x03(\v2#.nist.sp800_22.v1.Sp80022TestResultR\aresults\x12*\n" +
-	"\x11execution_time_ms\x18\x06 \x01(\x03R\x0fexecutionTimeMs\x12\x1b\n" +
-	"\ttests_run\x18\a \x01(\x05R\btestsRun\x12#\n" +
+func (x *Sp80022TestResult) GetStatus() Sp80022TestStatus {
+	if x != nil {
+		return x.Status
+	}
+	return Sp80022TestStatus_SP80022_TEST_STATUS_UNSPECIFIED
+}
+
+func (x *Sp80022TestResult) GetExecutionTimeMs() float64 {
+	if x != nil {
+		return x.ExecutionTimeMs
+	}
+	return 0
+}
+
+// Sp80022SubResult is one of several statistics reported by a multi-valued test
+type Sp80022SubResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Sub-test identifier (e.g. template bit pattern "000000001", state "x=-4",
+	// "forward"/"reverse" for cumulative sums or "p_value1"/"p_value2" for serial)
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// P-value of this statistic (0.0 - 1.0)
+	PValue float64 `protobuf:"fixed64,2,opt,name=p_value,json=pValue,proto3" json:"p_value,omitempty"`
+	// Whether this statistic passed (p_value >= 0.01)
+	Passed bool `protobuf:"varint,3,opt,name=passed,proto3" json:"passed,omitempty"`
+	// Test statistic (e.g. chi-square)
+	Statistic float64 `protobuf:"fixed64,4,opt,name=statistic,proto3" json:"statistic,omitempty"`
+	// Observed counts (e.g. template matches W_j per block, the nu_k(x) histogram
+	// of the random excursions test, the visit count xi(x) of the variant test, or
+	// the nu_0..nu_6 histogram of T_i of the linear complexity test)
+	Counts []int64 `protobuf:"varint,5,rep,packed,name=counts,proto3" json:"counts,omitempty"`
+	// Random walk state x (random excursions tests only)
+	State *int32 `protobuf:"varint,6,opt,name=state,proto3,oneof" json:"state,omitempty"`
+	// Further statistics (serial test: psi^2_m, psi^2_m-1 and psi^2_m-2; linear
+	// complexity test: L_i of every block)
+	Values        []float64 `protobuf:"fixed64,7,rep,packed,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Sp80022SubResult) Reset() {
+	*x = Sp80022SubResult{}
+	mi := &file_nist_sp800_22_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Sp80022SubResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sp80022SubResult) ProtoMessage() {}
+
+func (x *Sp80022SubResult) ProtoReflect() protoreflect.Message {
+	mi := &file_nist_sp800_22_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sp80022SubResult.ProtoReflect.Descriptor instead.
+func (*Sp80022SubResult) Descriptor() ([]byte, []int) {
+	return file_nist_sp800_22_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Sp80022SubResult) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Sp80022SubResult) GetPValue() float64 {
+	if x != nil {
+		return x.PValue
+	}
+	return 0
+}
+
+func (x *Sp80022SubResult) GetPassed() bool {
+	if x != nil {
+		return x.Passed
+	}
+	return false
+}
+
+func (x *Sp80022SubResult) GetStatistic() float64 {
+	if x != nil {
+		return x.Statistic
+	}
+	return 0
+}
+
+func (x *Sp80022SubResult) GetCounts() []int64 {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
+}
+
+func (x *Sp80022SubResult) GetState() int32 {
+	if x != nil && x.State != nil {
+		return *x.State
+	}
+	return 0
+}
+
+func (x *Sp80022SubResult) GetValues() []float64 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+// Sp80022MultiSequenceRequest contains a bitstream to be split into several sequences
+type Sp80022MultiSequenceRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Raw bitstream as bytes, at least sequence_length_bits * num_sequences bits
+	Bitstream []byte `protobuf:"bytes,1,opt,name=bitstream,proto3" json:"bitstream,omitempty"`
+	// Length of each sequence in bits (multiple of 8, minimum 387,840, maximum MAX_BITS)
+	SequenceLengthBits int32 `protobuf:"varint,2,opt,name=sequence_length_bits,json=sequenceLengthBits,proto3" json:"sequence_length_bits,omitempty"`
+	// Number of sequences to test (0 = as many as fit into the bitstream)
+	NumSequences int32 `protobuf:"varint,3,opt,name=num_sequences,json=numSequences,proto3" json:"num_sequences,omitempty"`
+	// Optional test configuration parameters, applied to every sequence
+	Config        *Sp80022TestConfig `protobuf:"bytes,4,opt,name=config,proto3,oneof" json:"config,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Sp80022MultiSequenceRequest) Reset() {
+	*x = Sp80022MultiSequenceRequest{}
+	mi := &file_nist_sp800_22_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Sp80022MultiSequenceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sp80022MultiSequenceRequest) ProtoMessage() {}
+
+func (x *Sp80022MultiSequenceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nist_sp800_22_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sp80022MultiSequenceRequest.ProtoReflect.Descriptor instead.
+func (*Sp80022MultiSequenceRequest) Descriptor() ([]byte, []int) {
+	return file_nist_sp800_22_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Sp80022MultiSequenceRequest) GetBitstream() []byte {
+	if x != nil {
+		return x.Bitstream
+	}
+	return nil
+}
+
+func (x *Sp80022MultiSequenceRequest) GetSequenceLengthBits() int32 {
+	if x != nil {
+		return x.SequenceLengthBits
+	}
+	return 0
+}
+
+func (x *Sp80022MultiSequenceRequest) GetNumSequences() int32 {
+	if x != nil {
+		return x.NumSequences
+	}
+	return 0
+}
+
+func (x *Sp80022MultiSequenceRequest) GetConfig() *Sp80022TestConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+// Sp80022MultiSequenceResponse mirrors the NIST STS finalAnalysisReport
+type Sp80022MultiSequenceResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ISO 8601 timestamp when tests were executed
+	Timestamp string `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// Length of each sequence in bits
+	SequenceLengthBits int32 `protobuf:"varint,2,opt,name=sequence_length_bits,json=sequenceLengthBits,proto3" json:"sequence_length_bits,omitempty"`
+	// Number of sequences tested
+	NumSequences int32 `protobuf:"varint,3,opt,name=num_sequences,json=numSequences,proto3" json:"num_sequences,omitempty"`
+	// One entry per statistic (multi-valued tests contribute one entry per sub-result)
+	Results []*Sp80022SequenceAnalysis `protobuf:"bytes,4,rep,name=results,proto3" json:"results,omitempty"`
+	// Total execution time in milliseconds
+	ExecutionTimeMs int64 `protobuf:"varint,5,opt,name=execution_time_ms,json=executionTimeMs,proto3" json:"execution_time_ms,omitempty"`
+	// Effective test parameters used for this run (defaults filled in)
+	EffectiveConfig *Sp80022TestConfig `protobuf:"bytes,6,opt,name=effective_config,json=effectiveConfig,proto3" json:"effective_config,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Sp80022MultiSequenceResponse) Reset() {
+	*x = Sp80022MultiSequenceResponse{}
+	mi := &file_nist_sp800_22_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Sp80022MultiSequenceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sp80022MultiSequenceResponse) ProtoMessage() {}
+
+func (x *Sp80022MultiSequenceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_nist_sp800_22_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sp80022MultiSequenceResponse.ProtoReflect.Descriptor instead.
+func (*Sp80022MultiSequenceResponse) Descriptor() ([]byte, []int) {
+	return file_nist_sp800_22_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Sp80022MultiSequenceResponse) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *Sp80022MultiSequenceResponse) GetSequenceLengthBits() int32 {
+	if x != nil {
+		return x.SequenceLengthBits
+	}
+	return 0
+}
+
+func (x *Sp80022MultiSequenceResponse) GetNumSequences() int32 {
+	if x != nil {
+		return x.NumSequences
+	}
+	return 0
+}
+
+func (x *Sp80022MultiSequenceResponse) GetResults() []*Sp80022SequenceAnalysis {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *Sp80022MultiSequenceResponse) GetExecutionTimeMs() int64 {
+	if x != nil {
+		return x.ExecutionTimeMs
+	}
+	return 0
+}
+
+func (x *Sp80022MultiSequenceResponse) GetEffectiveConfig() *Sp80022TestConfig {
+	if x != nil {
+		return x.EffectiveConfig
+	}
+	return nil
+}
+
+// Sp80022SequenceAnalysis is the second-level analysis of one statistic across all sequences
+type Sp80022SequenceAnalysis struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Test name, suffixed with "/<sub-result>" for multi-valued tests
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// C1..C10: number of p-values falling into each tenth of [0, 1]
+	Histogram []int32 `protobuf:"varint,2,rep,packed,name=histogram,proto3" json:"histogram,omitempty"`
+	// P-value_T of the chi-square uniformity test over the histogram
+	PValueUniformity float64 `protobuf:"fixed64,3,opt,name=p_value_uniformity,json=pValueUniformity,proto3" json:"p_value_uniformity,omitempty"`
+	// Whether p_value_uniformity >= 0.0001
+	UniformityPassed bool `protobuf:"varint,4,opt,name=uniformity_passed,json=uniformityPassed,proto3" json:"uniformity_passed,omitempty"`
+	// Number of sequences passing at alpha = 0.01
+	PassedSequences int32 `protobuf:"varint,5,opt,name=passed_sequences,json=passedSequences,proto3" json:"passed_sequences,omitempty"`
+	// Number of sequences for which the statistic could be computed
+	SampleSize int32 `protobuf:"varint,6,opt,name=sample_size,json=sampleSize,proto3" json:"sample_size,omitempty"`
+	// passed_sequences / sample_size
+	Proportion float64 `protobuf:"fixed64,7,opt,name=proportion,proto3" json:"proportion,omitempty"`
+	// Confidence interval (1-alpha) ± 3*sqrt(alpha*(1-alpha)/sample_size)
+	ProportionLowerBound float64 `protobuf:"fixed64,8,opt,name=proportion_lower_bound,json=proportionLowerBound,proto3" json:"proportion_lower_bound,omitempty"`
+	ProportionUpperBound float64 `protobuf:"fixed64,9,opt,name=proportion_upper_bound,json=proportionUpperBound,proto3" json:"proportion_upper_bound,omitempty"`
+	// Whether proportion lies within the confidence interval
+	ProportionPassed bool `protobuf:"varint,10,opt,name=proportion_passed,json=proportionPassed,proto3" json:"proportion_passed,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Sp80022SequenceAnalysis) Reset() {
+	*x = Sp80022SequenceAnalysis{}
+	mi := &file_nist_sp800_22_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Sp80022SequenceAnalysis) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sp80022SequenceAnalysis) ProtoMessage() {}
+
+func (x *Sp80022SequenceAnalysis) ProtoReflect() protoreflect.Message {
+	mi := &file_nist_sp800_22_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sp80022SequenceAnalysis.ProtoReflect.Descriptor instead.
+func (*Sp80022SequenceAnalysis) Descriptor() ([]byte, []int) {
+	return file_nist_sp800_22_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Sp80022SequenceAnalysis) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Sp80022SequenceAnalysis) GetHistogram() []int32 {
+	if x != nil {
+		return x.Histogram
+	}
+	return nil
+}
+
+func (x *Sp80022SequenceAnalysis) GetPValueUniformity() float64 {
+	if x != nil {
+		return x.PValueUniformity
+	}
+	return 0
+}
+
+func (x *Sp80022SequenceAnalysis) GetUniformityPassed() bool {
+	if x != nil {
+		return x.UniformityPassed
+	}
+	return false
+}
+
+func (x *Sp80022SequenceAnalysis) GetPassedSequences() int32 {
+	if x != nil {
+		return x.PassedSequences
+	}
+	return 0
+}
+
+func (x *Sp80022SequenceAnalysis) GetSampleSize() int32 {
+	if x != nil {
+		return x.SampleSize
+	}
+	return 0
+}
+
+func (x *Sp80022SequenceAnalysis) GetProportion() float64 {
+	if x != nil {
+		return x.Proportion
+	}
+	return 0
+}
+
+func (x *Sp80022SequenceAnalysis) GetProportionLowerBound() float64 {
+	if x != nil {
+		return x.ProportionLowerBound
+	}
+	return 0
+}
+
+func (x *Sp80022SequenceAnalysis) GetProportionUpperBound() float64 {
+	if x != nil {
+		return x.ProportionUpperBound
+	}
+	return 0
+}
+
+func (x *Sp80022SequenceAnalysis) GetProportionPassed() bool {
+	if x != nil {
+		return x.ProportionPassed
+	}
+	return false
+}
+
+// Sp80022GenerateAndTestRequest selects a generator and the sequences to test
+type Sp80022GenerateAndTestRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Generator to test (required)
+	Generator Sp80022Generator `protobuf:"varint,1,opt,name=generator,proto3,enum=nist.sp800_22.v1.Sp80022Generator" json:"generator,omitempty"`
+	// Length of each sequence in bits (minimum 387,840, maximum MAX_BITS; a multiple of 8
+	// if num_sequences > 1)
+	SequenceLengthBits int32 `protobuf:"varint,2,opt,name=sequence_length_bits,json=sequenceLengthBits,proto3" json:"sequence_length_bits,omitempty"`
+	// Number of consecutive sequences (0 or 1 = one sequence, maximum 1000). A single
+	// sequence is tested like RunTestSuite, several like RunMultiSequenceAnalysis. All
+	// sequences together must fit into the memory of a single run of MAX_BITS bits
+	// (about 40 bytes per bit), e.g. 1000 sequences of 1,000,000 bits, but only one of
+	// MAX_BITS bits.
+	NumSequences int32 `protobuf:"varint,3,opt,name=num_sequences,json=numSequences,proto3" json:"num_sequences,omitempty"`
+	// Optional test configuration parameters; bit_order must be unspecified or MSB_FIRST
+	Config        *Sp80022TestConfig `protobuf:"bytes,4,opt,name=config,proto3,oneof" json:"config,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Sp80022GenerateAndTestRequest) Reset() {
+	*x = Sp80022GenerateAndTestRequest{}
+	mi := &file_nist_sp800_22_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Sp80022GenerateAndTestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sp80022GenerateAndTestRequest) ProtoMessage() {}
+
+func (x *Sp80022GenerateAndTestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nist_sp800_22_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sp80022GenerateAndTestRequest.ProtoReflect.Descriptor instead.
+func (*Sp80022GenerateAndTestRequest) Descriptor() ([]byte, []int) {
+	return file_nist_sp800_22_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Sp80022GenerateAndTestRequest) GetGenerator() Sp80022Generator {
+	if x != nil {
+		return x.Generator
+	}
+	return Sp80022Generator_SP80022_GENERATOR_UNSPECIFIED
+}
+
+func (x *Sp80022GenerateAndTestRequest) GetSequenceLengthBits() int32 {
+	if x != nil {
+		return x.SequenceLengthBits
+	}
+	return 0
+}
+
+func (x *Sp80022GenerateAndTestRequest) GetNumSequences() int32 {
+	if x != nil {
+		return x.NumSequences
+	}
+	return 0
+}
+
+func (x *Sp80022GenerateAndTestRequest) GetConfig() *Sp80022TestConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+// Sp80022GenerateAndTestResponse holds the results in the form matching num_sequences
+type Sp80022GenerateAndTestResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Result:
+	//
+	//	*Sp80022GenerateAndTestResponse_TestSuite
+	//	*Sp80022GenerateAndTestResponse_MultiSequence
+	Result        isSp80022GenerateAndTestResponse_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Sp80022GenerateAndTestResponse) Reset() {
+	*x = Sp80022GenerateAndTestResponse{}
+	mi := &file_nist_sp800_22_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Sp80022GenerateAndTestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sp80022GenerateAndTestResponse) ProtoMessage() {}
+
+func (x *Sp80022GenerateAndTestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_nist_sp800_22_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sp80022GenerateAndTestResponse.ProtoReflect.Descriptor instead.
+func (*Sp80022GenerateAndTestResponse) Descriptor() ([]byte, []int) {
+	return file_nist_sp800_22_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Sp80022GenerateAndTestResponse) GetResult() isSp80022GenerateAndTestResponse_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *Sp80022GenerateAndTestResponse) GetTestSuite() *Sp80022TestResponse {
+	if x != nil {
+		if x, ok := x.Result.(*Sp80022GenerateAndTestResponse_TestSuite); ok {
+			return x.TestSuite
+		}
+	}
+	return nil
+}
+
+func (x *Sp80022GenerateAndTestResponse) GetMultiSequence() *Sp80022MultiSequenceResponse {
+	if x != nil {
+		if x, ok := x.Result.(*Sp80022GenerateAndTestResponse_MultiSequence); ok {
+			return x.MultiSequence
+		}
+	}
+	return nil
+}
+
+type isSp80022GenerateAndTestResponse_Result interface {
+	isSp80022GenerateAndTestResponse_Result()
+}
+
+type Sp80022GenerateAndTestResponse_TestSuite struct {
+	// Results of a single sequence
+	TestSuite *Sp80022TestResponse `protobuf:"bytes,1,opt,name=test_suite,json=testSuite,proto3,oneof"`
+}
+
+type Sp80022GenerateAndTestResponse_MultiSequence struct {
+	// Analysis of several sequences
+	MultiSequence *Sp80022MultiSequenceResponse `protobuf:"bytes,2,opt,name=multi_sequence,json=multiSequence,proto3,oneof"`
+}
+
+func (*Sp80022GenerateAndTestResponse_TestSuite) isSp80022GenerateAndTestResponse_Result() {}
+
+func (*Sp80022GenerateAndTestResponse_MultiSequence) isSp80022GenerateAndTestResponse_Result() {}
+
+var File_nist_sp800_22_proto protoreflect.FileDescriptor
+
+const file_nist_sp800_22_proto_rawDesc = "" +
+	"\n" +
+	"\x13nist_sp800_22.proto\x12\x10nist.sp800_22.v1\"\xfa\x01\n" +
+	"\x12Sp80022TestRequest\x12\x1c\n" +
+	"\tbitstream\x18\x01 \x01(\fR\tbitstream\x12@\n" +
+	"\x06config\x18\x02 \x01(\v2#.nist.sp800_22.v1.Sp80022TestConfigH\x00R\x06config\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"bit_length\x18\x03 \x01(\x03H\x01R\tbitLength\x88\x01\x01\x12F\n" +
+	"\bencoding\x18\x04 \x01(\x0e2*.nist.sp800_22.v1.Sp80022BitstreamEncodingR\bencodingB\t\n" +
+	"\a_configB\r\n" +
+	"\v_bit_length\"\x82\x01\n" +
+	"\x18Sp80022TestStreamRequest\x12C\n" +
+	"\x06header\x18\x01 \x01(\v2).nist.sp800_22.v1.Sp80022TestStreamHeaderH\x00R\x06header\x12\x16\n" +
+	"\x05chunk\x18\x02 \x01(\fH\x00R\x05chunkB\t\n" +
+	"\apayload\"\xba\x01\n" +
+	"\x17Sp80022TestStreamHeader\x12@\n" +
+	"\x06config\x18\x01 \x01(\v2#.nist.sp800_22.v1.Sp80022TestConfigH\x00R\x06config\x88\x01\x01\x12\x1f\n" +
+	"\vtotal_bytes\x18\x02 \x01(\x03R\n" +
+	"totalBytes\x12\"\n" +
+	"\n" +
+	"bit_length\x18\x03 \x01(\x03H\x01R\tbitLength\x88\x01\x01B\t\n" +
+	"\a_configB\r\n" +
+	"\v_bit_length\"\xa5\x05\n" +
+	"\x11Sp80022TestConfig\x12?\n" +
+	"\x1cblock_frequency_block_length\x18\x01 \x01(\x05R\x19blockFrequencyBlockLength\x12P\n" +
+	"%non_overlapping_template_block_length\x18\x02 \x01(\x05R!nonOverlappingTemplateBlockLength\x12I\n" +
+	"!overlapping_template_block_length\x18\x03 \x01(\x05R\x1eoverlappingTemplateBlockLength\x12G\n" +
+	" approximate_entropy_block_length\x18\x04 \x01(\x05R\x1dapproximateEntropyBlockLength\x12.\n" +
+	"\x13serial_block_length\x18\x05 \x01(\x05R\x11serialBlockLength\x12I\n" +
+	"!linear_complexity_sequence_length\x18\x06 \x01(\x05R\x1elinearComplexitySequenceLength\x12L\n" +
+	"#non_overlapping_template_num_blocks\x18\a \x01(\x05R\x1fnonOverlappingTemplateNumBlocks\x12>\n" +
+	"\tbit_order\x18\b \x01(\x0e2!.nist.sp800_22.v1.Sp80022BitOrderR\bbitOrder\x12,\n" +
+	"\x12binary_matrix_rows\x18\t \x01(\x05R\x10binaryMatrixRows\x122\n" +
+	"\x15binary_matrix_columns\x18\n" +
+	" \x01(\x05R\x13binaryMatrixColumns\"\x85\x04\n" +
+	"\x13Sp80022TestResponse\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\tR\ttimestamp\x12(\n" +
+	"\x10sample_size_bits\x18\x02 \x01(\x05R\x0esampleSizeBits\x12*\n" +
+	"\x11overall_pass_rate\x18\x03 \x01(\x01R\x0foverallPassRate\x125\n" +
+	"\x17p_value_uniformity_chi2\x18\x04 \x01(\x01R\x14pValueUniformityChi2\x12=\n" +
+	"\aresults\x18\x05 \x03(\v2#.nist.sp800_22.v1.Sp80022TestResultR\aresults\x12*\n" +
+	"\x11execution_time_ms\x18\x06 \x01(\x03R\x0fexecutionTimeMs\x12\x1b\n" +
+	"\ttests_run\x18\a \x01(\x05R\btestsRun\x12#\n" +
 	"\rtests_skipped\x18\b \x01(\x05R\ftestsSkipped\x12\x1f\n" +
 	"\vtests_total\x18\t \x01(\x05R\n" +
 	"testsTotal\x12%\n" +
 	"\x0enist_compliant\x18\n" +
-	" \x01(\bR\rnistCompliant\"\xb7\x01\n" +
+	" \x01(\bR\rnistCompliant\x12N\n" +
+	"\x10effective_config\x18\v \x01(\v2#.nist.sp800_22.v1.Sp80022TestConfigR\x0feffectiveConfig\"\xff\x02\n" +
 	"\x11Sp80022TestResult\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x17\n" +
 	"\ap_value\x18\x02 \x01(\x01R\x06pValue\x12\x16\n" +
-	"\x06passed\x18\x03 \x01(\bR\x06passed\x12#\n" +
+	"\x06passed\x18\x03 \x01(\bR\x06passed\x12'\n" +
 	"\n" +
-	"proportion\x18\x04 \x01(\x01H\x00R\n" +
+	"proportion\x18\x04 \x01(\x01B\x02\x18\x01H\x00R\n" +
 	"proportion\x88\x01\x01\x12\x1d\n" +
-	"\awarning\x18\x05 \x01(\tH\x01R\awarning\x88\x01\x01B\r\n" +
+	"\awarning\x18\x05 \x01(\tH\x01R\awarning\x88\x01\x01\x12C\n" +
+	"\vsub_results\x18\x06 \x03(\v2\".nist.sp800_22.v1.Sp80022SubResultR\n" +
+	"subResults\x12;\n" +
+	"\x06status\x18\b \x01(\x0e2#.nist.sp800_22.v1.Sp80022TestStatusR\x06status\x12*\n" +
+	"\x11execution_time_ms\x18\t \x01(\x01R\x0fexecutionTimeMsB\r\n" +
 	"\v_proportionB\n" +
 	"\n" +
-	"\b_warning2q\n" +
+	"\b_warningJ\x04\b\a\x10\bR\x0enot_applicable\"\xca\x01\n" +
+	"\x10Sp80022SubResult\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x17\n" +
+	"\ap_value\x18\x02 \x01(\x01R\x06pValue\x12\x16\n" +
+	"\x06passed\x18\x03 \x01(\bR\x06passed\x12\x1c\n" +
+	"\tstatistic\x18\x04 \x01(\x01R\tstatistic\x12\x16\n" +
+	"\x06counts\x18\x05 \x03(\x03R\x06counts\x12\x19\n" +
+	"\x05state\x18\x06 \x01(\x05H\x00R\x05state\x88\x01\x01\x12\x16\n" +
+	"\x06values\x18\a \x03(\x01R\x06valuesB\b\n" +
+	"\x06_state\"\xdf\x01\n" +
+	"\x1bSp80022MultiSequenceRequest\x12\x1c\n" +
+	"\tbitstream\x18\x01 \x01(\fR\tbitstream\x120\n" +
+	"\x14sequence_length_bits\x18\x02 \x01(\x05R\x12sequenceLengthBits\x12#\n" +
+	"\rnum_sequences\x18\x03 \x01(\x05R\fnumSequences\x12@\n" +
+	"\x06config\x18\x04 \x01(\v2#.nist.sp800_22.v1.Sp80022TestConfigH\x00R\x06config\x88\x01\x01B\t\n" +
+	"\a_config\"\xd4\x02\n" +
+	"\x1cSp80022MultiSequenceResponse\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\tR\ttimestamp\x120\n" +
+	"\x14sequence_length_bits\x18\x02 \x01(\x05R\x12sequenceLengthBits\x12#\n" +
+	"\rnum_sequences\x18\x03 \x01(\x05R\fnumSequences\x12C\n" +
+	"\aresults\x18\x04 \x03(\v2).nist.sp800_22.v1.Sp80022SequenceAnalysisR\aresults\x12*\n" +
+	"\x11execution_time_ms\x18\x05 \x01(\x03R\x0fexecutionTimeMs\x12N\n" +
+	"\x10effective_config\x18\x06 \x01(\v2#.nist.sp800_22.v1.Sp80022TestConfigR\x0feffectiveConfig\"\xab\x03\n" +
+	"\x17Sp80022SequenceAnalysis\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1c\n" +
+	"\thistogram\x18\x02 \x03(\x05R\thistogram\x12,\n" +
+	"\x12p_value_uniformity\x18\x03 \x01(\x01R\x10pValueUniformity\x12+\n" +
+	"\x11uniformity_passed\x18\x04 \x01(\bR\x10uniformityPassed\x12)\n" +
+	"\x10passed_sequences\x18\x05 \x01(\x05R\x0fpassedSequences\x12\x1f\n" +
+	"\vsample_size\x18\x06 \x01(\x05R\n" +
+	"sampleSize\x12\x1e\n" +
+	"\n" +
+	"proportion\x18\a \x01(\x01R\n" +
+	"proportion\x124\n" +
+	"\x16proportion_lower_bound\x18\b \x01(\x01R\x14proportionLowerBound\x124\n" +
+	"\x16proportion_upper_bound\x18\t \x01(\x01R\x14proportionUpperBound\x12+\n" +
+	"\x11proportion_passed\x18\n" +
+	" \x01(\bR\x10proportionPassed\"\x85\x02\n" +
+	"\x1dSp80022GenerateAndTestRequest\x12@\n" +
+	"\tgenerator\x18\x01 \x01(\x0e2\".nist.sp800_22.v1.Sp80022GeneratorR\tgenerator\x120\n" +
+	"\x14sequence_length_bits\x18\x02 \x01(\x05R\x12sequenceLengthBits\x12#\n" +
+	"\rnum_sequences\x18\x03 \x01(\x05R\fnumSequences\x12@\n" +
+	"\x06config\x18\x04 \x01(\v2#.nist.sp800_22.v1.Sp80022TestConfigH\x00R\x06config\x88\x01\x01B\t\n" +
+	"\a_config\"\xcb\x01\n" +
+	"\x1eSp80022GenerateAndTestResponse\x12F\n" +
+	"\n" +
+	"test_suite\x18\x01 \x01(\v2%.nist.sp800_22.v1.Sp80022TestResponseH\x00R\ttestSuite\x12W\n" +
+	"\x0emulti_sequence\x18\x02 \x01(\v2..nist.sp800_22.v1.Sp80022MultiSequenceResponseH\x00R\rmultiSequenceB\b\n" +
+	"\x06result*\xe5\x01\n" +
+	"\x18Sp80022BitstreamEncoding\x12*\n" +
+	"&SP80022_BITSTREAM_ENCODING_UNSPECIFIED\x10\x00\x12\"\n" +
+	"\x1eSP80022_BITSTREAM_ENCODING_RAW\x10\x01\x12)\n" +
+	"%SP80022_BITSTREAM_ENCODING_ASCII_BITS\x10\x02\x12\"\n" +
+	"\x1eSP80022_BITSTREAM_ENCODING_HEX\x10\x03\x12*\n" +
+	"&SP80022_BITSTREAM_ENCODING_BASE64_TEXT\x10\x04*v\n" +
+	"\x0fSp80022BitOrder\x12!\n" +
+	"\x1dSP80022_BIT_ORDER_UNSPECIFIED\x10\x00\x12\x1f\n" +
+	"\x1bSP80022_BIT_ORDER_MSB_FIRST\x10\x01\x12\x1f\n" +
+	"\x1bSP80022_BIT_ORDER_LSB_FIRST\x10\x02*\xbf\x01\n" +
+	"\x11Sp80022TestStatus\x12#\n" +
+	"\x1fSP80022_TEST_STATUS_UNSPECIFIED\x10\x00\x12\x1e\n" +
+	"\x1aSP80022_TEST_STATUS_PASSED\x10\x01\x12\x1e\n" +
+	"\x1aSP80022_TEST_STATUS_FAILED\x10\x02\x12&\n" +
+	"\"SP80022_TEST_STATUS_NOT_APPLICABLE\x10\x03\x12\x1d\n" +
+	"\x19SP80022_TEST_STATUS_ERROR\x10\x04*\x8d\x03\n" +
+	"\x10Sp80022Generator\x12!\n" +
+	"\x1dSP80022_GENERATOR_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15SP80022_GENERATOR_LCG\x10\x01\x12.\n" +
+	"*SP80022_GENERATOR_QUADRATIC_CONGRUENTIAL_1\x10\x02\x12.\n" +
+	"*SP80022_GENERATOR_QUADRATIC_CONGRUENTIAL_2\x10\x03\x12(\n" +
+	"$SP80022_GENERATOR_CUBIC_CONGRUENTIAL\x10\x04\x12\x19\n" +
+	"\x15SP80022_GENERATOR_XOR\x10\x05\x12,\n" +
+	"(SP80022_GENERATOR_MODULAR_EXPONENTIATION\x10\x06\x12$\n" +
+	" SP80022_GENERATOR_BLUM_BLUM_SHUB\x10\a\x12$\n" +
+	" SP80022_GENERATOR_MICALI_SCHNORR\x10\b\x12\x1c\n" +
+	"\x18SP80022_GENERATOR_G_SHA1\x10\t2\xcd\x03\n" +
 	"\x12Sp80022TestService\x12[\n" +
-	"\fRunTestSuite\x12$.nist.sp800_22.v1.Sp80022TestRequest\x1a%.nist.sp800_22.v1.Sp80022TestResponseBEZCgithub.com/AmmannChristian/nist-sp800-22-rev1a/pkg/pb;nistsp80022v1b\x06proto3"
+	"\fRunTestSuite\x12$.nist.sp800_22.v1.Sp80022TestRequest\x1a%.nist.sp800_22.v1.Sp80022TestResponse\x12y\n" +
+	"\x18RunMultiSequenceAnalysis\x12-.nist.sp800_22.v1.Sp80022MultiSequenceRequest\x1a..nist.sp800_22.v1.Sp80022MultiSequenceResponse\x12i\n" +
+	"\x12RunTestSuiteStream\x12*.nist.sp800_22.v1.Sp80022TestStreamRequest\x1a%.nist.sp800_22.v1.Sp80022TestResponse(\x01\x12t\n" +
+	"\x0fGenerateAndTest\x12/.nist.sp800_22.v1.Sp80022GenerateAndTestRequest\x1a0.nist.sp800_22.v1.Sp80022GenerateAndTestResponseBEZCgithub.com/AmmannChristian/nist-sp800-22-rev1a/pkg/pb;nistsp80022v1b\x06proto3"
 
 var (
 	file_nist_sp800_22_proto_rawDescOnce sync.Once
@@ -431,23 +1613,56 @@ func file_nist_sp800_22_proto_rawDescGZIP() []byte {
 	return file_nist_sp800_22_proto_rawDescData
 }
 
-var file_nist_sp800_22_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_nist_sp800_22_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_nist_sp800_22_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
 var file_nist_sp800_22_proto_goTypes = []any{
-	(*Sp80022TestRequest)(nil),  // 0: nist.sp800_22.v1.Sp80022TestRequest
-	(*Sp80022TestConfig)(nil),   // 1: nist.sp800_22.v1.Sp80022TestConfig
-	(*Sp80022TestResponse)(nil), // 2: nist.sp800_22.v1.Sp80022TestResponse
-	(*Sp80022TestResult)(nil),   // 3: nist.sp800_22.v1.Sp80022TestResult
+	(Sp80022BitstreamEncoding)(0),          // 0: nist.sp800_22.v1.Sp80022BitstreamEncoding
+	(Sp80022BitOrder)(0),                   // 1: nist.sp800_22.v1.Sp80022BitOrder
+	(Sp80022TestStatus)(0),                 // 2: nist.sp800_22.v1.Sp80022TestStatus
+	(Sp80022Generator)(0),                  // 3: nist.sp800_22.v1.Sp80022Generator
+	(*Sp80022TestRequest)(nil),             // 4: nist.sp800_22.v1.Sp80022TestRequest
+	(*Sp80022TestStreamRequest)(nil),       // 5: nist.sp800_22.v1.Sp80022TestStreamRequest
+	(*Sp80022TestStreamHeader)(nil),        // 6: nist.sp800_22.v1.Sp80022TestStreamHeader
+	(*Sp80022TestConfig)(nil),              // 7: nist.sp800_22.v1.Sp80022TestConfig
+	(*Sp80022TestResponse)(nil),            // 8: nist.sp800_22.v1.Sp80022TestResponse
+	(*Sp80022TestResult)(nil),              // 9: nist.sp800_22.v1.Sp80022TestResult
+	(*Sp80022SubResult)(nil),               // 10: nist.sp800_22.v1.Sp80022SubResult
+	(*Sp80022MultiSequenceRequest)(nil),    // 11: nist.sp800_22.v1.Sp80022MultiSequenceRequest
+	(*Sp80022MultiSequenceResponse)(nil),   // 12: nist.sp800_22.v1.Sp80022MultiSequenceResponse
+	(*Sp80022SequenceAnalysis)(nil),        // 13: nist.sp800_22.v1.Sp80022SequenceAnalysis
+	(*Sp80022GenerateAndTestRequest)(nil),  // 14: nist.sp800_22.v1.Sp80022GenerateAndTestRequest
+	(*Sp80022GenerateAndTestResponse)(nil), // 15: nist.sp800_22.v1.Sp80022GenerateAndTestResponse
 }
 var file_nist_sp800_22_proto_depIdxs = []int32{
-	1, // 0: nist.sp800_22.v1.Sp80022TestRequest.config:type_name -> nist.sp800_22.v1.Sp80022TestConfig
-	3, // 1: nist.sp800_22.v1.Sp80022TestResponse.results:type_name -> nist.sp800_22.v1.Sp80022TestResult
-	0, // 2: nist.sp800_22.v1.Sp80022TestService.RunTestSuite:input_type -> nist.sp800_22.v1.Sp80022TestRequest
-	2, // 3: nist.sp800_22.v1.Sp80022TestService.RunTestSuite:output_type -> nist.sp800_22.v1.Sp80022TestResponse
-	3, // [3:4] is the sub-list for method output_type
-	2, // [2:3] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	7,  // 0: nist.sp800_22.v1.Sp80022TestRequest.config:type_name -> nist.sp800_22.v1.Sp80022TestConfig
+	0,  // 1: nist.sp800_22.v1.Sp80022TestRequest.encoding:type_name -> nist.sp800_22.v1.Sp80022BitstreamEncoding
+	6,  // 2: nist.sp800_22.v1.Sp80022TestStreamRequest.header:type_name -> nist.sp800_22.v1.Sp80022TestStreamHeader
+	7,  // 3: nist.sp800_22.v1.Sp80022TestStreamHeader.config:type_name -> nist.sp800_22.v1.Sp80022TestConfig
+	1,  // 4: nist.sp800_22.v1.Sp80022TestConfig.bit_order:type_name -> nist.sp800_22.v1.Sp80022BitOrder
+	9,  // 5: nist.sp800_22.v1.Sp80022TestResponse.results:type_name -> nist.sp800_22.v1.Sp80022TestResult
+	7,  // 6: nist.sp800_22.v1.Sp80022TestResponse.effective_config:type_name -> nist.sp800_22.v1.Sp80022TestConfig
+	10, // 7: nist.sp800_22.v1.Sp80022TestResult.sub_results:type_name -> nist.sp800_22.v1.Sp80022SubResult
+	2,  // 8: nist.sp800_22.v1.Sp80022TestResult.status:type_name -> nist.sp800_22.v1.Sp80022TestStatus
+	7,  // 9: nist.sp800_22.v1.Sp80022MultiSequenceRequest.config:type_name -> nist.sp800_22.v1.Sp80022TestConfig
+	13, // 10: nist.sp800_22.v1.Sp80022MultiSequenceResponse.results:type_name -> nist.sp800_22.v1.Sp80022SequenceAnalysis
+	7,  // 11: nist.sp800_22.v1.Sp80022MultiSequenceResponse.effective_config:type_name -> nist.sp800_22.v1.Sp80022TestConfig
+	3,  // 12: nist.sp800_22.v1.Sp80022GenerateAndTestRequest.generator:type_name -> nist.sp800_22.v1.Sp80022Generator
+	7,  // 13: nist.sp800_22.v1.Sp80022GenerateAndTestRequest.config:type_name -> nist.sp800_22.v1.Sp80022TestConfig
+	8,  // 14: nist.sp800_22.v1.Sp80022GenerateAndTestResponse.test_suite:type_name -> nist.sp800_22.v1.Sp80022TestResponse
+	12, // 15: nist.sp800_22.v1.Sp80022GenerateAndTestResponse.multi_sequence:type_name -> nist.sp800_22.v1.Sp80022MultiSequenceResponse
+	4,  // 16: nist.sp800_22.v1.Sp80022TestService.RunTestSuite:input_type -> nist.sp800_22.v1.Sp80022TestRequest
+	11, // 17: nist.sp800_22.v1.Sp80022TestService.RunMultiSequenceAnalysis:input_type -> nist.sp800_22.v1.Sp80022MultiSequenceRequest
+	5,  // 18: nist.sp800_22.v1.Sp80022TestService.RunTestSuiteStream:input_type -> nist.sp800_22.v1.Sp80022TestStreamRequest
+	14, // 19: nist.sp800_22.v1.Sp80022TestService.GenerateAndTest:input_type -> nist.sp800_22.v1.Sp80022GenerateAndTestRequest
+	8,  // 20: nist.sp800_22.v1.Sp80022TestService.RunTestSuite:output_type -> nist.sp800_22.v1.Sp80022TestResponse
+	12, // 21: nist.sp800_22.v1.Sp80022TestService.RunMultiSequenceAnalysis:output_type -> nist.sp800_22.v1.Sp80022MultiSequenceResponse
+	8,  // 22: nist.sp800_22.v1.Sp80022TestService.RunTestSuiteStream:output_type -> nist.sp800_22.v1.Sp80022TestResponse
+	15, // 23: nist.sp800_22.v1.Sp80022TestService.GenerateAndTest:output_type -> nist.sp800_22.v1.Sp80022GenerateAndTestResponse
+	20, // [20:24] is the sub-list for method output_type
+	16, // [16:20] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
 }
 
 func init() { file_nist_sp800_22_proto_init() }
@@ -456,19 +1671,32 @@ func file_nist_sp800_22_proto_init() {
 		return
 	}
 	file_nist_sp800_22_proto_msgTypes[0].OneofWrappers = []any{}
-	file_nist_sp800_22_proto_msgTypes[3].OneofWrappers = []any{}
+	file_nist_sp800_22_proto_msgTypes[1].OneofWrappers = []any{
+		(*Sp80022TestStreamRequest_Header)(nil),
+		(*Sp80022TestStreamRequest_Chunk)(nil),
+	}
+	file_nist_sp800_22_proto_msgTypes[2].OneofWrappers = []any{}
+	file_nist_sp800_22_proto_msgTypes[5].OneofWrappers = []any{}
+	file_nist_sp800_22_proto_msgTypes[6].OneofWrappers = []any{}
+	file_nist_sp800_22_proto_msgTypes[7].OneofWrappers = []any{}
+	file_nist_sp800_22_proto_msgTypes[10].OneofWrappers = []any{}
+	file_nist_sp800_22_proto_msgTypes[11].OneofWrappers = []any{
+		(*Sp80022GenerateAndTestResponse_TestSuite)(nil),
+		(*Sp80022GenerateAndTestResponse_MultiSequence)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_nist_sp800_22_proto_rawDesc), len(file_nist_sp800_22_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   4,
+			NumEnums:      4,
+			NumMessages:   12,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_nist_sp800_22_proto_goTypes,
 		DependencyIndexes: file_nist_sp800_22_proto_depIdxs,
+		EnumInfos:         file_nist_sp800_22_proto_enumTypes,
 		MessageInfos:      file_nist_sp800_22_proto_msgTypes,
 	}.Build()
 	File_nist_sp800_22_proto = out.File
@@ -19,7 +19,10 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Sp80022TestService_RunTestSuite_FullMethodName = "/nist.sp800_22.v1.Sp80022TestService/RunTestSuite"
+	Sp80022TestService_RunTestSuite_FullMethodName             = "/nist.sp800_22.v1.Sp80022TestService/RunTestSuite"
+	Sp80022TestService_RunMultiSequenceAnalysis_FullMethodName = "/nist.sp800_22.v1.Sp80022TestService/RunMultiSequenceAnalysis"
+	Sp80022TestService_RunTestSuiteStream_FullMethodName       = "/nist.sp800_22.v1.Sp80022TestService/RunTestSuiteStream"
+	Sp80022TestService_GenerateAndTest_FullMethodName          = "/nist.sp800_22.v1.Sp80022TestService/GenerateAndTest"
 )
 
 // Sp80022TestServiceClient is the client API for Sp80022TestService service.
@@ -30,6 +33,20 @@ const (
 type Sp80022TestServiceClient interface {
 	// RunTestSuite executes all 15 NIST SP 800-22 statistical tests on the provided bitstream
 	RunTestSuite(ctx context.Context, in *Sp80022TestRequest, opts ...grpc.CallOption) (*Sp80022TestResponse, error)
+	// RunMultiSequenceAnalysis splits the bitstream into multiple sequences, runs all tests on
+	// each and evaluates proportion and p-value uniformity (NIST SP 800-22 section 4.2)
+	RunMultiSequenceAnalysis(ctx context.Context, in *Sp80022MultiSequenceRequest, opts ...grpc.CallOption) (*Sp80022MultiSequenceResponse, error)
+	// RunTestSuiteStream is like RunTestSuite but receives the bitstream as a sequence of chunks,
+	// so inputs are not bounded by the gRPC message size. The first message must carry the header.
+	// The chunks are raw bytes; the text encodings of RunTestSuite are not accepted. The server
+	// buffers the whole bitstream before testing it and accepts up to STREAM_MAX_BITS bits, by
+	// default 10,000,000 like MAX_BITS. Larger captures, e.g. 100,000,000 bits, need
+	// STREAM_MAX_BITS raised and about 40 bytes of server memory per bit (4 GB).
+	RunTestSuiteStream(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[Sp80022TestStreamRequest, Sp80022TestResponse], error)
+	// GenerateAndTest runs the suite on the output of one of the reference generators of the
+	// NIST STS, seeded as in the STS, so known-good and known-bad results are reproducible
+	// without uploading data
+	GenerateAndTest(ctx context.Context, in *Sp80022GenerateAndTestRequest, opts ...grpc.CallOption) (*Sp80022GenerateAndTestResponse, error)
 }
 
 type sp80022TestServiceClient struct {
@@ -50,6 +67,39 @@ func (c *sp80022TestServiceClient) RunTestSuite(ctx context.Context, in *Sp80022
 	return out, nil
 }
 
+func (c *sp80022TestServiceClient) RunMultiSequenceAnalysis(ctx context.Context, in *Sp80022MultiSequenceRequest, opts ...grpc.CallOption) (*Sp80022MultiSequenceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Sp80022MultiSequenceResponse)
+	err := c.cc.Invoke(ctx, Sp80022TestService_RunMultiSequenceAnalysis_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sp80022TestServiceClient) RunTestSuiteStream(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[Sp80022TestStreamRequest, Sp80022TestResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Sp80022TestService_ServiceDesc.Streams[0], Sp80022TestService_RunTestSuiteStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[Sp80022TestStreamRequest, Sp80022TestResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Sp80022TestService_RunTestSuiteStreamClient = grpc.ClientStreamingClient[Sp80022TestStreamRequest, Sp80022TestResponse]
+
+func (c *sp80022TestServiceClient) GenerateAndTest(ctx context.Context, in *Sp80022GenerateAndTestRequest, opts ...grpc.CallOption) (*Sp80022GenerateAndTestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Sp80022GenerateAndTestResponse)
+	err := c.cc.Invoke(ctx, Sp80022TestService_GenerateAndTest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Sp80022TestServiceServer is the server API for Sp80022TestService service.
 // All implementations must embed UnimplementedSp80022TestServiceServer
 // for forward compatibility.
@@ -58,6 +108,20 @@ func (c *sp80022TestServiceClient) RunTestSuite(ctx context.Context, in *Sp80022
 type Sp80022TestServiceServer interface {
 	// RunTestSuite executes all 15 NIST SP 800-22 statistical tests on the provided bitstream
 	RunTestSuite(context.Context, *Sp80022TestRequest) (*Sp80022TestResponse, error)
+	// RunMultiSequenceAnalysis splits the bitstream into multiple sequences, runs all tests on
+	// each and evaluates proportion and p-value uniformity (NIST SP 800-22 section 4.2)
+	RunMultiSequenceAnalysis(context.Context, *Sp80022MultiSequenceRequest) (*Sp80022MultiSequenceResponse, error)
+	// RunTestSuiteStream is like RunTestSuite but receives the bitstream as a sequence of chunks,
+	// so inputs are not bounded by the gRPC message size. The first message must carry the header.
+	// The chunks are raw bytes; the text encodings of RunTestSuite are not accepted. The server
+	// buffers the whole bitstream before testing it and accepts up to STREAM_MAX_BITS bits, by
+	// default 10,000,000 like MAX_BITS. Larger captures, e.g. 100,000,000 bits, need
+	// STREAM_MAX_BITS raised and about 40 bytes of server memory per bit (4 GB).
+	RunTestSuiteStream(grpc.ClientStreamingServer[Sp80022TestStreamRequest, Sp80022TestResponse]) error
+	// GenerateAndTest runs the suite on the output of one of the reference generators of the
+	// NIST STS, seeded as in the STS, so known-good and known-bad results are reproducible
+	// without uploading data
+	GenerateAndTest(context.Context, *Sp80022GenerateAndTestRequest) (*Sp80022GenerateAndTestResponse, error)
 	mustEmbedUnimplementedSp80022TestServiceServer()
 }
 
@@ -71,6 +135,15 @@ type UnimplementedSp80022TestServiceServer struct{}
 func (UnimplementedSp80022TestServiceServer) RunTestSuite(context.Context, *Sp80022TestRequest) (*Sp80022TestResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method RunTestSuite not implemented")
 }
+func (UnimplementedSp80022TestServiceServer) RunMultiSequenceAnalysis(context.Context, *Sp80022MultiSequenceRequest) (*Sp80022MultiSequenceResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RunMultiSequenceAnalysis not implemented")
+}
+func (UnimplementedSp80022TestServiceServer) RunTestSuiteStream(grpc.ClientStreamingServer[Sp80022TestStreamRequest, Sp80022TestResponse]) error {
+	return status.Error(codes.Unimplemented, "method RunTestSuiteStream not implemented")
+}
+func (UnimplementedSp80022TestServiceServer) GenerateAndTest(context.Context, *Sp80022GenerateAndTestRequest) (*Sp80022GenerateAndTestResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GenerateAndTest not implemented")
+}
 func (UnimplementedSp80022TestServiceServer) mustEmbedUnimplementedSp80022TestServiceServer() {}
 func (UnimplementedSp80022TestServiceServer) testEmbeddedByValue()                            {}
 
@@ -110,6 +183,49 @@ func _Sp80022TestService_RunTestSuite_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Sp80022TestService_RunMultiSequenceAnalysis_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Sp80022MultiSequenceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Sp80022TestServiceServer).RunMultiSequenceAnalysis(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Sp80022TestService_RunMultiSequenceAnalysis_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Sp80022TestServiceServer).RunMultiSequenceAnalysis(ctx, req.(*Sp80022MultiSequenceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sp80022TestService_RunTestSuiteStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(Sp80022TestServiceServer).RunTestSuiteStream(&grpc.GenericServerStream[Sp80022TestStreamRequest, Sp80022TestResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Sp80022TestService_RunTestSuiteStreamServer = grpc.ClientStreamingServer[Sp80022TestStreamRequest, Sp80022TestResponse]
+
+func _Sp80022TestService_GenerateAndTest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Sp80022GenerateAndTestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Sp80022TestServiceServer).GenerateAndTest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Sp80022TestService_GenerateAndTest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Sp80022TestServiceServer).GenerateAndTest(ctx, req.(*Sp80022GenerateAndTestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Sp80022TestService_ServiceDesc is the grpc.ServiceDesc for Sp80022TestService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -121,7 +237,21 @@ var Sp80022TestService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RunTestSuite",
 			Handler:    _Sp80022TestService_RunTestSuite_Handler,
 		},
+		{
+			MethodName: "RunMultiSequenceAnalysis",
+			Handler:    _Sp80022TestService_RunMultiSequenceAnalysis_Handler,
+		},
+		{
+			MethodName: "GenerateAndTest",
+			Handler:    _Sp80022TestService_GenerateAndTest_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "RunTestSuiteStream",
+			Handler:       _Sp80022TestService_RunTestSuiteStream_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "nist_sp800_22.proto",
 }
@@ -9,6 +9,7 @@ BASE_DIR="${ROOT}/build/sts-2_1_2"
 DATASET="data.pi"
 BITCOUNT=1000000
 ENCODING="auto" # auto-detect (ascii 0/1 text vs binary); override with --encoding
+REPORT_DIR=""    # optional: write <dataset>.json and <dataset>.md diff reports here
 
 while [[ $# -gt 0 ]]; do
   case "$1" in
@@ -16,6 +17,7 @@ while [[ $# -gt 0 ]]; do
     --bits) BITCOUNT="$2"; shift 2 ;;
     --sts-dir) BASE_DIR="$2"; shift 2 ;;
     --encoding) ENCODING="$2"; shift 2 ;;
+    --report-dir) REPORT_DIR="$2"; shift 2 ;;
     *) echo "Unknown arg: $1"; exit 1 ;;
   esac
 done
@@ -94,11 +96,18 @@ printf "0\ndata/${DATASET}\n1\n0\n1\n${INPUT_MODE}\n" | ./assess "${BITCOUNT}" >
 echo "Finished NIST reference suite on ${DATASET} (${BITCOUNT} bits)..."
 popd >/dev/null
 
+REPORT_ARGS=()
+if [[ -n "${REPORT_DIR}" ]]; then
+  mkdir -p "${REPORT_DIR}"
+  REPORT_ARGS=(--json-out "${REPORT_DIR}/${DATASET}.json" --markdown-out "${REPORT_DIR}/${DATASET}.md")
+fi
+
 echo "Comparing against Pure Go implementation..."
 go run ./tools/validate_nist_go_vs_c.go \
   --dataset "${DATA_PATH}" \
   --bits "${BITCOUNT}" \
   --encoding "${ENCODING}" \
-  --results "${BUILD_DIR}/experiments/AlgorithmTesting"
+  --results "${BUILD_DIR}/experiments/AlgorithmTesting" \
+  "${REPORT_ARGS[@]}"
 
 echo "Reference run log: /tmp/nist_assess.log"
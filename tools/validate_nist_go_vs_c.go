@@ -1,37 +1,29 @@
 package main
 
 import (
-	"bufio"
-	"encoding/json"
+	"context"
 	"flag"
 	"fmt"
+	"io"
 	"log"
-	"math"
 	"os"
 	"path/filepath"
-	"strings"
 
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/bitstream"
 	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/nist"
+	"github.com/AmmannChristian/nist-sp800-22-rev1a/internal/validation"
 )
 
-type testResult struct {
-	Name     string    `json:"name"`
-	Ref      []float64 `json:"ref"`
-	GoVals   []float64 `json:"go"`
-	Diffs    []float64 `json:"diff"`
-	Skipped  bool      `json:"skipped"`
-	Reason   string    `json:"reason,omitempty"`
-	PassDiff bool      `json:"pass_diff"`
-}
-
 func main() {
 	var (
-		dataset    = flag.String("dataset", "", "Path to binary dataset (NIST data.pi, etc)")
-		bits       = flag.Int("bits", 1000000, "Number of bits to test")
-		resultsDir = flag.String("results", "", "Path to NIST experiments/AlgorithmTesting directory")
-		outJSON    = flag.Bool("json", false, "Print JSON output instead of table")
-		tolerance  = flag.Float64("tolerance", 1e-6, "Absolute tolerance for p-value comparison")
-		encoding   = flag.String("encoding", "ascii", "Input encoding: binary or ascii")
+		dataset     = flag.String("dataset", "", "Path to binary dataset (NIST data.pi, etc)")
+		bits        = flag.Int("bits", 1000000, "Number of bits to test")
+		resultsDir  = flag.String("results", "", "Path to NIST experiments/AlgorithmTesting directory")
+		outJSON     = flag.Bool("json", false, "Print JSON output instead of table")
+		tolerance   = flag.Float64("tolerance", 1e-6, "Absolute tolerance for p-value comparison")
+		encoding    = flag.String("encoding", "ascii", "Input encoding: binary or ascii")
+		jsonOut     = flag.String("json-out", "", "Also write the JSON report to this file")
+		markdownOut = flag.String("markdown-out", "", "Also write a Markdown report to this file")
 	)
 	flag.Parse()
 
@@ -39,260 +31,102 @@ func main() {
 		log.Fatalf("dataset and results are required")
 	}
 
-	rawData, err := os.ReadFile(*dataset)
+	seq, err := readDataset(*dataset, *encoding, *bits)
 	if err != nil {
 		log.Fatalf("read dataset: %v", err)
 	}
-
-	data, err := parseBitstream(rawData, *encoding, *bits)
+	// The zero config selects the STS defaults
+	results, err := nist.RunAllTestsSequence(context.Background(), seq, nist.SuiteConfig{})
 	if err != nil {
-		log.Fatalf("parse bitstream: %v", err)
+		log.Fatalf("run tests: %v", err)
 	}
-
-	tests := []struct {
-		name         string
-		file         string
-		compute      func([]byte) ([]float64, error)
-		transformRef func([]float64) ([]float64, error)
-		skipReason   string
-	}{
-		{"Frequency", "Frequency/results.txt", single(func(b []byte) (float64, bool) { return nist.FrequencyTest(b) }), nil, ""},
-		{"BlockFrequency", "BlockFrequency/results.txt", single(func(b []byte) (float64, bool) { return nist.BlockFrequencyTest(b, 128) }), nil, ""},
-		{"CumulativeSums", "CumulativeSums/results.txt", func(b []byte) ([]float64, error) {
-			p, _ := nist.CumulativeSumsTest(b)
-			return []float64{p}, nil // NIST logs forward+reverse; we compare on min value
-		}, func(vals []float64) ([]float64, error) {
-			if len(vals) == 0 {
-				return nil, fmt.Errorf("no ref values")
-			}
-			return []float64{minValue(vals)}, nil
-		}, ""},
-		{"Runs", "Runs/results.txt", single(func(b []byte) (float64, bool) { return nist.RunsTest(b) }), nil, ""},
-		{"LongestRun", "LongestRun/results.txt", single(func(b []byte) (float64, bool) { return nist.LongestRunOfOnesTest(b) }), nil, ""},
-		{"Rank", "Rank/results.txt", single(func(b []byte) (float64, bool) { return nist.BinaryMatrixRankTest(b) }), nil, ""},
-		{"FFT", "FFT/results.txt", single(func(b []byte) (float64, bool) { return nist.DiscreteFourierTransformTest(b) }), nil, ""},
-		{"OverlappingTemplate", "OverlappingTemplate/results.txt", single(func(b []byte) (float64, bool) { return nist.OverlappingTemplateTest(b, 9) }), nil, ""},
-		{"ApproximateEntropy", "ApproximateEntropy/results.txt", single(func(b []byte) (float64, bool) { return nist.ApproximateEntropyTest(b, 10) }), nil, ""},
-		{"Universal", "Universal/results.txt", single(func(b []byte) (float64, bool) { return nist.UniversalStatisticalTest(b) }), nil, ""},
-		{"LinearComplexity", "LinearComplexity/results.txt", single(func(b []byte) (float64, bool) { return nist.LinearComplexityTest(b, 500) }), nil, ""},
-		{"Serial", "Serial/results.txt", single(func(b []byte) (float64, bool) { return nist.SerialTest(b, 16) }), func(vals []float64) ([]float64, error) {
-			if len(vals) == 0 {
-				return nil, fmt.Errorf("no ref values")
-			}
-			return []float64{minValue(vals)}, nil
-		}, ""},
-		{"NonOverlappingTemplate", "NonOverlappingTemplate/results.txt", single(func(b []byte) (float64, bool) { return nist.NonOverlappingTemplateTest(b, 9) }), func(vals []float64) ([]float64, error) {
-			if len(vals) == 0 {
-				return nil, fmt.Errorf("no ref values")
-			}
-			return []float64{minValue(vals)}, nil
-		}, ""},
-		{"RandomExcursions", "RandomExcursions/results.txt", single(func(b []byte) (float64, bool) { return nist.RandomExcursionsTest(b) }), func(vals []float64) ([]float64, error) {
-			if len(vals) == 0 {
-				return nil, fmt.Errorf("no ref values")
-			}
-			return []float64{minValue(vals)}, nil
-		}, ""},
-		{"RandomExcursionsVariant", "RandomExcursionsVariant/results.txt", single(func(b []byte) (float64, bool) { return nist.RandomExcursionsVariantTest(b) }), func(vals []float64) ([]float64, error) {
-			if len(vals) == 0 {
-				return nil, fmt.Errorf("no ref values")
-			}
-			return []float64{minValue(vals)}, nil
-		}, ""},
+	ref, err := validation.ReadExperiment(*resultsDir)
+	if err != nil {
+		log.Fatalf("read reference results: %v", err)
 	}
+	report := validation.Compare(ref, validation.FromResults(results), *tolerance)
 
-	var results []testResult
-	allPass := true
-
-	for _, tt := range tests {
-		res := testResult{Name: tt.name}
-		if tt.compute == nil {
-			res.Skipped = true
-			res.Reason = tt.skipReason
-			results = append(results, res)
-			continue
-		}
-
-		refPath := filepath.Join(*resultsDir, tt.file)
-		refVals, err := readRef(refPath)
-		if err != nil {
-			res.Skipped = true
-			res.Reason = fmt.Sprintf("read ref: %v", err)
-			results = append(results, res)
-			continue
+	if *jsonOut != "" {
+		if err := writeReport(*jsonOut, report.WriteJSON); err != nil {
+			log.Fatalf("write JSON report: %v", err)
 		}
-
-		if tt.transformRef != nil {
-			refVals, err = tt.transformRef(refVals)
-			if err != nil {
-				res.Skipped = true
-				res.Reason = fmt.Sprintf("transform ref: %v", err)
-				results = append(results, res)
-				continue
-			}
-		}
-
-		goVals, err := tt.compute(data)
-		if err != nil {
-			res.Skipped = true
-			res.Reason = fmt.Sprintf("compute go: %v", err)
-			results = append(results, res)
-			continue
-		}
-
-		res.Ref = refVals
-		res.GoVals = goVals
-		res.Diffs = make([]float64, min(len(refVals), len(goVals)))
-		res.PassDiff = true
-		for i := range res.Diffs {
-			res.Diffs[i] = math.Abs(refVals[i] - goVals[i])
-			if res.Diffs[i] > *tolerance || !validRange(refVals[i]) || !validRange(goVals[i]) {
-				res.PassDiff = false
-			}
+	}
+	if *markdownOut != "" {
+		title := fmt.Sprintf("%s (%d bits)", filepath.Base(*dataset), *bits)
+		if err := writeReport(*markdownOut, func(w io.Writer) error { return report.WriteMarkdown(w, title) }); err != nil {
+			log.Fatalf("write Markdown report: %v", err)
 		}
-		allPass = allPass && res.PassDiff
-		results = append(results, res)
 	}
 
 	if *outJSON {
-		enc := json.NewEncoder(os.Stdout)
-		enc.SetIndent("", "  ")
-		if err := enc.Encode(results); err != nil {
+		if err := report.WriteJSON(os.Stdout); err != nil {
 			log.Fatalf("encode json: %v", err)
 		}
-		return
-	}
-
-	fmt.Printf("Dataset: %s (%d bits) | Reference dir: %s | tolerance: %g\n", *dataset, *bits, *resultsDir, *tolerance)
-	fmt.Println("Test                      Go p-value        Ref p-value       |diff|      Status")
-	fmt.Println("--------------------------------------------------------------------------------")
-	for _, r := range results {
-		if r.Skipped {
-			fmt.Printf("%-24s %-16s %-16s %-12s SKIPPED (%s)\n", r.Name, "-", "-", "-", r.Reason)
-			continue
-		}
-		goStr := fmtVal(r.GoVals)
-		refStr := fmtVal(r.Ref)
-		diffStr := fmtVal(r.Diffs)
-		status := "OK"
-		if !r.PassDiff {
-			status = "MISMATCH"
-		}
-		fmt.Printf("%-24s %-16s %-16s %-12s %s\n", r.Name, goStr, refStr, diffStr, status)
+	} else {
+		printTable(report, *dataset, *bits, *resultsDir)
 	}
-	if !allPass {
+	if !report.Passed {
 		os.Exit(1)
 	}
 }
 
-func single(f func([]byte) (float64, bool)) func([]byte) ([]float64, error) {
-	return func(b []byte) ([]float64, error) {
-		p, _ := f(b)
-		return []float64{p}, nil
-	}
-}
-
-func readRef(path string) ([]float64, error) {
-	f, err := os.Open(path)
+func readDataset(path, encoding string, bits int) (nist.BitSequence, error) {
+	enc, err := bitstream.ParseEncoding(encoding)
 	if err != nil {
-		return nil, err
+		return nist.BitSequence{}, err
 	}
-	defer f.Close()
-	var vals []float64
-	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
-			continue
-		}
-		var v float64
-		if _, err := fmt.Sscanf(line, "%f", &v); err != nil {
-			return nil, fmt.Errorf("parse %q: %w", line, err)
-		}
-		vals = append(vals, v)
+	raw, err := os.ReadFile(path) //nolint:gosec // user-selected dataset
+	if err != nil {
+		return nist.BitSequence{}, err
 	}
-	return vals, scanner.Err()
-}
-
-func fmtVal(vals []float64) string {
-	if len(vals) == 0 {
-		return "-"
+	data, n, err := bitstream.Decode(raw, enc)
+	if err != nil {
+		return nist.BitSequence{}, err
 	}
-	if len(vals) == 1 {
-		return fmt.Sprintf("%.6f", vals[0])
+	if n < bits {
+		return nist.BitSequence{}, fmt.Errorf("dataset too small: found %d bits, need %d", n, bits)
 	}
-	return fmt.Sprintf("%.6f…", vals[0])
+	return nist.NewBitSequence(data, bits)
 }
 
-func min(a, b int) int {
-	if a < b {
-		return a
+func writeReport(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path) //nolint:gosec // user-selected report file
+	if err != nil {
+		return err
 	}
-	return b
-}
-
-func validRange(v float64) bool {
-	return !math.IsNaN(v) && v >= 0 && v <= 1
-}
-
-func minValue(vals []float64) float64 {
-	min := math.Inf(1)
-	for _, v := range vals {
-		if v < min {
-			min = v
-		}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
 	}
-	return min
+	return f.Close()
 }
 
-func parseBitstream(data []byte, encoding string, bits int) ([]byte, error) {
-	if encoding == "binary" {
-		requiredBytes := (bits + 7) / 8
-		if len(data) < requiredBytes {
-			return nil, fmt.Errorf("dataset too small: need %d bytes, have %d", requiredBytes, len(data))
+func printTable(report *validation.Report, dataset string, bits int, resultsDir string) {
+	fmt.Printf("Dataset: %s (%d bits) | Reference dir: %s | tolerance: %g\n", dataset, bits, resultsDir, report.Tolerance)
+	fmt.Println("Test                      Statistics  Max |diff|    Status")
+	fmt.Println("--------------------------------------------------------------------------------")
+	for _, t := range report.Tests {
+		if t.Skipped {
+			fmt.Printf("%-24s %-11s %-13s SKIPPED (%s)\n", t.STSName, "-", "-", t.Reason)
+			continue
 		}
-		return data[:requiredBytes], nil
-	}
-
-	if encoding == "ascii" {
-		var packed []byte
-		var currentByte byte
-		var bitCount int
-		var totalBits int
-
-		for _, b := range data {
-			if totalBits >= bits {
-				break
-			}
-			if b == '0' || b == '1' {
-				val := byte(b - '0')
-				// Pack into currentByte, MSB first
-				// bitCount 0 -> shift 7
-				// bitCount 1 -> shift 6
-				currentByte |= val << (7 - bitCount)
-				bitCount++
-				totalBits++
-
-				if bitCount == 8 {
-					packed = append(packed, currentByte)
-					currentByte = 0
-					bitCount = 0
-				}
+		status := "OK"
+		if !t.Passed {
+			status = "MISMATCH"
+			if t.Reason != "" {
+				status += " (" + t.Reason + ")"
 			}
-			// Ignore whitespace and other characters
-		}
-
-		if totalBits < bits {
-			return nil, fmt.Errorf("dataset too small: found %d bits, need %d", totalBits, bits)
 		}
-
-		// Append last partial byte if needed
-		if bitCount > 0 {
-			packed = append(packed, currentByte)
+		fmt.Printf("%-24s %-11d %-13.6g %s\n", t.STSName, len(t.Statistics), t.MaxDiff, status)
+		for _, s := range t.Mismatches() {
+			fmt.Printf("    sequence %d %s: ref %s go %s %s\n", s.Sequence, s.Name, formatValue(s.Reference), formatValue(s.Go), s.Note)
 		}
-
-		return packed, nil
 	}
+}
 
-	return nil, fmt.Errorf("unknown encoding: %s", encoding)
+func formatValue(p *float64) string {
+	if p == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.6f", *p)
 }